@@ -0,0 +1,425 @@
+package api
+
+import (
+	"errors"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// bioPagePrefix marks a bio page's slug in the root path, as in /@username
+	bioPagePrefix = "@"
+	// bioPageLinkKey names the query parameter carrying the link a visitor picked on a bio page
+	bioPageLinkKey = "link"
+
+	bioThemeLight      = "light"
+	defaultAccentColor = "#1a56db"
+)
+
+var ErrBioPageNotOwned = errors.New("bio page does not belong to this user")
+
+type bioPageParams struct {
+	// Slug defaults to the username when a page is created and is kept as it is when left out of an update
+	Slug        string `json:"slug" binding:"omitempty,alphanum,max=50"`
+	Title       string `json:"title" binding:"max=100"`
+	Description string `json:"description" binding:"max=500"`
+	AvatarURL   string `json:"avatar_url" binding:"omitempty,http_url,max=2048"`
+	Theme       string `json:"theme" binding:"omitempty,oneof=light dark"`
+	AccentColor string `json:"accent_color" binding:"omitempty,hexcolor"`
+}
+
+type bioPageLinkParams struct {
+	LinkID  int64  `json:"link_id" binding:"required,min=1"`
+	Label   string `json:"label" binding:"max=100"`
+	IconURL string `json:"icon_url" binding:"omitempty,http_url,max=2048"`
+}
+
+type setBioPageLinksParams struct {
+	Links []bioPageLinkParams `json:"links" binding:"required,max=100,unique=LinkID,dive"`
+}
+
+type bioPageStatsParams struct {
+	ClickedAfter  time.Time `form:"clicked_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	ClickedBefore time.Time `form:"clicked_before" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type bioPageSlugParams struct {
+	Slug   string `form:"-" binding:"required,alphanum,max=50"`
+	LinkID int64  `form:"link" binding:"omitempty,min=1"`
+}
+
+type bioPageResponse struct {
+	db.BioPage
+	URL   string                  `json:"url"`
+	Links []db.GetBioPageLinksRow `json:"links,omitempty"`
+}
+
+type bioPageLinkStats struct {
+	LinkID int64 `json:"link_id"`
+	Clicks int64 `json:"clicks"`
+}
+
+type bioPageStatsResponse struct {
+	BioPageID int64              `json:"bio_page_id"`
+	Clicks    int64              `json:"clicks"`
+	Links     []bioPageLinkStats `json:"links"`
+}
+
+type bioPageView struct {
+	Title       string
+	Description string
+	AvatarURL   string
+	Theme       string
+	AccentColor string
+	Links       []bioPageViewLink
+}
+
+type bioPageViewLink struct {
+	Label   string
+	IconURL string
+	URL     string
+}
+
+func (server *Server) newBioPageResponse(ctx *gin.Context, page db.BioPage, links []db.GetBioPageLinksRow) bioPageResponse {
+	return bioPageResponse{
+		BioPage: page,
+		URL:     server.shortURL(ctx, bioPagePrefix+page.Slug),
+		Links:   links,
+	}
+}
+
+func (server *Server) CreateBioPage(ctx *gin.Context) {
+	var req bioPageParams
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	user := ctx.MustGet(authorizedUserKey).(db.User)
+
+	if req.Slug == "" {
+		req.Slug = user.Username
+	}
+
+	arg := db.CreateBioPageParams{
+		UserID:      user.ID,
+		Slug:        strings.ToLower(req.Slug),
+		Title:       req.Title,
+		Description: req.Description,
+		AvatarUrl:   req.AvatarURL,
+		Theme:       firstNonEmpty(req.Theme, bioThemeLight),
+		AccentColor: strings.ToLower(firstNonEmpty(req.AccentColor, defaultAccentColor)),
+	}
+
+	page, err := server.store.CreateBioPage(ctx, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, successResponse(server.newBioPageResponse(ctx, page, nil), http.StatusCreated))
+}
+
+func (server *Server) GetBioPages(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	pages, err := server.store.GetBioPagesByUser(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	rsp := make([]bioPageResponse, len(pages))
+	for i, page := range pages {
+		rsp[i] = server.newBioPageResponse(ctx, page, nil)
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp, http.StatusOK))
+}
+
+func (server *Server) GetBioPage(ctx *gin.Context) {
+	page, ok := server.ownedBioPage(ctx)
+	if !ok {
+		return
+	}
+
+	links, err := server.store.GetBioPageLinks(ctx, page.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(server.newBioPageResponse(ctx, page, links), http.StatusOK))
+}
+
+func (server *Server) UpdateBioPage(ctx *gin.Context) {
+	var req bioPageParams
+
+	page, ok := server.ownedBioPage(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	if req.Slug == "" {
+		req.Slug = page.Slug
+	}
+
+	arg := db.UpdateBioPageParams{
+		ID:          page.ID,
+		Slug:        strings.ToLower(req.Slug),
+		Title:       req.Title,
+		Description: req.Description,
+		AvatarUrl:   req.AvatarURL,
+		Theme:       firstNonEmpty(req.Theme, bioThemeLight),
+		AccentColor: strings.ToLower(firstNonEmpty(req.AccentColor, defaultAccentColor)),
+	}
+
+	page, err := server.store.UpdateBioPage(ctx, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(server.newBioPageResponse(ctx, page, nil), http.StatusOK))
+}
+
+// SetBioPageLinks replaces the links a bio page shows, in the order given
+func (server *Server) SetBioPageLinks(ctx *gin.Context) {
+	var req setBioPageLinksParams
+
+	page, ok := server.ownedBioPage(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	arg := db.SetBioPageLinksTxParams{
+		BioPageID: page.ID,
+		UserID:    page.UserID,
+		Links:     make([]db.BioPageLinkItem, len(req.Links)),
+	}
+	for i, item := range req.Links {
+		arg.Links[i] = db.BioPageLinkItem{
+			LinkID:  item.LinkID,
+			Label:   item.Label,
+			IconUrl: item.IconURL,
+		}
+	}
+
+	if err := server.store.SetBioPageLinksTx(ctx, arg); err != nil {
+		if errors.Is(err, db.ErrBioPageLinkNotOwned) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	links, err := server.store.GetBioPageLinks(ctx, page.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(server.newBioPageResponse(ctx, page, links), http.StatusOK))
+}
+
+// GetBioPageStats counts the clicks made through a bio page, per link
+func (server *Server) GetBioPageStats(ctx *gin.Context) {
+	var req bioPageStatsParams
+
+	page, ok := server.ownedBioPage(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	arg := db.GetBioPageClickStatsParams{
+		BioPageID: page.ID,
+	}
+
+	if !req.ClickedAfter.IsZero() {
+		arg.ClickedAfter = pgtype.Timestamptz{Time: req.ClickedAfter, Valid: true}
+	}
+
+	if !req.ClickedBefore.IsZero() {
+		arg.ClickedBefore = pgtype.Timestamptz{Time: req.ClickedBefore, Valid: true}
+	}
+
+	stats, err := server.store.GetBioPageClickStats(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	rsp := bioPageStatsResponse{
+		BioPageID: page.ID,
+		Links:     make([]bioPageLinkStats, len(stats)),
+	}
+	for i, stat := range stats {
+		rsp.Clicks += stat.Clicks
+		rsp.Links[i] = bioPageLinkStats{LinkID: stat.LinkID, Clicks: stat.Clicks}
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp, http.StatusOK))
+}
+
+func (server *Server) DeleteBioPage(ctx *gin.Context) {
+	page, ok := server.ownedBioPage(ctx)
+	if !ok {
+		return
+	}
+
+	if err := server.store.DeleteBioPage(ctx, page.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(nil, http.StatusOK))
+}
+
+// serveBioPage renders the public page for slug, or follows one of its links when the visitor picked one
+func (server *Server) serveBioPage(ctx *gin.Context, slug string) {
+	req := bioPageSlugParams{Slug: strings.ToLower(slug)}
+
+	// Binding the query validates the slug along with it
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	page, err := server.store.GetBioPageBySlug(ctx, req.Slug)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			server.respondNotFound(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	if req.LinkID == 0 {
+		server.renderBioPage(ctx, page)
+		return
+	}
+
+	link, err := server.store.GetBioPageLink(ctx, db.GetBioPageLinkParams{BioPageID: page.ID, LinkID: req.LinkID})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			server.respondNotFound(ctx, err)
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	if !link.Active.Bool {
+		ctx.JSON(http.StatusNotFound, errorResponse(err, http.StatusNotFound))
+		return
+	}
+
+	server.followLink(ctx, link, pgtype.Int8{Int64: page.ID, Valid: true})
+}
+
+// renderBioPage lists the page's links that can still be followed. Each one points back at the page
+// so the click is counted for both.
+func (server *Server) renderBioPage(ctx *gin.Context, page db.BioPage) {
+	links, err := server.store.GetBioPageLinks(ctx, page.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	pageURL := server.shortURL(ctx, bioPagePrefix+page.Slug)
+
+	view := bioPageView{
+		Title:       firstNonEmpty(page.Title, bioPagePrefix+page.Slug),
+		Description: page.Description,
+		AvatarURL:   page.AvatarUrl,
+		Theme:       page.Theme,
+		AccentColor: page.AccentColor,
+		Links:       make([]bioPageViewLink, 0, len(links)),
+	}
+
+	for _, link := range links {
+		if !bioPageLinkAvailable(link) {
+			continue
+		}
+		view.Links = append(view.Links, bioPageViewLink{
+			Label:   firstNonEmpty(link.Label, link.Title, link.MetaTitle, link.Link),
+			IconURL: link.IconUrl,
+			URL:     pageURL + "?" + bioPageLinkKey + "=" + strconv.FormatInt(link.LinkID, 10),
+		})
+	}
+
+	ctx.HTML(http.StatusOK, "bio_page.html", view)
+}
+
+func bioPageLinkAvailable(link db.GetBioPageLinksRow) bool {
+	switch {
+	case !link.Active.Bool:
+		return false
+	case link.ExpiresAt.Valid && !link.ExpiresAt.Time.After(time.Now()):
+		return false
+	case link.MaxClicks.Valid && link.ClickCount >= link.MaxClicks.Int64:
+		return false
+	}
+	return true
+}
+
+// ownedBioPage loads the bio page named in the uri and writes the error response when the caller cannot see it
+func (server *Server) ownedBioPage(ctx *gin.Context) (db.BioPage, bool) {
+	var req getLinkByIDParams
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return db.BioPage{}, false
+	}
+
+	page, err := server.store.GetBioPage(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err, http.StatusNotFound))
+			return db.BioPage{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return db.BioPage{}, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if page.UserID != authPayload.UserID {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrBioPageNotOwned, http.StatusForbidden))
+		return db.BioPage{}, false
+	}
+
+	return page, true
+}
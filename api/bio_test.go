@@ -0,0 +1,519 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func createRandomBioPage(userID int64) db.BioPage {
+	return db.BioPage{
+		ID:          util.RandomInt(1, 100),
+		UserID:      userID,
+		Slug:        strings.ToLower(util.RandomString(8)),
+		Title:       util.RandomString(10),
+		Description: util.RandomString(30),
+		Theme:       bioThemeLight,
+		AccentColor: defaultAccentColor,
+		CreatedAt:   time.Now(),
+	}
+}
+
+func TestCreateBioPage(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	user.Username = "JaneDoe"
+
+	testCases := []struct {
+		name          string
+		payload       gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "DefaultSlug",
+			payload: gin.H{"title": "Jane"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				arg := db.CreateBioPageParams{
+					UserID:      user.ID,
+					Slug:        "janedoe",
+					Title:       "Jane",
+					Theme:       bioThemeLight,
+					AccentColor: defaultAccentColor,
+				}
+
+				store.EXPECT().
+					CreateBioPage(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.BioPage{ID: 1, UserID: user.ID, Slug: arg.Slug, Title: arg.Title}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var response Response[bioPageResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, "janedoe", response.Data.Slug)
+				require.Equal(t, "http://example.com/@janedoe", response.Data.URL)
+			},
+		},
+		{
+			name: "ChosenSlug",
+			payload: gin.H{
+				"slug":         "Launch",
+				"theme":        "dark",
+				"accent_color": "#FF0000",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				arg := db.CreateBioPageParams{
+					UserID:      user.ID,
+					Slug:        "launch",
+					Theme:       "dark",
+					AccentColor: "#ff0000",
+				}
+
+				store.EXPECT().
+					CreateBioPage(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.BioPage{ID: 1, UserID: user.ID, Slug: arg.Slug}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:    "InvalidTheme",
+			payload: gin.H{"theme": "neon"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateBioPage(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "SlugTaken",
+			payload: gin.H{"slug": "taken"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateBioPage(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.BioPage{}, db.ErrUniqueViolation)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/bio-pages", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+			request.Host = "example.com"
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSetBioPageLinks(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	page := createRandomBioPage(user.ID)
+
+	testCases := []struct {
+		name          string
+		payload       gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			payload: gin.H{"links": []gin.H{
+				{"link_id": 9, "label": "Shop", "icon_url": "https://cdn.example.com/shop.png"},
+				{"link_id": 4},
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetBioPage(gomock.Any(), gomock.Eq(page.ID)).
+					Times(1).
+					Return(page, nil)
+
+				arg := db.SetBioPageLinksTxParams{
+					BioPageID: page.ID,
+					UserID:    user.ID,
+					Links: []db.BioPageLinkItem{
+						{LinkID: 9, Label: "Shop", IconUrl: "https://cdn.example.com/shop.png"},
+						{LinkID: 4},
+					},
+				}
+
+				store.EXPECT().
+					SetBioPageLinksTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(nil)
+
+				store.EXPECT().
+					GetBioPageLinks(gomock.Any(), gomock.Eq(page.ID)).
+					Times(1).
+					Return([]db.GetBioPageLinksRow{{LinkID: 9, Label: "Shop"}, {LinkID: 4}}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response Response[bioPageResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Data.Links, 2)
+				require.Equal(t, int64(9), response.Data.Links[0].LinkID)
+			},
+		},
+		{
+			name:    "DuplicateLink",
+			payload: gin.H{"links": []gin.H{{"link_id": 9}, {"link_id": 9}}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetBioPage(gomock.Any(), gomock.Eq(page.ID)).
+					Times(1).
+					Return(page, nil)
+
+				store.EXPECT().
+					SetBioPageLinksTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "LinkNotOwned",
+			payload: gin.H{"links": []gin.H{{"link_id": 9}}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetBioPage(gomock.Any(), gomock.Eq(page.ID)).
+					Times(1).
+					Return(page, nil)
+
+				store.EXPECT().
+					SetBioPageLinksTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ErrBioPageLinkNotOwned)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "PageNotOwned",
+			payload: gin.H{"links": []gin.H{}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				other := page
+				other.UserID = user.ID + 1
+
+				store.EXPECT().
+					GetBioPage(gomock.Any(), gomock.Eq(page.ID)).
+					Times(1).
+					Return(other, nil)
+
+				store.EXPECT().
+					SetBioPageLinksTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, fmt.Sprintf("/bio-pages/%d/links", page.ID), bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetBioPageStats(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	page := createRandomBioPage(user.ID)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().
+		GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+		Times(1).
+		Return(user, nil)
+
+	store.EXPECT().
+		GetBioPage(gomock.Any(), gomock.Eq(page.ID)).
+		Times(1).
+		Return(page, nil)
+
+	after := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	store.EXPECT().
+		GetBioPageClickStats(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ any, arg db.GetBioPageClickStatsParams) ([]db.GetBioPageClickStatsRow, error) {
+			require.Equal(t, page.ID, arg.BioPageID)
+			require.True(t, arg.ClickedAfter.Time.Equal(after))
+			require.False(t, arg.ClickedBefore.Valid)
+			return []db.GetBioPageClickStatsRow{{LinkID: 4, Clicks: 3}, {LinkID: 9, Clicks: 2}}, nil
+		})
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/bio-pages/%d/stats?clicked_after=2024-01-01T00:00:00Z", page.ID), nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response Response[bioPageStatsResponse]
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Equal(t, int64(5), response.Data.Clicks)
+	require.Equal(t, []bioPageLinkStats{{LinkID: 4, Clicks: 3}, {LinkID: 9, Clicks: 2}}, response.Data.Links)
+}
+
+func TestServeBioPage(t *testing.T) {
+	userID := util.RandomInt(1, 20)
+	page := createRandomBioPage(userID)
+	page.Theme = "dark"
+
+	link := createRandomLink(userID)
+
+	rows := []db.GetBioPageLinksRow{
+		{LinkID: link.ID, Label: "My <shop>", IconUrl: "https://cdn.example.com/shop.png", Link: link.Link, Active: pgtype.Bool{Bool: true, Valid: true}},
+		{LinkID: 77, MetaTitle: "Fetched title", Link: util.RandomLink(), Active: pgtype.Bool{Bool: true, Valid: true}},
+		{LinkID: 78, Label: "Switched off", Link: util.RandomLink(), Active: pgtype.Bool{Bool: false, Valid: true}},
+		{LinkID: 79, Label: "Expired", Link: util.RandomLink(), Active: pgtype.Bool{Bool: true, Valid: true}, ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true}},
+	}
+
+	testCases := []struct {
+		name          string
+		url           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Page",
+			url:  "/@" + strings.ToUpper(page.Slug),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBioPageBySlug(gomock.Any(), gomock.Eq(page.Slug)).
+					Times(1).
+					Return(page, nil)
+
+				store.EXPECT().
+					GetBioPageLinks(gomock.Any(), gomock.Eq(page.ID)).
+					Times(1).
+					Return(rows, nil)
+
+				store.EXPECT().
+					RecordClick(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Header().Get("Content-Type"), "text/html")
+
+				body := recorder.Body.String()
+				require.Contains(t, body, fmt.Sprintf("<title>%s</title>", page.Title))
+				require.Contains(t, body, `<body class="dark">`)
+				require.Contains(t, body, fmt.Sprintf(`href="http://example.com/@%s?link=%d"`, page.Slug, link.ID))
+				require.Contains(t, body, "My &lt;shop&gt;")
+				require.Contains(t, body, `<img src="https://cdn.example.com/shop.png" alt="">`)
+				require.Contains(t, body, "Fetched title")
+				require.NotContains(t, body, "Switched off")
+				require.NotContains(t, body, "Expired")
+				require.Less(t, strings.Index(body, "My &lt;shop&gt;"), strings.Index(body, "Fetched title"))
+			},
+		},
+		{
+			name: "FollowLink",
+			url:  fmt.Sprintf("/@%s?link=%d", page.Slug, link.ID),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBioPageBySlug(gomock.Any(), gomock.Eq(page.Slug)).
+					Times(1).
+					Return(page, nil)
+
+				store.EXPECT().
+					GetBioPageLink(gomock.Any(), gomock.Eq(db.GetBioPageLinkParams{BioPageID: page.ID, LinkID: link.ID})).
+					Times(1).
+					Return(link, nil)
+
+				arg := db.RecordClickParams{
+					LinkID:    link.ID,
+					BioPageID: pgtype.Int8{Int64: page.ID, Valid: true},
+				}
+
+				store.EXPECT().
+					RecordClick(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPermanentRedirect, recorder.Code)
+				require.Equal(t, link.Link, recorder.Header().Get("Location"))
+			},
+		},
+		{
+			name: "LinkNotOnPage",
+			url:  fmt.Sprintf("/@%s?link=%d", page.Slug, 404),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBioPageBySlug(gomock.Any(), gomock.Eq(page.Slug)).
+					Times(1).
+					Return(page, nil)
+
+				store.EXPECT().
+					GetBioPageLink(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Link{}, db.ErrRecordNotFound)
+
+				store.EXPECT().
+					RecordClick(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownSlug",
+			url:  "/@nobody",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBioPageBySlug(gomock.Any(), gomock.Eq("nobody")).
+					Times(1).
+					Return(db.BioPage{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidSlug",
+			url:  "/@no-pe",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBioPageBySlug(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+			request.Host = "example.com"
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
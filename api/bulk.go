@@ -0,0 +1,194 @@
+package api
+
+import (
+	"errors"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/token"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/bolusarz/urlmini/utm"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"net/http"
+)
+
+const (
+	bulkModeAtomic     = "atomic"
+	bulkModeBestEffort = "best_effort"
+)
+
+var (
+	ErrBulkRolledBack     = errors.New("no links were created because at least one failed")
+	ErrBulkNothingCreated = errors.New("none of the links could be created")
+	ErrBulkDuplicateCode  = errors.New("code is used more than once in this request")
+	ErrBulkCodeTaken      = errors.New("code is already in use")
+	ErrBulkNotCreated     = errors.New("link could not be created")
+)
+
+type bulkLinkItem struct {
+	Link  string   `json:"link" binding:"required,http_url,max=2048"`
+	Code  string   `json:"code" binding:"omitempty,alpha"`
+	Title string   `json:"title" binding:"max=300"`
+	Tags  []string `json:"tags" binding:"max=20,dive,required,max=64"`
+}
+
+type bulkCreateLinksParams struct {
+	// Links are validated one by one so a bad item is reported against its index
+	Links []bulkLinkItem `json:"links" binding:"required,min=1,max=500"`
+	Mode  string         `json:"mode" binding:"omitempty,oneof=atomic best_effort"`
+}
+
+type bulkLinkResult struct {
+	Index int      `json:"index"`
+	Link  *db.Link `json:"link,omitempty"`
+	Tags  []db.Tag `json:"tags,omitempty"`
+	Error string   `json:"error,omitempty"`
+}
+
+type bulkCreateLinksResponse struct {
+	Mode    string           `json:"mode"`
+	Created int              `json:"created"`
+	Failed  int              `json:"failed"`
+	Results []bulkLinkResult `json:"results"`
+}
+
+// BulkCreateLinks creates many links at once. In atomic mode either every link is created or none is,
+// best effort mode keeps the links that succeeded and reports the rest.
+func (server *Server) BulkCreateLinks(ctx *gin.Context) {
+	var req bulkCreateLinksParams
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	if req.Mode == "" {
+		req.Mode = bulkModeAtomic
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	rsp := bulkCreateLinksResponse{
+		Mode:    req.Mode,
+		Results: make([]bulkLinkResult, len(req.Links)),
+	}
+
+	var pending []int
+	args := make([]db.CreateLinkTxParams, len(req.Links))
+	codes := make(map[string]bool, len(req.Links))
+
+	for i, item := range req.Links {
+		rsp.Results[i].Index = i
+
+		if err := binding.Validator.ValidateStruct(&item); err != nil {
+			rsp.Results[i].Error = err.Error()
+			continue
+		}
+
+		if item.Code == "" {
+			item.Code = util.RandomCode()
+		}
+
+		if codes[item.Code] {
+			rsp.Results[i].Error = ErrBulkDuplicateCode.Error()
+			continue
+		}
+		codes[item.Code] = true
+
+		args[i] = db.CreateLinkTxParams{
+			CreateLinkParams: db.CreateLinkParams{
+				Code:        item.Code,
+				Link:        item.Link,
+				UserID:      authPayload.UserID,
+				Title:       item.Title,
+				UtmCampaign: utm.Campaign(item.Link),
+			},
+			Tags: item.Tags,
+		}
+		pending = append(pending, i)
+	}
+
+	if req.Mode == bulkModeAtomic {
+		server.bulkCreateAtomic(ctx, args, pending, rsp)
+		return
+	}
+
+	for _, i := range pending {
+		result, err := server.store.CreateLinkTx(ctx, args[i])
+		if err != nil {
+			rsp.Results[i].Error = bulkItemError(err).Error()
+			continue
+		}
+
+		rsp.Results[i].Link = &result.Link
+		rsp.Results[i].Tags = result.Tags
+	}
+
+	rsp.Created, rsp.Failed = countBulkResults(rsp.Results)
+
+	if rsp.Created == 0 {
+		ctx.JSON(http.StatusBadRequest, bulkErrorResponse(ErrBulkNothingCreated, rsp))
+		return
+	}
+
+	status := http.StatusCreated
+	if rsp.Failed > 0 {
+		status = http.StatusMultiStatus
+	}
+
+	ctx.JSON(status, successResponse(rsp, status))
+}
+
+func (server *Server) bulkCreateAtomic(ctx *gin.Context, args []db.CreateLinkTxParams, pending []int, rsp bulkCreateLinksResponse) {
+	if len(pending) < len(args) {
+		rsp.Failed = len(args) - len(pending)
+		ctx.JSON(http.StatusBadRequest, bulkErrorResponse(ErrBulkRolledBack, rsp))
+		return
+	}
+
+	results, err := server.store.CreateLinksTx(ctx, args)
+	if err != nil {
+		var txErr *db.LinkTxError
+		if !errors.As(err, &txErr) || bulkItemError(txErr.Err) == ErrBulkNotCreated {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+			return
+		}
+
+		rsp.Results[txErr.Index].Error = bulkItemError(txErr.Err).Error()
+		rsp.Failed = 1
+		ctx.JSON(http.StatusBadRequest, bulkErrorResponse(ErrBulkRolledBack, rsp))
+		return
+	}
+
+	for i := range results {
+		rsp.Results[i].Link = &results[i].Link
+		rsp.Results[i].Tags = results[i].Tags
+	}
+	rsp.Created = len(results)
+
+	ctx.JSON(http.StatusCreated, successResponse(rsp, http.StatusCreated))
+}
+
+// bulkItemError turns a database failure into the message reported for that item
+func bulkItemError(err error) error {
+	if db.ErrorCode(err) == db.UniqueViolation {
+		return ErrBulkCodeTaken
+	}
+	return ErrBulkNotCreated
+}
+
+func bulkErrorResponse(err error, rsp bulkCreateLinksResponse) gin.H {
+	body := errorResponse(err, http.StatusBadRequest)
+	body["data"] = rsp
+	return body
+}
+
+func countBulkResults(results []bulkLinkResult) (created, failed int) {
+	for _, result := range results {
+		if result.Link != nil {
+			created++
+		} else {
+			failed++
+		}
+	}
+	return created, failed
+}
@@ -0,0 +1,221 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestBulkCreateLinks(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	first := createRandomLink(user.ID)
+	second := createRandomLink(user.ID)
+	tag := createRandomTag(user.ID)
+
+	items := []gin.H{
+		{"link": first.Link, "code": first.Code, "tags": []string{tag.Name}},
+		{"link": second.Link, "code": second.Code},
+	}
+
+	testCases := []struct {
+		name          string
+		payload       gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "Atomic",
+			payload: gin.H{"links": items},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := []db.CreateLinkTxParams{
+					{
+						CreateLinkParams: db.CreateLinkParams{Code: first.Code, Link: first.Link, UserID: user.ID},
+						Tags:             []string{tag.Name},
+					},
+					{
+						CreateLinkParams: db.CreateLinkParams{Code: second.Code, Link: second.Link, UserID: user.ID},
+					},
+				}
+
+				store.EXPECT().
+					CreateLinksTx(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return([]db.CreateLinkTxResult{
+						{Link: first, Tags: []db.Tag{tag}},
+						{Link: second, Tags: []db.Tag{}},
+					}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				rsp := requireBulkResponse(t, recorder)
+				require.Equal(t, bulkModeAtomic, rsp.Mode)
+				require.Equal(t, 2, rsp.Created)
+				require.Equal(t, first.ID, rsp.Results[0].Link.ID)
+				require.Len(t, rsp.Results[0].Tags, 1)
+				require.Equal(t, tag.Name, rsp.Results[0].Tags[0].Name)
+			},
+		},
+		{
+			name: "AtomicInvalidItem",
+			payload: gin.H{"links": []gin.H{
+				items[0],
+				{"link": "not a url"},
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateLinksTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+				rsp := requireBulkResponse(t, recorder)
+				require.Empty(t, rsp.Results[0].Error)
+				require.NotEmpty(t, rsp.Results[1].Error)
+				require.Equal(t, 1, rsp.Failed)
+			},
+		},
+		{
+			name:    "AtomicCodeTaken",
+			payload: gin.H{"links": items},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateLinksTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, &db.LinkTxError{Index: 1, Err: &pgconn.PgError{Code: db.UniqueViolation}})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+
+				rsp := requireBulkResponse(t, recorder)
+				require.Zero(t, rsp.Created)
+				require.Equal(t, ErrBulkCodeTaken.Error(), rsp.Results[1].Error)
+				require.Nil(t, rsp.Results[0].Link)
+			},
+		},
+		{
+			name:    "AtomicInternalServerError",
+			payload: gin.H{"links": items},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateLinksTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+		{
+			name: "BestEffortPartial",
+			payload: gin.H{
+				"mode": bulkModeBestEffort,
+				"links": []gin.H{
+					items[0],
+					items[1],
+					{"link": "ftp:/broken"},
+				},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateLinkTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateLinkTxResult{Link: first, Tags: []db.Tag{tag}}, nil)
+
+				store.EXPECT().
+					CreateLinkTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateLinkTxResult{}, &pgconn.PgError{Code: db.UniqueViolation})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusMultiStatus, recorder.Code)
+
+				rsp := requireBulkResponse(t, recorder)
+				require.Equal(t, 1, rsp.Created)
+				require.Equal(t, 2, rsp.Failed)
+				require.NotNil(t, rsp.Results[0].Link)
+				require.Equal(t, ErrBulkCodeTaken.Error(), rsp.Results[1].Error)
+				require.NotEmpty(t, rsp.Results[2].Error)
+			},
+		},
+		{
+			name: "DuplicateCodeInRequest",
+			payload: gin.H{
+				"mode":  bulkModeBestEffort,
+				"links": []gin.H{items[0], items[0]},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateLinkTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CreateLinkTxResult{Link: first}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusMultiStatus, recorder.Code)
+
+				rsp := requireBulkResponse(t, recorder)
+				require.Equal(t, ErrBulkDuplicateCode.Error(), rsp.Results[1].Error)
+			},
+		},
+		{
+			name:    "NoLinks",
+			payload: gin.H{"links": []gin.H{}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateLinksTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+				Times(1).
+				Return(user, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/links/bulk", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func requireBulkResponse(t *testing.T, recorder *httptest.ResponseRecorder) bulkCreateLinksResponse {
+	var response struct {
+		Data bulkCreateLinksResponse `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	return response.Data
+}
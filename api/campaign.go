@@ -0,0 +1,252 @@
+package api
+
+import (
+	"errors"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrCampaignNotOwned = errors.New("campaign does not belong to this user")
+	ErrCampaignExists   = errors.New("a campaign with this name already exists")
+	ErrCampaignDates    = errors.New("campaign must end after it starts")
+)
+
+type createCampaignParams struct {
+	Name     string     `json:"name" binding:"required,max=100"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+}
+
+type campaignLinksParams struct {
+	LinkIDs []int64 `json:"link_ids" binding:"required,min=1,max=1000,dive,min=1"`
+}
+
+type campaignLinksResponse struct {
+	Affected int64 `json:"affected"`
+}
+
+// campaignStatsParams narrow the stats window, which defaults to the campaign's own dates
+type campaignStatsParams struct {
+	ClickedAfter  time.Time `form:"clicked_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	ClickedBefore time.Time `form:"clicked_before" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+type campaignStatsResponse struct {
+	CampaignID    int64                           `json:"campaign_id"`
+	ClickedAfter  pgtype.Timestamptz              `json:"clicked_after"`
+	ClickedBefore pgtype.Timestamptz              `json:"clicked_before"`
+	Clicks        int64                           `json:"clicks"`
+	Links         []db.GetCampaignLinkStatsRow    `json:"links"`
+	Channels      []db.GetCampaignChannelStatsRow `json:"channels"`
+}
+
+func (server *Server) CreateCampaign(ctx *gin.Context) {
+	var req createCampaignParams
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	if req.StartsAt != nil && req.EndsAt != nil && !req.EndsAt.After(*req.StartsAt) {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ErrCampaignDates, http.StatusBadRequest))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.CreateCampaignParams{
+		UserID: authPayload.UserID,
+		Name:   req.Name,
+	}
+
+	if req.StartsAt != nil {
+		arg.StartsAt = pgtype.Timestamptz{Time: *req.StartsAt, Valid: true}
+	}
+
+	if req.EndsAt != nil {
+		arg.EndsAt = pgtype.Timestamptz{Time: *req.EndsAt, Valid: true}
+	}
+
+	campaign, err := server.store.CreateCampaign(ctx, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ErrCampaignExists, http.StatusBadRequest))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, successResponse(campaign, http.StatusCreated))
+}
+
+func (server *Server) GetCampaigns(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	campaigns, err := server.store.GetCampaignsByUser(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(campaigns, http.StatusOK))
+}
+
+func (server *Server) DeleteCampaign(ctx *gin.Context) {
+	campaign, ok := server.ownedCampaign(ctx)
+	if !ok {
+		return
+	}
+
+	if err := server.store.DeleteCampaign(ctx, campaign.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(nil, http.StatusOK))
+}
+
+// AddCampaignLinks puts links of the caller in a campaign, skipping ones already in it
+func (server *Server) AddCampaignLinks(ctx *gin.Context) {
+	var req campaignLinksParams
+
+	campaign, ok := server.ownedCampaign(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	affected, err := server.store.AddCampaignLinks(ctx, db.AddCampaignLinksParams{
+		CampaignID: campaign.ID,
+		LinkIds:    req.LinkIDs,
+		UserID:     campaign.UserID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(campaignLinksResponse{Affected: affected}, http.StatusOK))
+}
+
+func (server *Server) RemoveCampaignLinks(ctx *gin.Context) {
+	var req campaignLinksParams
+
+	campaign, ok := server.ownedCampaign(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	affected, err := server.store.RemoveCampaignLinks(ctx, db.RemoveCampaignLinksParams{
+		CampaignID: campaign.ID,
+		LinkIds:    req.LinkIDs,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(campaignLinksResponse{Affected: affected}, http.StatusOK))
+}
+
+// GetCampaignStats sums the clicks of a campaign's links, broken down per link and per channel.
+// A click's channel is the host that referred it, bio_page when it came through a bio page
+// and direct when there was no referrer.
+func (server *Server) GetCampaignStats(ctx *gin.Context) {
+	var req campaignStatsParams
+
+	campaign, ok := server.ownedCampaign(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	rsp := campaignStatsResponse{
+		CampaignID:    campaign.ID,
+		ClickedAfter:  campaign.StartsAt,
+		ClickedBefore: campaign.EndsAt,
+	}
+
+	if !req.ClickedAfter.IsZero() {
+		rsp.ClickedAfter = pgtype.Timestamptz{Time: req.ClickedAfter, Valid: true}
+	}
+
+	if !req.ClickedBefore.IsZero() {
+		rsp.ClickedBefore = pgtype.Timestamptz{Time: req.ClickedBefore, Valid: true}
+	}
+
+	links, err := server.store.GetCampaignLinkStats(ctx, db.GetCampaignLinkStatsParams{
+		CampaignID:    campaign.ID,
+		ClickedAfter:  rsp.ClickedAfter,
+		ClickedBefore: rsp.ClickedBefore,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	channels, err := server.store.GetCampaignChannelStats(ctx, db.GetCampaignChannelStatsParams{
+		CampaignID:    campaign.ID,
+		ClickedAfter:  rsp.ClickedAfter,
+		ClickedBefore: rsp.ClickedBefore,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	rsp.Links = links
+	rsp.Channels = channels
+	for _, link := range links {
+		rsp.Clicks += link.Clicks
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp, http.StatusOK))
+}
+
+func (server *Server) ownedCampaign(ctx *gin.Context) (db.Campaign, bool) {
+	var req getLinkByIDParams
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return db.Campaign{}, false
+	}
+
+	campaign, err := server.store.GetCampaign(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err, http.StatusNotFound))
+			return db.Campaign{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return db.Campaign{}, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if campaign.UserID != authPayload.UserID {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrCampaignNotOwned, http.StatusForbidden))
+		return db.Campaign{}, false
+	}
+
+	return campaign, true
+}
@@ -0,0 +1,226 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/token"
+	"github.com/bolusarz/urlmini/utm"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	ErrCampaignTemplateNotOwned = errors.New("campaign template does not belong to this user")
+	ErrCampaignTemplateExists   = errors.New("a campaign template with this name already exists")
+	ErrCampaignTemplateEmpty    = errors.New("campaign template sets no parameters")
+	ErrReservedUTMParam         = errors.New("custom params cannot replace the standard utm parameters")
+)
+
+type createCampaignTemplateParams struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	UtmSource   string `json:"utm_source" binding:"max=200"`
+	UtmMedium   string `json:"utm_medium" binding:"max=200"`
+	UtmCampaign string `json:"utm_campaign" binding:"max=200"`
+	UtmTerm     string `json:"utm_term" binding:"max=200"`
+	UtmContent  string `json:"utm_content" binding:"max=200"`
+
+	// Params are any other query parameters to add, such as a partner id
+	Params map[string]string `json:"params" binding:"max=20,dive,keys,required,max=50,endkeys,max=500"`
+}
+
+type campaignTemplateResponse struct {
+	ID          int64             `json:"id"`
+	Name        string            `json:"name"`
+	UtmSource   string            `json:"utm_source"`
+	UtmMedium   string            `json:"utm_medium"`
+	UtmCampaign string            `json:"utm_campaign"`
+	UtmTerm     string            `json:"utm_term"`
+	UtmContent  string            `json:"utm_content"`
+	Params      map[string]string `json:"params"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+func newCampaignTemplateResponse(template db.CampaignTemplate) (campaignTemplateResponse, error) {
+	params, err := templateUTMParams(template)
+	if err != nil {
+		return campaignTemplateResponse{}, err
+	}
+
+	return campaignTemplateResponse{
+		ID:          template.ID,
+		Name:        template.Name,
+		UtmSource:   template.UtmSource,
+		UtmMedium:   template.UtmMedium,
+		UtmCampaign: template.UtmCampaign,
+		UtmTerm:     template.UtmTerm,
+		UtmContent:  template.UtmContent,
+		Params:      params.Custom,
+		CreatedAt:   template.CreatedAt,
+	}, nil
+}
+
+// templateUTMParams decodes the parameters a template adds to a destination
+func templateUTMParams(template db.CampaignTemplate) (utm.Params, error) {
+	params := utm.Params{
+		Source:   template.UtmSource,
+		Medium:   template.UtmMedium,
+		Campaign: template.UtmCampaign,
+		Term:     template.UtmTerm,
+		Content:  template.UtmContent,
+		Custom:   map[string]string{},
+	}
+
+	if len(template.Params) > 0 {
+		if err := json.Unmarshal(template.Params, &params.Custom); err != nil {
+			return utm.Params{}, err
+		}
+	}
+
+	return params, nil
+}
+
+func (server *Server) CreateCampaignTemplate(ctx *gin.Context) {
+	var req createCampaignTemplateParams
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	for key := range req.Params {
+		if strings.HasPrefix(strings.ToLower(key), "utm_") {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ErrReservedUTMParam, http.StatusBadRequest))
+			return
+		}
+	}
+
+	if req.UtmSource == "" && req.UtmMedium == "" && req.UtmCampaign == "" &&
+		req.UtmTerm == "" && req.UtmContent == "" && len(req.Params) == 0 {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ErrCampaignTemplateEmpty, http.StatusBadRequest))
+		return
+	}
+
+	if req.Params == nil {
+		req.Params = map[string]string{}
+	}
+
+	params, err := json.Marshal(req.Params)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	template, err := server.store.CreateCampaignTemplate(ctx, db.CreateCampaignTemplateParams{
+		UserID:      authPayload.UserID,
+		Name:        req.Name,
+		UtmSource:   req.UtmSource,
+		UtmMedium:   req.UtmMedium,
+		UtmCampaign: req.UtmCampaign,
+		UtmTerm:     req.UtmTerm,
+		UtmContent:  req.UtmContent,
+		Params:      params,
+	})
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ErrCampaignTemplateExists, http.StatusBadRequest))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	rsp, err := newCampaignTemplateResponse(template)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, successResponse(rsp, http.StatusCreated))
+}
+
+func (server *Server) GetCampaignTemplates(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	templates, err := server.store.GetCampaignTemplatesByUser(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	rsp := make([]campaignTemplateResponse, len(templates))
+	for i, template := range templates {
+		rsp[i], err = newCampaignTemplateResponse(template)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp, http.StatusOK))
+}
+
+func (server *Server) DeleteCampaignTemplate(ctx *gin.Context) {
+	var req getLinkByIDParams
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	template, status, err := server.ownedCampaignTemplate(ctx, req.ID)
+	if err != nil {
+		ctx.JSON(status, errorResponse(err, status))
+		return
+	}
+
+	if err := server.store.DeleteCampaignTemplate(ctx, template.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(nil, http.StatusOK))
+}
+
+// ownedCampaignTemplate loads a template of the caller, with the status to respond with when it can't be used
+func (server *Server) ownedCampaignTemplate(ctx *gin.Context, id int64) (db.CampaignTemplate, int, error) {
+	template, err := server.store.GetCampaignTemplate(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return db.CampaignTemplate{}, http.StatusNotFound, err
+		}
+		return db.CampaignTemplate{}, http.StatusInternalServerError, err
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if template.UserID != authPayload.UserID {
+		return db.CampaignTemplate{}, http.StatusForbidden, ErrCampaignTemplateNotOwned
+	}
+
+	return template, http.StatusOK, nil
+}
+
+// applyCampaignTemplate merges a template's parameters into a destination
+func (server *Server) applyCampaignTemplate(ctx *gin.Context, id int64, destination string) (string, int, error) {
+	template, status, err := server.ownedCampaignTemplate(ctx, id)
+	if err != nil {
+		return "", status, err
+	}
+
+	params, err := templateUTMParams(template)
+	if err != nil {
+		return "", http.StatusInternalServerError, err
+	}
+
+	link, err := utm.Apply(destination, params)
+	if err != nil {
+		return "", http.StatusBadRequest, err
+	}
+
+	return link, http.StatusOK, nil
+}
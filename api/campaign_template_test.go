@@ -0,0 +1,265 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func createRandomCampaignTemplate(userID int64) db.CampaignTemplate {
+	return db.CampaignTemplate{
+		ID:          util.RandomInt(1, 1000),
+		UserID:      userID,
+		Name:        util.RandomString(8),
+		UtmSource:   "newsletter",
+		UtmMedium:   "email",
+		UtmCampaign: util.RandomString(6),
+		Params:      []byte(`{"partner":"acme"}`),
+		CreatedAt:   time.Now(),
+	}
+}
+
+func TestCreateCampaignTemplate(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	template := createRandomCampaignTemplate(user.ID)
+
+	testCases := []struct {
+		name          string
+		payload       gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			payload: gin.H{
+				"name":         template.Name,
+				"utm_source":   template.UtmSource,
+				"utm_medium":   template.UtmMedium,
+				"utm_campaign": template.UtmCampaign,
+				"params":       gin.H{"partner": "acme"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				arg := db.CreateCampaignTemplateParams{
+					UserID:      user.ID,
+					Name:        template.Name,
+					UtmSource:   template.UtmSource,
+					UtmMedium:   template.UtmMedium,
+					UtmCampaign: template.UtmCampaign,
+					Params:      template.Params,
+				}
+
+				store.EXPECT().
+					CreateCampaignTemplate(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(template, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var response Response[campaignTemplateResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, template.ID, response.Data.ID)
+				require.Equal(t, template.UtmCampaign, response.Data.UtmCampaign)
+				require.Equal(t, map[string]string{"partner": "acme"}, response.Data.Params)
+			},
+		},
+		{
+			name:    "Empty",
+			payload: gin.H{"name": template.Name},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateCampaignTemplate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "ReservedParam",
+			payload: gin.H{
+				"name":   template.Name,
+				"params": gin.H{"UTM_source": "other"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateCampaignTemplate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "DuplicateName",
+			payload: gin.H{
+				"name":       template.Name,
+				"utm_source": template.UtmSource,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateCampaignTemplate(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.CampaignTemplate{}, &pgconn.PgError{Code: db.UniqueViolation})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/campaign-templates", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteCampaignTemplate(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	template := createRandomCampaignTemplate(user.ID)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetCampaignTemplate(gomock.Any(), gomock.Eq(template.ID)).
+					Times(1).
+					Return(template, nil)
+
+				store.EXPECT().
+					DeleteCampaignTemplate(gomock.Any(), gomock.Eq(template.ID)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetCampaignTemplate(gomock.Any(), gomock.Eq(template.ID)).
+					Times(1).
+					Return(db.CampaignTemplate{}, db.ErrRecordNotFound)
+
+				store.EXPECT().
+					DeleteCampaignTemplate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NotOwned",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				other := template
+				other.UserID = user.ID + 1
+
+				store.EXPECT().
+					GetCampaignTemplate(gomock.Any(), gomock.Eq(template.ID)).
+					Times(1).
+					Return(other, nil)
+
+				store.EXPECT().
+					DeleteCampaignTemplate(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/campaign-templates/%d", template.ID), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
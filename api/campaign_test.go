@@ -0,0 +1,320 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func createRandomCampaign(userID int64) db.Campaign {
+	return db.Campaign{
+		ID:        util.RandomInt(1, 1000),
+		UserID:    userID,
+		Name:      util.RandomString(8),
+		StartsAt:  pgtype.Timestamptz{Time: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		EndsAt:    pgtype.Timestamptz{Time: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), Valid: true},
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestCreateCampaign(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	campaign := createRandomCampaign(user.ID)
+
+	testCases := []struct {
+		name          string
+		payload       gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			payload: gin.H{
+				"name":      campaign.Name,
+				"starts_at": campaign.StartsAt.Time,
+				"ends_at":   campaign.EndsAt.Time,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				arg := db.CreateCampaignParams{
+					UserID:   user.ID,
+					Name:     campaign.Name,
+					StartsAt: campaign.StartsAt,
+					EndsAt:   campaign.EndsAt,
+				}
+
+				store.EXPECT().
+					CreateCampaign(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(campaign, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var response Response[db.Campaign]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, campaign.ID, response.Data.ID)
+			},
+		},
+		{
+			name: "EndsBeforeStart",
+			payload: gin.H{
+				"name":      campaign.Name,
+				"starts_at": campaign.EndsAt.Time,
+				"ends_at":   campaign.StartsAt.Time,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateCampaign(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "MissingName",
+			payload: gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateCampaign(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/campaigns", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAddCampaignLinks(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	campaign := createRandomCampaign(user.ID)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().
+		GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+		Times(1).
+		Return(user, nil)
+
+	store.EXPECT().
+		GetCampaign(gomock.Any(), gomock.Eq(campaign.ID)).
+		Times(1).
+		Return(campaign, nil)
+
+	store.EXPECT().
+		AddCampaignLinks(gomock.Any(), gomock.Eq(db.AddCampaignLinksParams{
+			CampaignID: campaign.ID,
+			LinkIds:    []int64{1, 2},
+			UserID:     user.ID,
+		})).
+		Times(1).
+		Return(int64(2), nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	jsonBody, err := json.Marshal(gin.H{"link_ids": []int64{1, 2}})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/campaigns/%d/links", campaign.ID), bytes.NewBuffer(jsonBody))
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response Response[campaignLinksResponse]
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Equal(t, int64(2), response.Data.Affected)
+}
+
+func TestGetCampaignStats(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	campaign := createRandomCampaign(user.ID)
+
+	linkStats := []db.GetCampaignLinkStatsRow{
+		{LinkID: 1, Code: "spring", Link: "https://example.com/a", Clicks: 5, Referrers: 2},
+		{LinkID: 2, Code: "summer", Link: "https://example.com/b", Clicks: 3, Referrers: 1},
+		{LinkID: 3, Code: "autumn", Link: "https://example.com/c"},
+	}
+	channelStats := []db.GetCampaignChannelStatsRow{
+		{Channel: "t.co", Clicks: 4},
+		{Channel: "direct", Clicks: 3},
+		{Channel: "bio_page", Clicks: 1},
+	}
+
+	clickedAfter := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		query         url.Values
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "CampaignWindow",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCampaign(gomock.Any(), gomock.Eq(campaign.ID)).
+					Times(1).
+					Return(campaign, nil)
+
+				store.EXPECT().
+					GetCampaignLinkStats(gomock.Any(), gomock.Eq(db.GetCampaignLinkStatsParams{
+						CampaignID:    campaign.ID,
+						ClickedAfter:  campaign.StartsAt,
+						ClickedBefore: campaign.EndsAt,
+					})).
+					Times(1).
+					Return(linkStats, nil)
+
+				store.EXPECT().
+					GetCampaignChannelStats(gomock.Any(), gomock.Eq(db.GetCampaignChannelStatsParams{
+						CampaignID:    campaign.ID,
+						ClickedAfter:  campaign.StartsAt,
+						ClickedBefore: campaign.EndsAt,
+					})).
+					Times(1).
+					Return(channelStats, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response Response[campaignStatsResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, campaign.ID, response.Data.CampaignID)
+				require.Equal(t, int64(8), response.Data.Clicks)
+				require.Equal(t, linkStats, response.Data.Links)
+				require.Equal(t, channelStats, response.Data.Channels)
+			},
+		},
+		{
+			name:  "QueryWindow",
+			query: url.Values{"clicked_after": {clickedAfter.Format(time.RFC3339)}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetCampaign(gomock.Any(), gomock.Eq(campaign.ID)).
+					Times(1).
+					Return(campaign, nil)
+
+				store.EXPECT().
+					GetCampaignLinkStats(gomock.Any(), gomock.Eq(db.GetCampaignLinkStatsParams{
+						CampaignID:    campaign.ID,
+						ClickedAfter:  pgtype.Timestamptz{Time: clickedAfter, Valid: true},
+						ClickedBefore: campaign.EndsAt,
+					})).
+					Times(1).
+					Return(linkStats, nil)
+
+				store.EXPECT().
+					GetCampaignChannelStats(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(channelStats, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotOwned",
+			buildStubs: func(store *mockdb.MockStore) {
+				other := campaign
+				other.UserID = user.ID + 1
+
+				store.EXPECT().
+					GetCampaign(gomock.Any(), gomock.Eq(campaign.ID)).
+					Times(1).
+					Return(other, nil)
+
+				store.EXPECT().
+					GetCampaignLinkStats(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+				Times(1).
+				Return(user, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/campaigns/%d/stats?%s", campaign.ID, tc.query.Encode()), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
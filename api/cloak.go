@@ -0,0 +1,97 @@
+package api
+
+import (
+	"errors"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/token"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/url"
+)
+
+type cloakPage struct {
+	Title       string
+	Favicon     string
+	Destination string
+	Pixels      []db.Pixel
+}
+
+type setLinkCloakParams struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// SetLinkCloak turns cloaking on or off; turning it on checks again that the destination may be framed
+func (server *Server) SetLinkCloak(ctx *gin.Context) {
+	var req setLinkCloakParams
+	var linkReq getLinkByIDParams
+
+	if err := ctx.ShouldBindUri(&linkReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	link, err := server.store.GetLinkById(ctx, linkReq.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err, http.StatusNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if link.UserID != authPayload.UserID {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrLinkNotOwned, http.StatusForbidden))
+		return
+	}
+
+	if *req.Enabled {
+		if err := server.cloakChecker.Check(ctx, link.Link); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+			return
+		}
+	}
+
+	link, err = server.store.SetLinkCloaked(ctx, db.SetLinkCloakedParams{
+		Cloaked: *req.Enabled,
+		ID:      link.ID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(link, http.StatusOK))
+}
+
+// renderCloak shows the destination in a full-window frame so the short URL stays in the address bar,
+// titled from the link's metadata and firing its pixels, if any
+func (server *Server) renderCloak(ctx *gin.Context, link db.Link) {
+	if !isWebURL(link.Link) {
+		ctx.Redirect(http.StatusPermanentRedirect, link.Link)
+		return
+	}
+
+	host := link.Link
+	if destination, err := url.Parse(link.Link); err == nil && destination.Host != "" {
+		host = destination.Hostname()
+	}
+
+	page := cloakPage{
+		Title:       firstNonEmpty(link.Title, link.MetaTitle, link.OgTitle, host),
+		Favicon:     link.MetaFavicon,
+		Destination: link.Link,
+		Pixels:      server.linkPixels(ctx, link),
+	}
+
+	// The frame page itself must not end up framed elsewhere
+	ctx.Header("X-Frame-Options", "DENY")
+	ctx.HTML(http.StatusOK, "cloak.html", page)
+}
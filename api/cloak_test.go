@@ -0,0 +1,253 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/bolusarz/urlmini/cloak"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newFramingServer serves /open to anyone's frames and refuses framing everywhere else
+func newFramingServer(t *testing.T) *httptest.Server {
+	destination := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/open" {
+			w.Header().Set("Content-Security-Policy", "frame-ancestors 'self'")
+		}
+		w.Write([]byte("<html></html>"))
+	}))
+	t.Cleanup(destination.Close)
+	return destination
+}
+
+func TestCreateCloakedLink(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	destination := newFramingServer(t)
+	code := util.RandomCode()
+
+	testCases := []struct {
+		name          string
+		link          string
+		buildStubs    func(store *mockdb.MockStore, link string)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			link: destination.URL + "/open",
+			buildStubs: func(store *mockdb.MockStore, link string) {
+				arg := db.CreateLinkParams{
+					Code:    code,
+					Link:    link,
+					UserID:  user.ID,
+					Cloaked: true,
+				}
+
+				store.EXPECT().
+					CreateLink(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(db.Link{ID: 1, UserID: user.ID, Code: code, Link: link, Cloaked: true}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "FramingRefused",
+			link: destination.URL + "/closed",
+			buildStubs: func(store *mockdb.MockStore, link string) {
+				store.EXPECT().
+					CreateLink(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+				Times(1).
+				Return(user, nil)
+			tc.buildStubs(store, tc.link)
+
+			server := newTestServer(t, store)
+			server.cloakChecker = cloak.NewChecker(cloak.Options{AllowPrivateNetworks: true, AllowInsecure: true})
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(gin.H{"link": tc.link, "code": code, "cloak": true})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/links", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSetLinkCloak(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	destination := newFramingServer(t)
+
+	open := createRandomLink(user.ID)
+	open.Link = destination.URL + "/open"
+
+	closed := createRandomLink(user.ID)
+	closed.Link = destination.URL + "/closed"
+
+	testCases := []struct {
+		name          string
+		link          db.Link
+		enabled       bool
+		buildStubs    func(store *mockdb.MockStore, link db.Link)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "Enable",
+			link:    open,
+			enabled: true,
+			buildStubs: func(store *mockdb.MockStore, link db.Link) {
+				updated := link
+				updated.Cloaked = true
+
+				store.EXPECT().
+					SetLinkCloaked(gomock.Any(), gomock.Eq(db.SetLinkCloakedParams{Cloaked: true, ID: link.ID})).
+					Times(1).
+					Return(updated, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response Response[db.Link]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.True(t, response.Data.Cloaked)
+			},
+		},
+		{
+			name:    "FramingRefused",
+			link:    closed,
+			enabled: true,
+			buildStubs: func(store *mockdb.MockStore, link db.Link) {
+				store.EXPECT().
+					SetLinkCloaked(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "DisableSkipsCheck",
+			link:    closed,
+			enabled: false,
+			buildStubs: func(store *mockdb.MockStore, link db.Link) {
+				store.EXPECT().
+					SetLinkCloaked(gomock.Any(), gomock.Eq(db.SetLinkCloakedParams{Cloaked: false, ID: link.ID})).
+					Times(1).
+					Return(link, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+				Times(1).
+				Return(user, nil)
+
+			store.EXPECT().
+				GetLinkById(gomock.Any(), gomock.Eq(tc.link.ID)).
+				Times(1).
+				Return(tc.link, nil)
+			tc.buildStubs(store, tc.link)
+
+			server := newTestServer(t, store)
+			server.cloakChecker = cloak.NewChecker(cloak.Options{AllowPrivateNetworks: true, AllowInsecure: true})
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(gin.H{"enabled": tc.enabled})
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("/links/%d/cloak", tc.link.ID), bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestCloakPage(t *testing.T) {
+	link := createRandomLink(util.RandomInt(1, 20))
+	link.Link = "https://shop.example.com/sale"
+	link.MetaTitle = "Spring sale"
+	link.MetaFavicon = "https://shop.example.com/favicon.ico"
+	link.Cloaked = true
+	link.HasPixels = true
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetLinkByCode(gomock.Any(), gomock.Eq(link.Code)).
+		Times(1).
+		Return(link, nil)
+
+	store.EXPECT().
+		RecordClick(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(int64(1), nil)
+
+	store.EXPECT().
+		GetPixelsByLink(gomock.Any(), gomock.Eq(link.ID)).
+		Times(1).
+		Return([]db.Pixel{{ID: 1, Provider: pixelProviderLinkedIn, AccountID: "98765"}}, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/"+link.Code, nil)
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.Equal(t, "DENY", recorder.Header().Get("X-Frame-Options"))
+
+	body := recorder.Body.String()
+	require.Contains(t, body, "<title>Spring sale</title>")
+	require.Contains(t, body, `<link rel="icon" href="https://shop.example.com/favicon.ico">`)
+	require.Contains(t, body, `<iframe src="https://shop.example.com/sale"`)
+	require.Contains(t, body, `_linkedin_data_partner_ids.push("98765")`)
+	require.NotContains(t, body, "location.replace")
+}
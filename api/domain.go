@@ -0,0 +1,327 @@
+package api
+
+import (
+	"errors"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/domain"
+	"github.com/bolusarz/urlmini/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// requestDomainKey holds the custom domain a redirect request came in on
+const requestDomainKey = "request_domain"
+
+var (
+	ErrDomainNotOwned    = errors.New("domain does not belong to this user")
+	ErrDomainNotVerified = errors.New("domain has not been verified yet")
+	ErrDomainReserved    = errors.New("domain is the service's own short domain")
+	ErrDomainInUse       = errors.New("domain still has links")
+	ErrDomainTaken       = errors.New("domain has already been verified by another account")
+)
+
+type createDomainParams struct {
+	Hostname string `json:"hostname" binding:"required,fqdn,max=253"`
+}
+
+type verifyDomainParams struct {
+	Method string `json:"method" binding:"required,oneof=dns http"`
+}
+
+type setDomainFallbacksParams struct {
+	RootRedirectURL string `json:"root_redirect_url" binding:"omitempty,http_url,max=2048"`
+	NotFoundURL     string `json:"not_found_url" binding:"omitempty,http_url,max=2048,excluded_with=NotFoundHTML"`
+	NotFoundHTML    string `json:"not_found_html" binding:"max=65536"`
+}
+
+type domainVerification struct {
+	TXTRecord string `json:"txt_record"`
+	TXTValue  string `json:"txt_value"`
+	HTTPURL   string `json:"http_url"`
+	HTTPBody  string `json:"http_body"`
+}
+
+type domainResponse struct {
+	ID           int64              `json:"id"`
+	Hostname     string             `json:"hostname"`
+	Verified     bool               `json:"verified"`
+	VerifiedAt   pgtype.Timestamptz `json:"verified_at"`
+	CreatedAt    time.Time          `json:"created_at"`
+	Verification domainVerification `json:"verification"`
+	Fallbacks    domainFallbacks    `json:"fallbacks"`
+}
+
+type domainFallbacks struct {
+	RootRedirectURL string `json:"root_redirect_url"`
+	NotFoundURL     string `json:"not_found_url"`
+	NotFoundHTML    string `json:"not_found_html"`
+}
+
+func newDomainResponse(d db.Domain) domainResponse {
+	return domainResponse{
+		ID:         d.ID,
+		Hostname:   d.Hostname,
+		Verified:   d.VerifiedAt.Valid,
+		VerifiedAt: d.VerifiedAt,
+		CreatedAt:  d.CreatedAt,
+		Verification: domainVerification{
+			TXTRecord: domain.TXTRecord(d.Hostname),
+			TXTValue:  domain.TXTValue(d.VerificationToken),
+			HTTPURL:   domain.WellKnownURL(d.Hostname),
+			HTTPBody:  d.VerificationToken,
+		},
+		Fallbacks: domainFallbacks{
+			RootRedirectURL: d.RootRedirectUrl,
+			NotFoundURL:     d.NotFoundUrl,
+			NotFoundHTML:    d.NotFoundHtml,
+		},
+	}
+}
+
+func (server *Server) CreateDomain(ctx *gin.Context) {
+	var req createDomainParams
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	hostname := strings.ToLower(strings.TrimSuffix(req.Hostname, "."))
+	if hostname == server.defaultHostname() {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ErrDomainReserved, http.StatusBadRequest))
+		return
+	}
+
+	verificationToken, err := domain.NewToken()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.CreateDomainParams{
+		UserID:            authPayload.UserID,
+		Hostname:          hostname,
+		VerificationToken: verificationToken,
+	}
+
+	d, err := server.store.CreateDomain(ctx, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, successResponse(newDomainResponse(d), http.StatusCreated))
+}
+
+func (server *Server) GetDomains(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	domains, err := server.store.GetDomainsByUser(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	rsp := make([]domainResponse, len(domains))
+	for i, d := range domains {
+		rsp[i] = newDomainResponse(d)
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp, http.StatusOK))
+}
+
+func (server *Server) VerifyDomain(ctx *gin.Context) {
+	var req verifyDomainParams
+
+	d, ok := server.ownedDomain(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	if err := server.domainVerifier.Verify(ctx, req.Method, d.Hostname, d.VerificationToken); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	d, err := server.store.MarkDomainVerified(ctx, d.ID)
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ErrDomainTaken, http.StatusBadRequest))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(newDomainResponse(d), http.StatusOK))
+}
+
+// SetDomainFallbacks replaces where visitors go on the domain's root and on codes that do not exist
+func (server *Server) SetDomainFallbacks(ctx *gin.Context) {
+	var req setDomainFallbacksParams
+
+	d, ok := server.ownedDomain(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	arg := db.SetDomainFallbacksParams{
+		ID:              d.ID,
+		RootRedirectUrl: req.RootRedirectURL,
+		NotFoundUrl:     req.NotFoundURL,
+		NotFoundHtml:    req.NotFoundHTML,
+	}
+
+	d, err := server.store.SetDomainFallbacks(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(newDomainResponse(d), http.StatusOK))
+}
+
+func (server *Server) DeleteDomain(ctx *gin.Context) {
+	d, ok := server.ownedDomain(ctx)
+	if !ok {
+		return
+	}
+
+	if err := server.store.DeleteDomain(ctx, d.ID); err != nil {
+		if db.ErrorCode(err) == db.ForeignKeyViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ErrDomainInUse, http.StatusBadRequest))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(nil, http.StatusOK))
+}
+
+// ownedDomain loads the domain named in the uri and writes the error response when the caller cannot see it
+func (server *Server) ownedDomain(ctx *gin.Context) (db.Domain, bool) {
+	var req getLinkByIDParams
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return db.Domain{}, false
+	}
+
+	d, err := server.store.GetDomain(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err, http.StatusNotFound))
+			return db.Domain{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return db.Domain{}, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if d.UserID != authPayload.UserID {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrDomainNotOwned, http.StatusForbidden))
+		return db.Domain{}, false
+	}
+
+	return d, true
+}
+
+// findLink resolves a code on the domain the request came in on. Hosts that are not a verified
+// custom domain serve the default domain's links.
+func (server *Server) findLink(ctx *gin.Context, code string) (db.Link, error) {
+	d, ok, err := server.requestDomain(ctx)
+	if err != nil {
+		return db.Link{}, err
+	}
+	if !ok {
+		return server.store.GetLinkByCode(ctx, code)
+	}
+
+	return server.store.GetLinkByDomainCode(ctx, db.GetLinkByDomainCodeParams{
+		DomainID: pgtype.Int8{Int64: d.ID, Valid: true},
+		Code:     code,
+	})
+}
+
+// requestDomain looks up the verified custom domain the request came in on and remembers it on ctx
+func (server *Server) requestDomain(ctx *gin.Context) (db.Domain, bool, error) {
+	hostname := requestHostname(ctx)
+	if hostname == "" || hostname == server.defaultHostname() {
+		return db.Domain{}, false, nil
+	}
+
+	d, err := server.store.GetVerifiedDomainByHostname(ctx, hostname)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return db.Domain{}, false, nil
+		}
+		return db.Domain{}, false, err
+	}
+
+	ctx.Set(requestDomainKey, d)
+
+	return d, true, nil
+}
+
+// linkDomain checks that the caller may create links on a domain
+func (server *Server) linkDomain(ctx *gin.Context, id int64) (pgtype.Int8, int, error) {
+	d, err := server.store.GetDomain(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			return pgtype.Int8{}, http.StatusNotFound, err
+		}
+		return pgtype.Int8{}, http.StatusInternalServerError, err
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if d.UserID != authPayload.UserID {
+		return pgtype.Int8{}, http.StatusForbidden, ErrDomainNotOwned
+	}
+
+	if !d.VerifiedAt.Valid {
+		return pgtype.Int8{}, http.StatusBadRequest, ErrDomainNotVerified
+	}
+
+	return pgtype.Int8{Int64: d.ID, Valid: true}, http.StatusOK, nil
+}
+
+// defaultHostname is the host of the configured base URL, empty when there is none
+func (server *Server) defaultHostname() string {
+	base, err := url.Parse(server.config.BaseURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(base.Hostname())
+}
+
+func requestHostname(ctx *gin.Context) string {
+	host := ctx.Request.Host
+	if hostname, _, err := net.SplitHostPort(host); err == nil {
+		host = hostname
+	}
+	return strings.ToLower(host)
+}
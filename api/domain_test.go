@@ -0,0 +1,661 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/domain"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeResolver map[string][]string
+
+func (resolver fakeResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	records, ok := resolver[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
+
+func createRandomDomain(userId int64) db.Domain {
+	return db.Domain{
+		ID:                util.RandomInt(1, 100),
+		UserID:            userId,
+		Hostname:          strings.ToLower(fmt.Sprintf("go.%s.com", util.RandomString(8))),
+		VerificationToken: util.RandomString(32),
+		CreatedAt:         time.Now(),
+	}
+}
+
+func TestCreateDomain(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	d := createRandomDomain(user.ID)
+
+	testCases := []struct {
+		name          string
+		payload       gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			payload: gin.H{"hostname": "Go.Acme.com."},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateDomain(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.CreateDomainParams) (db.Domain, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.Equal(t, "go.acme.com", arg.Hostname)
+						require.Len(t, arg.VerificationToken, 32)
+						d.Hostname = arg.Hostname
+						d.VerificationToken = arg.VerificationToken
+						return d, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var response Response[domainResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.False(t, response.Data.Verified)
+				require.Equal(t, "_urlmini-challenge.go.acme.com", response.Data.Verification.TXTRecord)
+				require.Equal(t, "urlmini-verification="+d.VerificationToken, response.Data.Verification.TXTValue)
+				require.Equal(t, "http://go.acme.com/.well-known/urlmini-verification", response.Data.Verification.HTTPURL)
+			},
+		},
+		{
+			name:    "DefaultDomain",
+			payload: gin.H{"hostname": "sho.rt"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateDomain(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "InvalidHostname",
+			payload: gin.H{"hostname": "not a host"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateDomain(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "AlreadyRegistered",
+			payload: gin.H{"hostname": d.Hostname},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateDomain(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Domain{}, db.ErrUniqueViolation)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.config.BaseURL = "https://sho.rt"
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/domains", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestVerifyDomain(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	d := createRandomDomain(user.ID)
+
+	testCases := []struct {
+		name          string
+		payload       gin.H
+		resolver      fakeResolver
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			payload:  gin.H{"method": domain.MethodDNS},
+			resolver: fakeResolver{domain.TXTRecord(d.Hostname): {domain.TXTValue(d.VerificationToken)}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetDomain(gomock.Any(), gomock.Eq(d.ID)).
+					Times(1).
+					Return(d, nil)
+
+				verified := d
+				verified.VerifiedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+				store.EXPECT().
+					MarkDomainVerified(gomock.Any(), gomock.Eq(d.ID)).
+					Times(1).
+					Return(verified, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response Response[domainResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.True(t, response.Data.Verified)
+			},
+		},
+		{
+			name:     "VerifiedByAnotherAccount",
+			payload:  gin.H{"method": domain.MethodDNS},
+			resolver: fakeResolver{domain.TXTRecord(d.Hostname): {domain.TXTValue(d.VerificationToken)}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetDomain(gomock.Any(), gomock.Eq(d.ID)).
+					Times(1).
+					Return(d, nil)
+
+				store.EXPECT().
+					MarkDomainVerified(gomock.Any(), gomock.Eq(d.ID)).
+					Times(1).
+					Return(db.Domain{}, db.ErrUniqueViolation)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "TokenMissing",
+			payload:  gin.H{"method": domain.MethodDNS},
+			resolver: fakeResolver{domain.TXTRecord(d.Hostname): {"urlmini-verification=stale"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetDomain(gomock.Any(), gomock.Eq(d.ID)).
+					Times(1).
+					Return(d, nil)
+
+				store.EXPECT().
+					MarkDomainVerified(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "InvalidMethod",
+			payload: gin.H{"method": "email"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetDomain(gomock.Any(), gomock.Eq(d.ID)).
+					Times(1).
+					Return(d, nil)
+
+				store.EXPECT().
+					MarkDomainVerified(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "NotOwned",
+			payload: gin.H{"method": domain.MethodDNS},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				other := d
+				other.UserID = user.ID + 1
+
+				store.EXPECT().
+					GetDomain(gomock.Any(), gomock.Eq(d.ID)).
+					Times(1).
+					Return(other, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.domainVerifier = domain.NewVerifier(domain.Options{Resolver: tc.resolver})
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, fmt.Sprintf("/domains/%d/verify", d.ID), bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteDomain(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	d := createRandomDomain(user.ID)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetDomain(gomock.Any(), gomock.Eq(d.ID)).
+					Times(1).
+					Return(d, nil)
+
+				store.EXPECT().
+					DeleteDomain(gomock.Any(), gomock.Eq(d.ID)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "StillHasLinks",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetDomain(gomock.Any(), gomock.Eq(d.ID)).
+					Times(1).
+					Return(d, nil)
+
+				store.EXPECT().
+					DeleteDomain(gomock.Any(), gomock.Eq(d.ID)).
+					Times(1).
+					Return(&pgconn.PgError{Code: db.ForeignKeyViolation})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/domains/%d", d.ID), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetLinkByCodeOnDomain(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	d := createRandomDomain(user.ID)
+	d.VerifiedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+	link := createRandomLink(user.ID)
+	link.DomainID = pgtype.Int8{Int64: d.ID, Valid: true}
+
+	testCases := []struct {
+		name          string
+		host          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "CustomDomain",
+			host: d.Hostname + ":443",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetVerifiedDomainByHostname(gomock.Any(), gomock.Eq(d.Hostname)).
+					Times(1).
+					Return(d, nil)
+
+				arg := db.GetLinkByDomainCodeParams{DomainID: link.DomainID, Code: link.Code}
+
+				store.EXPECT().
+					GetLinkByDomainCode(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				store.EXPECT().
+					RecordClick(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPermanentRedirect, recorder.Code)
+				require.Equal(t, link.Link, recorder.Header().Get("Location"))
+			},
+		},
+		{
+			name: "CodeOnOtherDomain",
+			host: d.Hostname,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetVerifiedDomainByHostname(gomock.Any(), gomock.Eq(d.Hostname)).
+					Times(1).
+					Return(d, nil)
+
+				store.EXPECT().
+					GetLinkByDomainCode(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Link{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "UnknownHost",
+			host: "unknown.example.org",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetVerifiedDomainByHostname(gomock.Any(), gomock.Eq("unknown.example.org")).
+					Times(1).
+					Return(db.Domain{}, db.ErrRecordNotFound)
+
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Eq(link.Code)).
+					Times(1).
+					Return(createRandomLink(user.ID), nil)
+
+				store.EXPECT().
+					RecordClick(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPermanentRedirect, recorder.Code)
+			},
+		},
+		{
+			name: "DefaultHost",
+			host: "sho.rt",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetVerifiedDomainByHostname(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Eq(link.Code)).
+					Times(1).
+					Return(createRandomLink(user.ID), nil)
+
+				store.EXPECT().
+					RecordClick(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPermanentRedirect, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			server.config.BaseURL = "https://sho.rt"
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/"+link.Code, nil)
+			require.NoError(t, err)
+			request.Host = tc.host
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSetDomainFallbacks(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	d := createRandomDomain(user.ID)
+
+	testCases := []struct {
+		name          string
+		payload       gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			payload: gin.H{
+				"root_redirect_url": "https://acme.com",
+				"not_found_html":    "<h1>Gone</h1>",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetDomain(gomock.Any(), gomock.Eq(d.ID)).
+					Times(1).
+					Return(d, nil)
+
+				arg := db.SetDomainFallbacksParams{
+					ID:              d.ID,
+					RootRedirectUrl: "https://acme.com",
+					NotFoundHtml:    "<h1>Gone</h1>",
+				}
+
+				updated := d
+				updated.RootRedirectUrl = arg.RootRedirectUrl
+				updated.NotFoundHtml = arg.NotFoundHtml
+
+				store.EXPECT().
+					SetDomainFallbacks(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(updated, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response Response[domainResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, "https://acme.com", response.Data.Fallbacks.RootRedirectURL)
+				require.Equal(t, "<h1>Gone</h1>", response.Data.Fallbacks.NotFoundHTML)
+			},
+		},
+		{
+			name: "UrlAndPage",
+			payload: gin.H{
+				"not_found_url":  "https://acme.com/missing",
+				"not_found_html": "<h1>Gone</h1>",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetDomain(gomock.Any(), gomock.Eq(d.ID)).
+					Times(1).
+					Return(d, nil)
+
+				store.EXPECT().
+					SetDomainFallbacks(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InvalidUrl",
+			payload: gin.H{
+				"root_redirect_url": "javascript:alert(1)",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetDomain(gomock.Any(), gomock.Eq(d.ID)).
+					Times(1).
+					Return(d, nil)
+
+				store.EXPECT().
+					SetDomainFallbacks(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, fmt.Sprintf("/domains/%d/fallbacks", d.ID), bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
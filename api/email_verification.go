@@ -0,0 +1,195 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/mail"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// emailVerificationPolicyRequired keeps users from creating links until their email is verified
+	emailVerificationPolicyRequired       = "required"
+	defaultEmailVerificationTokenDuration = 24 * time.Hour
+	// Verification emails are rationed per account, like password resets, so resending cannot flood an inbox
+	emailVerificationWindow             = time.Hour
+	emailVerificationRequestsPerAccount = 3
+
+	userTokenBytes = 32
+
+	mailerSMTP = "smtp"
+	mailerFile = "file"
+	// mailerLog prints emails, tokens and all, so it only suits development
+	mailerLog = "log"
+)
+
+var (
+	ErrEmailNotVerified        = errors.New("verify your email before creating links")
+	ErrEmailAlreadyVerified    = errors.New("email is already verified")
+	ErrInvalidVerificationLink = errors.New("verification token is invalid or has expired")
+)
+
+type verifyEmailRequest struct {
+	Token string `json:"token" binding:"required,max=100"`
+}
+
+// newMailer builds the mailer the config names. Leaving MAILER out falls back to the log mailer, with a warning,
+// so deployments that predate it keep starting, unless verification is required and users must get the email.
+func newMailer(config util.Config) (mail.Mailer, error) {
+	switch config.Mailer {
+	case "":
+		if config.EmailVerificationPolicy == emailVerificationPolicyRequired {
+			return nil, errors.New("email verification is required, set MAILER to smtp, file or log")
+		}
+		log.Println("MAILER is not set, emails and the tokens in them will only be logged")
+		return mail.NewLogMailer(config.MailFrom), nil
+	case mailerSMTP:
+		if config.SMTPHost == "" {
+			return nil, errors.New("smtp mailer needs SMTP_HOST")
+		}
+		return mail.NewSMTPMailer(mail.SMTPOptions{
+			Host:     config.SMTPHost,
+			Port:     config.SMTPPort,
+			Username: config.SMTPUsername,
+			Password: config.SMTPPassword,
+			From:     config.MailFrom,
+		}), nil
+	case mailerFile:
+		if config.MailFilePath == "" {
+			return nil, errors.New("file mailer needs MAIL_FILE_PATH")
+		}
+		return mail.NewFileMailer(config.MailFilePath, config.MailFrom), nil
+	case mailerLog:
+		return mail.NewLogMailer(config.MailFrom), nil
+	}
+	return nil, fmt.Errorf("unknown mailer %q, set MAILER to smtp, file or log", config.Mailer)
+}
+
+// VerifyEmail uses up the token from a verification email
+func (server *Server) VerifyEmail(ctx *gin.Context) {
+	var req verifyEmailRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	user, err := server.store.VerifyEmailTx(ctx, hashUserToken(req.Token))
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ErrInvalidVerificationLink, http.StatusBadRequest))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(newUserResponse(user), http.StatusOK))
+}
+
+// ResendEmailVerification emails the caller a fresh verification token, revoking the ones sent before
+func (server *Server) ResendEmailVerification(ctx *gin.Context) {
+	user := ctx.MustGet(authorizedUserKey).(db.User)
+
+	if user.EmailVerifiedAt.Valid {
+		ctx.JSON(http.StatusBadRequest, errorResponse(ErrEmailAlreadyVerified, http.StatusBadRequest))
+		return
+	}
+
+	recent, err := server.store.CountRecentUserTokens(ctx, db.CountRecentUserTokensParams{
+		UserID:    user.ID,
+		Purpose:   db.UserTokenEmailVerification,
+		CreatedAt: time.Now().Add(-emailVerificationWindow),
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+	if recent >= emailVerificationRequestsPerAccount {
+		ctx.JSON(http.StatusTooManyRequests, errorResponse(ErrTooManyRequests, http.StatusTooManyRequests))
+		return
+	}
+
+	if err := server.sendEmailVerification(ctx, user); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, successResponse(nil, http.StatusAccepted))
+}
+
+func (server *Server) sendEmailVerification(ctx context.Context, user db.User) error {
+	duration := server.config.EmailVerificationTokenDuration
+	if duration == 0 {
+		duration = defaultEmailVerificationTokenDuration
+	}
+
+	token, err := server.issueUserToken(ctx, user.ID, db.UserTokenEmailVerification, duration)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Hi %s,\n\nConfirm this is your email address to start creating short links:\n\n%s\n\nThis expires in %s. If you did not sign up, you can ignore this email.\n",
+		user.FirstName, userTokenLink(server.config.EmailVerificationURL, token), duration)
+
+	return server.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body:    body,
+	})
+}
+
+// issueUserToken revokes the user's outstanding tokens for purpose and stores the hash of a new one.
+// The token itself is only returned to be emailed.
+func (server *Server) issueUserToken(ctx context.Context, userID int64, purpose string, duration time.Duration) (string, error) {
+	if err := server.store.RevokeUserTokens(ctx, db.RevokeUserTokensParams{UserID: userID, Purpose: purpose}); err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, userTokenBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	token := base64.RawURLEncoding.EncodeToString(buf)
+
+	_, err := server.store.CreateUserToken(ctx, db.CreateUserTokenParams{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hashUserToken(token),
+		ExpiresAt: time.Now().Add(duration),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+func hashUserToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// userTokenLink appends the token to page, or hands back the bare token when there is no page to link to
+func userTokenLink(page, token string) string {
+	u, err := url.Parse(page)
+	if page == "" || err != nil {
+		return token
+	}
+
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
@@ -0,0 +1,359 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/mail"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type recordingMailer struct {
+	messages []mail.Message
+}
+
+func (m *recordingMailer) Send(_ context.Context, msg mail.Message) error {
+	m.messages = append(m.messages, msg)
+	return nil
+}
+
+func TestVerifyEmail(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	token := util.RandomString(43)
+
+	testCases := []struct {
+		name          string
+		payload       gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			payload: gin.H{"token": token},
+			buildStubs: func(store *mockdb.MockStore) {
+				verified := user
+				verified.EmailVerifiedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+				store.EXPECT().
+					VerifyEmailTx(gomock.Any(), gomock.Eq(hashUserToken(token))).
+					Times(1).
+					Return(verified, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response Response[userResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.True(t, response.Data.EmailVerified)
+			},
+		},
+		{
+			name:    "InvalidToken",
+			payload: gin.H{"token": token},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					VerifyEmailTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "MissingToken",
+			payload: gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					VerifyEmailTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "InternalError",
+			payload: gin.H{"token": token},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					VerifyEmailTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/email/verify", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestResendEmailVerification(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+
+	t.Run("OK", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		var storedHash string
+
+		store := mockdb.NewMockStore(ctrl)
+		store.EXPECT().
+			GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+			Times(1).
+			Return(user, nil)
+
+		store.EXPECT().
+			CountRecentUserTokens(gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(func(_ context.Context, arg db.CountRecentUserTokensParams) (int64, error) {
+				require.Equal(t, user.ID, arg.UserID)
+				require.Equal(t, db.UserTokenEmailVerification, arg.Purpose)
+				require.WithinDuration(t, time.Now().Add(-emailVerificationWindow), arg.CreatedAt, time.Minute)
+				return 1, nil
+			})
+
+		store.EXPECT().
+			RevokeUserTokens(gomock.Any(), gomock.Eq(db.RevokeUserTokensParams{UserID: user.ID, Purpose: db.UserTokenEmailVerification})).
+			Times(1).
+			Return(nil)
+
+		store.EXPECT().
+			CreateUserToken(gomock.Any(), gomock.Any()).
+			Times(1).
+			DoAndReturn(func(_ context.Context, arg db.CreateUserTokenParams) (db.UserToken, error) {
+				require.Equal(t, user.ID, arg.UserID)
+				require.Equal(t, db.UserTokenEmailVerification, arg.Purpose)
+				require.WithinDuration(t, time.Now().Add(defaultEmailVerificationTokenDuration), arg.ExpiresAt, time.Minute)
+				storedHash = arg.TokenHash
+				return db.UserToken{}, nil
+			})
+
+		server := newTestServer(t, store)
+		server.config.EmailVerificationURL = "https://app.example.com/verify"
+		mailer := &recordingMailer{}
+		server.mailer = mailer
+		recorder := httptest.NewRecorder()
+
+		request, err := http.NewRequest(http.MethodPost, "/users/me/email/verification", nil)
+		require.NoError(t, err)
+
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+
+		require.Equal(t, http.StatusAccepted, recorder.Code)
+		require.Len(t, mailer.messages, 1)
+		require.Equal(t, user.Email, mailer.messages[0].To)
+
+		// The emailed link carries the token whose hash was stored
+		var link *url.URL
+		for _, field := range bytes.Fields([]byte(mailer.messages[0].Body)) {
+			if bytes.HasPrefix(field, []byte("https://app.example.com/verify?")) {
+				link, err = url.Parse(string(field))
+				require.NoError(t, err)
+			}
+		}
+		require.NotNil(t, link)
+		require.Equal(t, storedHash, hashUserToken(link.Query().Get("token")))
+	})
+
+	t.Run("AlreadyVerified", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		verified := user
+		verified.EmailVerifiedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+		store := mockdb.NewMockStore(ctrl)
+		store.EXPECT().
+			GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+			Times(1).
+			Return(verified, nil)
+
+		store.EXPECT().
+			CreateUserToken(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		server := newTestServer(t, store)
+		recorder := httptest.NewRecorder()
+
+		request, err := http.NewRequest(http.MethodPost, "/users/me/email/verification", nil)
+		require.NoError(t, err)
+
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+
+		require.Equal(t, http.StatusBadRequest, recorder.Code)
+	})
+
+	t.Run("TooManyRequests", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		store := mockdb.NewMockStore(ctrl)
+		store.EXPECT().
+			GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+			Times(1).
+			Return(user, nil)
+
+		store.EXPECT().
+			CountRecentUserTokens(gomock.Any(), gomock.Any()).
+			Times(1).
+			Return(int64(emailVerificationRequestsPerAccount), nil)
+
+		store.EXPECT().
+			CreateUserToken(gomock.Any(), gomock.Any()).
+			Times(0)
+
+		server := newTestServer(t, store)
+		mailer := &recordingMailer{}
+		server.mailer = mailer
+		recorder := httptest.NewRecorder()
+
+		request, err := http.NewRequest(http.MethodPost, "/users/me/email/verification", nil)
+		require.NoError(t, err)
+
+		addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+		server.router.ServeHTTP(recorder, request)
+
+		require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+		require.Empty(t, mailer.messages)
+	})
+}
+
+func TestEmailVerificationPolicy(t *testing.T) {
+	unverified, _ := randomUser(t)
+	unverified.ID = util.RandomInt(1, 20)
+
+	verified := unverified
+	verified.EmailVerifiedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+
+	testCases := []struct {
+		name         string
+		policy       string
+		user         db.User
+		expectedCode int
+	}{
+		{
+			name:         "RequiredUnverified",
+			policy:       emailVerificationPolicyRequired,
+			user:         unverified,
+			expectedCode: http.StatusForbidden,
+		},
+		// Reaching the handler shows up as the empty body being rejected
+		{
+			name:         "RequiredVerified",
+			policy:       emailVerificationPolicyRequired,
+			user:         verified,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "Off",
+			user:         unverified,
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserById(gomock.Any(), gomock.Eq(tc.user.ID)).
+				Times(1).
+				Return(tc.user, nil)
+
+			store.EXPECT().
+				CreateLink(gomock.Any(), gomock.Any()).
+				Times(0)
+
+			config := util.Config{
+				TokenSymmetricKey:       util.RandomString(32),
+				AccessTokenDuration:     time.Minute,
+				Mailer:                  mailerLog,
+				EmailVerificationPolicy: tc.policy,
+			}
+
+			// The policy is read when the routes are set up
+			server, err := NewServer(store, config)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodPost, "/links", bytes.NewBufferString("{}"))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+
+			require.Equal(t, tc.expectedCode, recorder.Code)
+		})
+	}
+}
+
+func TestNewMailer(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config util.Config
+		ok     bool
+	}{
+		{name: "SMTP", config: util.Config{Mailer: mailerSMTP, SMTPHost: "smtp.example.com"}, ok: true},
+		{name: "SMTPWithoutHost", config: util.Config{Mailer: mailerSMTP}},
+		{name: "File", config: util.Config{Mailer: mailerFile, MailFilePath: "mail.log"}, ok: true},
+		{name: "FileWithoutPath", config: util.Config{Mailer: mailerFile}},
+		{name: "Log", config: util.Config{Mailer: mailerLog}, ok: true},
+		// Leaving it out keeps older deployments starting, unless users cannot do anything without the email
+		{name: "Missing", config: util.Config{}, ok: true},
+		{name: "MissingWithVerificationRequired", config: util.Config{EmailVerificationPolicy: emailVerificationPolicyRequired}},
+		{name: "Unknown", config: util.Config{Mailer: "sendmail"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mailer, err := newMailer(tc.config)
+			if tc.ok {
+				require.NoError(t, err)
+				require.NotNil(t, mailer)
+			} else {
+				require.Error(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,342 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	exportFormatCSV    = "csv"
+	exportFormatJSON   = "json"
+	exportFormatNDJSON = "ndjson"
+
+	// exportBatchSize is how many links are read and written at a time, bounding the memory an export uses
+	exportBatchSize = 500
+)
+
+type exportLinksParams struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv json ndjson"`
+	// Clicks adds click aggregates for every link, counted between ClickedAfter and ClickedBefore when set
+	Clicks        bool      `form:"clicks"`
+	ClickedAfter  time.Time `form:"clicked_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	ClickedBefore time.Time `form:"clicked_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	// Campaign only exports links whose destination carries this utm_campaign
+	Campaign string `form:"campaign" binding:"max=200"`
+}
+
+type exportClickStats struct {
+	Clicks       int64      `json:"clicks"`
+	Referrers    int64      `json:"unique_referrers"`
+	FirstClickAt *time.Time `json:"first_click_at"`
+	LastClickAt  *time.Time `json:"last_click_at"`
+}
+
+type exportLink struct {
+	ID         int64             `json:"id"`
+	Code       string            `json:"code"`
+	ShortURL   string            `json:"short_url"`
+	Link       string            `json:"link"`
+	Title      string            `json:"title"`
+	Notes      string            `json:"notes"`
+	Active     bool              `json:"active"`
+	CreatedAt  time.Time         `json:"created_at"`
+	ClickCount int64             `json:"click_count"`
+	Stats      *exportClickStats `json:"stats,omitempty"`
+}
+
+var exportCSVHeader = []string{"id", "code", "short_url", "link", "title", "notes", "active", "created_at", "click_count"}
+
+var exportCSVStatsHeader = []string{"clicks", "unique_referrers", "first_click_at", "last_click_at"}
+
+// ExportLinks streams all of the caller's links in batches so large accounts never sit in memory at once
+func (server *Server) ExportLinks(ctx *gin.Context) {
+	var req exportLinksParams
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	if req.Format == "" {
+		req.Format = exportFormatCSV
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.ExportLinksByUserParams{
+		UserID: authPayload.UserID,
+		Limit:  exportBatchSize,
+	}
+
+	if req.Campaign != "" {
+		arg.Campaign = pgtype.Text{String: req.Campaign, Valid: true}
+	}
+
+	statsArg := db.GetClickStatsByLinksParams{}
+
+	if !req.ClickedAfter.IsZero() {
+		statsArg.ClickedAfter = pgtype.Timestamptz{Time: req.ClickedAfter, Valid: true}
+	}
+
+	if !req.ClickedBefore.IsZero() {
+		statsArg.ClickedBefore = pgtype.Timestamptz{Time: req.ClickedBefore, Valid: true}
+	}
+
+	var out exportWriter
+
+	for {
+		rows, err := server.exportBatch(ctx, arg, statsArg, req.Clicks)
+		if err != nil {
+			if out == nil {
+				ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+				return
+			}
+			// The status line is already sent, leaving the body unterminated is all that is left to signal it
+			log.Printf("cannot export links for user %d: %v", authPayload.UserID, err)
+			return
+		}
+
+		if out == nil {
+			out = newExportWriter(ctx, req.Format, req.Clicks)
+		}
+
+		for _, row := range rows {
+			if err := out.Write(row); err != nil {
+				return
+			}
+		}
+
+		if len(rows) < exportBatchSize {
+			break
+		}
+
+		arg.AfterID = rows[len(rows)-1].ID
+		if err := out.Flush(); err != nil {
+			return
+		}
+		ctx.Writer.Flush()
+	}
+
+	if err := out.Close(); err != nil {
+		log.Printf("cannot finish export for user %d: %v", authPayload.UserID, err)
+	}
+}
+
+func (server *Server) exportBatch(ctx *gin.Context, arg db.ExportLinksByUserParams, statsArg db.GetClickStatsByLinksParams, withStats bool) ([]exportLink, error) {
+	links, err := server.store.ExportLinksByUser(ctx, arg)
+	if err != nil {
+		return nil, err
+	}
+
+	hostnames := make(map[int64]string)
+
+	rows := make([]exportLink, len(links))
+	for i, link := range links {
+		shortURL, err := server.linkShortURL(ctx, link, hostnames)
+		if err != nil {
+			return nil, err
+		}
+
+		rows[i] = exportLink{
+			ID:         link.ID,
+			Code:       link.Code,
+			ShortURL:   shortURL,
+			Link:       link.Link,
+			Title:      link.Title,
+			Notes:      link.Notes,
+			Active:     link.Active.Bool,
+			CreatedAt:  link.CreatedAt,
+			ClickCount: link.ClickCount,
+		}
+	}
+
+	if !withStats || len(links) == 0 {
+		return rows, nil
+	}
+
+	statsArg.LinkIds = make([]int64, len(links))
+	for i, link := range links {
+		statsArg.LinkIds[i] = link.ID
+	}
+
+	stats, err := server.store.GetClickStatsByLinks(ctx, statsArg)
+	if err != nil {
+		return nil, err
+	}
+
+	byLink := make(map[int64]db.GetClickStatsByLinksRow, len(stats))
+	for _, stat := range stats {
+		byLink[stat.LinkID] = stat
+	}
+
+	for i := range rows {
+		rows[i].Stats = &exportClickStats{}
+
+		// Links without clicks in the window have no row and keep zero counts
+		if stat, ok := byLink[rows[i].ID]; ok {
+			rows[i].Stats.Clicks = stat.Clicks
+			rows[i].Stats.Referrers = stat.Referrers
+			rows[i].Stats.FirstClickAt = &stat.FirstClickAt
+			rows[i].Stats.LastClickAt = &stat.LastClickAt
+		}
+	}
+
+	return rows, nil
+}
+
+type exportWriter interface {
+	Write(row exportLink) error
+	// Flush pushes buffered rows to the response between batches
+	Flush() error
+	Close() error
+}
+
+// newExportWriter sends the response headers and returns the encoder for the requested format
+func newExportWriter(ctx *gin.Context, format string, withStats bool) exportWriter {
+	filename := "links-" + time.Now().UTC().Format("20060102") + "." + format
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+
+	switch format {
+	case exportFormatJSON:
+		ctx.Header("Content-Type", "application/json; charset=utf-8")
+		ctx.Status(http.StatusOK)
+		return &jsonExportWriter{w: ctx.Writer, enc: json.NewEncoder(ctx.Writer)}
+	case exportFormatNDJSON:
+		ctx.Header("Content-Type", "application/x-ndjson")
+		ctx.Status(http.StatusOK)
+		return &ndjsonExportWriter{enc: json.NewEncoder(ctx.Writer)}
+	default:
+		ctx.Header("Content-Type", "text/csv; charset=utf-8")
+		ctx.Status(http.StatusOK)
+		return &csvExportWriter{w: csv.NewWriter(ctx.Writer), withStats: withStats}
+	}
+}
+
+type csvExportWriter struct {
+	w         *csv.Writer
+	withStats bool
+	started   bool
+}
+
+func (out *csvExportWriter) Write(row exportLink) error {
+	if err := out.writeHeader(); err != nil {
+		return err
+	}
+
+	record := []string{
+		strconv.FormatInt(row.ID, 10),
+		row.Code,
+		row.ShortURL,
+		row.Link,
+		row.Title,
+		row.Notes,
+		strconv.FormatBool(row.Active),
+		row.CreatedAt.UTC().Format(time.RFC3339),
+		strconv.FormatInt(row.ClickCount, 10),
+	}
+
+	if row.Stats != nil {
+		record = append(record,
+			strconv.FormatInt(row.Stats.Clicks, 10),
+			strconv.FormatInt(row.Stats.Referrers, 10),
+			formatExportTime(row.Stats.FirstClickAt),
+			formatExportTime(row.Stats.LastClickAt),
+		)
+	}
+
+	return out.w.Write(record)
+}
+
+func (out *csvExportWriter) Flush() error {
+	out.w.Flush()
+	return out.w.Error()
+}
+
+func (out *csvExportWriter) Close() error {
+	// An account without links still gets a header row
+	if err := out.writeHeader(); err != nil {
+		return err
+	}
+	return out.Flush()
+}
+
+func (out *csvExportWriter) writeHeader() error {
+	if out.started {
+		return nil
+	}
+	out.started = true
+
+	header := exportCSVHeader
+	if out.withStats {
+		header = append(header[:len(header):len(header)], exportCSVStatsHeader...)
+	}
+	return out.w.Write(header)
+}
+
+// jsonExportWriter writes a single array one element at a time
+type jsonExportWriter struct {
+	w       io.Writer
+	enc     *json.Encoder
+	started bool
+}
+
+func (out *jsonExportWriter) Write(row exportLink) error {
+	separator := ","
+	if !out.started {
+		out.started = true
+		separator = "["
+	}
+
+	if _, err := io.WriteString(out.w, separator); err != nil {
+		return err
+	}
+	return out.enc.Encode(row)
+}
+
+func (out *jsonExportWriter) Flush() error {
+	return nil
+}
+
+func (out *jsonExportWriter) Close() error {
+	closing := "]\n"
+	if !out.started {
+		closing = "[]\n"
+	}
+
+	_, err := io.WriteString(out.w, closing)
+	return err
+}
+
+type ndjsonExportWriter struct {
+	enc *json.Encoder
+}
+
+func (out *ndjsonExportWriter) Write(row exportLink) error {
+	return out.enc.Encode(row)
+}
+
+func (out *ndjsonExportWriter) Flush() error {
+	return nil
+}
+
+func (out *ndjsonExportWriter) Close() error {
+	return nil
+}
+
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
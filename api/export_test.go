@@ -0,0 +1,249 @@
+package api
+
+import (
+	"bufio"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportLinks(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+
+	links := []db.Link{createRandomLink(user.ID), createRandomLink(user.ID)}
+	links[1].ID = links[0].ID + 1
+	clickedAt := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+
+	batch := make([]db.Link, exportBatchSize)
+	for i := range batch {
+		batch[i] = createRandomLink(user.ID)
+		batch[i].ID = int64(i + 1)
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:  "CSV",
+			query: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExportLinksByUser(gomock.Any(), gomock.Eq(db.ExportLinksByUserParams{UserID: user.ID, Limit: exportBatchSize})).
+					Times(1).
+					Return(links, nil)
+
+				store.EXPECT().
+					GetClickStatsByLinks(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Header().Get("Content-Type"), "text/csv")
+				require.Contains(t, recorder.Header().Get("Content-Disposition"), "attachment")
+
+				records, err := csv.NewReader(recorder.Body).ReadAll()
+				require.NoError(t, err)
+				require.Len(t, records, 3)
+				require.Equal(t, exportCSVHeader, records[0])
+				require.Equal(t, links[0].Code, records[1][1])
+				require.Equal(t, links[1].Link, records[2][3])
+			},
+		},
+		{
+			name:  "CSVWithClicks",
+			query: "?clicks=true&clicked_after=2024-01-01T00:00:00Z&clicked_before=2024-02-01T00:00:00Z",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExportLinksByUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(links, nil)
+
+				store.EXPECT().
+					GetClickStatsByLinks(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.GetClickStatsByLinksParams) ([]db.GetClickStatsByLinksRow, error) {
+						require.Equal(t, []int64{links[0].ID, links[1].ID}, arg.LinkIds)
+						require.True(t, arg.ClickedAfter.Valid)
+						require.Equal(t, 2024, arg.ClickedBefore.Time.Year())
+						require.Equal(t, time.February, arg.ClickedBefore.Time.Month())
+
+						return []db.GetClickStatsByLinksRow{
+							{LinkID: links[1].ID, Clicks: 4, Referrers: 2, FirstClickAt: clickedAt, LastClickAt: clickedAt},
+						}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				records, err := csv.NewReader(recorder.Body).ReadAll()
+				require.NoError(t, err)
+				require.Len(t, records, 3)
+				require.Equal(t, append(exportCSVHeader, exportCSVStatsHeader...), records[0])
+
+				stats := len(exportCSVHeader)
+				require.Equal(t, []string{"0", "0", "", ""}, records[1][stats:])
+				require.Equal(t, []string{"4", "2", clickedAt.Format(time.RFC3339), clickedAt.Format(time.RFC3339)}, records[2][stats:])
+			},
+		},
+		{
+			name:  "JSON",
+			query: "?format=json",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExportLinksByUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(links, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Header().Get("Content-Type"), "application/json")
+
+				var rows []exportLink
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rows))
+				require.Len(t, rows, 2)
+				require.Equal(t, links[0].ID, rows[0].ID)
+				require.Nil(t, rows[0].Stats)
+			},
+		},
+		{
+			name:  "CustomDomain",
+			query: "?format=json",
+			buildStubs: func(store *mockdb.MockStore) {
+				onDomain := []db.Link{createRandomLink(user.ID), createRandomLink(user.ID), createRandomLink(user.ID)}
+				onDomain[0].DomainID = pgtype.Int8{Int64: 7, Valid: true}
+				onDomain[1].DomainID = pgtype.Int8{Int64: 7, Valid: true}
+
+				store.EXPECT().
+					ExportLinksByUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(onDomain, nil)
+
+				// Links sharing a domain only look it up once
+				store.EXPECT().
+					GetDomain(gomock.Any(), gomock.Eq(int64(7))).
+					Times(1).
+					Return(db.Domain{ID: 7, UserID: user.ID, Hostname: "go.acme.com"}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var rows []exportLink
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &rows))
+				require.Len(t, rows, 3)
+				require.Equal(t, "http://go.acme.com/"+rows[0].Code, rows[0].ShortURL)
+				require.Equal(t, "http://go.acme.com/"+rows[1].Code, rows[1].ShortURL)
+				require.NotContains(t, rows[2].ShortURL, "go.acme.com")
+			},
+		},
+		{
+			name:  "EmptyJSON",
+			query: "?format=json",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExportLinksByUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return([]db.Link{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "[]\n", recorder.Body.String())
+			},
+		},
+		{
+			name:  "NDJSONBatches",
+			query: "?format=ndjson",
+			buildStubs: func(store *mockdb.MockStore) {
+				first := store.EXPECT().
+					ExportLinksByUser(gomock.Any(), gomock.Eq(db.ExportLinksByUserParams{UserID: user.ID, Limit: exportBatchSize})).
+					Times(1).
+					Return(batch, nil)
+
+				store.EXPECT().
+					ExportLinksByUser(gomock.Any(), gomock.Eq(db.ExportLinksByUserParams{
+						UserID:  user.ID,
+						AfterID: batch[len(batch)-1].ID,
+						Limit:   exportBatchSize,
+					})).
+					After(first).
+					Times(1).
+					Return(links[:1], nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var n int
+				scanner := bufio.NewScanner(strings.NewReader(recorder.Body.String()))
+				for scanner.Scan() {
+					var row exportLink
+					require.NoError(t, json.Unmarshal(scanner.Bytes(), &row))
+					n++
+				}
+				require.Equal(t, exportBatchSize+1, n)
+			},
+		},
+		{
+			name:  "InvalidFormat",
+			query: "?format=xml",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExportLinksByUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalServerError",
+			query: "",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ExportLinksByUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+				Times(1).
+				Return(user, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/links/export"+tc.query, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package api
+
+import (
+	"errors"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+var ErrNoRootRedirect = errors.New("nothing is served at the root of this domain")
+
+// GetRoot sends visitors of a domain's root to its configured url, falling back to the instance's
+func (server *Server) GetRoot(ctx *gin.Context) {
+	d, ok, err := server.requestDomain(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	target := server.config.RootRedirectURL
+	if ok && d.RootRedirectUrl != "" {
+		target = d.RootRedirectUrl
+	}
+
+	if target == "" {
+		ctx.JSON(http.StatusNotFound, errorResponse(ErrNoRootRedirect, http.StatusNotFound))
+		return
+	}
+
+	ctx.Redirect(http.StatusFound, target)
+}
+
+// respondNotFound answers a visitor whose code does not resolve. The domain's fallback wins over the instance's,
+// and a redirect wins over a page; with neither configured it is the plain 404.
+func (server *Server) respondNotFound(ctx *gin.Context, err error) {
+	target, page := server.config.NotFoundURL, server.notFoundPage
+
+	if value, ok := ctx.Get(requestDomainKey); ok {
+		d := value.(db.Domain)
+		if d.NotFoundUrl != "" || d.NotFoundHtml != "" {
+			target, page = d.NotFoundUrl, []byte(d.NotFoundHtml)
+		}
+	}
+
+	switch {
+	case target != "":
+		ctx.Redirect(http.StatusFound, target)
+	case len(page) > 0:
+		ctx.Data(http.StatusNotFound, "text/html; charset=utf-8", page)
+	default:
+		ctx.JSON(http.StatusNotFound, errorResponse(err, http.StatusNotFound))
+	}
+}
@@ -0,0 +1,263 @@
+package api
+
+import (
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGetRoot(t *testing.T) {
+	d := createRandomDomain(util.RandomInt(1, 20))
+	d.VerifiedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	d.RootRedirectUrl = "https://acme.com/home"
+
+	bare := createRandomDomain(d.UserID)
+	bare.VerifiedAt = d.VerifiedAt
+
+	testCases := []struct {
+		name          string
+		host          string
+		config        util.Config
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "CustomDomain",
+			host: d.Hostname,
+			config: util.Config{
+				RootRedirectURL: "https://sho.rt/about",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetVerifiedDomainByHostname(gomock.Any(), gomock.Eq(d.Hostname)).
+					Times(1).
+					Return(d, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusFound, recorder.Code)
+				require.Equal(t, d.RootRedirectUrl, recorder.Header().Get("Location"))
+			},
+		},
+		{
+			name: "CustomDomainWithoutRoot",
+			host: bare.Hostname,
+			config: util.Config{
+				RootRedirectURL: "https://sho.rt/about",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetVerifiedDomainByHostname(gomock.Any(), gomock.Eq(bare.Hostname)).
+					Times(1).
+					Return(bare, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusFound, recorder.Code)
+				require.Equal(t, "https://sho.rt/about", recorder.Header().Get("Location"))
+			},
+		},
+		{
+			name: "DefaultDomain",
+			host: "sho.rt",
+			config: util.Config{
+				RootRedirectURL: "https://sho.rt/about",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetVerifiedDomainByHostname(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusFound, recorder.Code)
+				require.Equal(t, "https://sho.rt/about", recorder.Header().Get("Location"))
+			},
+		},
+		{
+			name: "NotConfigured",
+			host: "sho.rt",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetVerifiedDomainByHostname(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			config := tc.config
+			config.TokenSymmetricKey = util.RandomString(32)
+			config.BaseURL = "https://sho.rt"
+			config.Mailer = mailerLog
+
+			server, err := NewServer(store, config)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/", nil)
+			require.NoError(t, err)
+			request.Host = tc.host
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestNotFoundFallback(t *testing.T) {
+	d := createRandomDomain(util.RandomInt(1, 20))
+	d.VerifiedAt = pgtype.Timestamptz{Time: time.Now(), Valid: true}
+	d.NotFoundHtml = "<h1>Nothing at acme</h1>"
+
+	redirecting := createRandomDomain(d.UserID)
+	redirecting.VerifiedAt = d.VerifiedAt
+	redirecting.NotFoundUrl = "https://acme.com/missing"
+
+	pagePath := filepath.Join(t.TempDir(), "404.html")
+	require.NoError(t, os.WriteFile(pagePath, []byte("<h1>Nothing here</h1>"), 0o600))
+
+	code := util.RandomCode()
+
+	testCases := []struct {
+		name          string
+		host          string
+		config        util.Config
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "DomainPage",
+			host: d.Hostname,
+			config: util.Config{
+				NotFoundURL: "https://sho.rt/missing",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetVerifiedDomainByHostname(gomock.Any(), gomock.Eq(d.Hostname)).
+					Times(1).
+					Return(d, nil)
+
+				store.EXPECT().
+					GetLinkByDomainCode(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Link{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Header().Get("Content-Type"), "text/html")
+				require.Equal(t, d.NotFoundHtml, recorder.Body.String())
+			},
+		},
+		{
+			name: "DomainRedirect",
+			host: redirecting.Hostname,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetVerifiedDomainByHostname(gomock.Any(), gomock.Eq(redirecting.Hostname)).
+					Times(1).
+					Return(redirecting, nil)
+
+				store.EXPECT().
+					GetLinkByDomainCode(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Link{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusFound, recorder.Code)
+				require.Equal(t, redirecting.NotFoundUrl, recorder.Header().Get("Location"))
+			},
+		},
+		{
+			name: "DefaultRedirect",
+			host: "sho.rt",
+			config: util.Config{
+				NotFoundURL:      "https://sho.rt/missing",
+				NotFoundPagePath: pagePath,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Eq(code)).
+					Times(1).
+					Return(db.Link{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusFound, recorder.Code)
+				require.Equal(t, "https://sho.rt/missing", recorder.Header().Get("Location"))
+			},
+		},
+		{
+			name: "DefaultPage",
+			host: "sho.rt",
+			config: util.Config{
+				NotFoundPagePath: pagePath,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Eq(code)).
+					Times(1).
+					Return(db.Link{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Equal(t, "<h1>Nothing here</h1>", recorder.Body.String())
+			},
+		},
+		{
+			name: "NotConfigured",
+			host: "sho.rt",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Eq(code)).
+					Times(1).
+					Return(db.Link{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+				require.Contains(t, recorder.Header().Get("Content-Type"), "application/json")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			config := tc.config
+			config.TokenSymmetricKey = util.RandomString(32)
+			config.BaseURL = "https://sho.rt"
+			config.Mailer = mailerLog
+
+			server, err := NewServer(store, config)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/"+code, nil)
+			require.NoError(t, err)
+			request.Host = tc.host
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
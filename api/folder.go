@@ -0,0 +1,203 @@
+package api
+
+import (
+	"errors"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"net/http"
+)
+
+var ErrFolderNotOwned = errors.New("folder does not belong to this user")
+
+type folderNameParams struct {
+	Name string `json:"name" binding:"required,max=64"`
+}
+
+type folderIDParams struct {
+	ID int64 `uri:"id" binding:"required,number,min=1"`
+}
+
+func (server *Server) CreateFolder(ctx *gin.Context) {
+	var req folderNameParams
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.CreateFolderParams{
+		UserID: authPayload.UserID,
+		Name:   req.Name,
+	}
+
+	folder, err := server.store.CreateFolder(ctx, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, successResponse(folder, http.StatusCreated))
+}
+
+func (server *Server) GetFolders(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	folders, err := server.store.GetFoldersByUser(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(folders, http.StatusOK))
+}
+
+func (server *Server) RenameFolder(ctx *gin.Context) {
+	var req folderNameParams
+
+	folder, ok := server.ownedFolder(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	arg := db.UpdateFolderParams{
+		ID:   folder.ID,
+		Name: req.Name,
+	}
+
+	folder, err := server.store.UpdateFolder(ctx, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(folder, http.StatusOK))
+}
+
+// DeleteFolder removes the folder, its links stay in place without a folder
+func (server *Server) DeleteFolder(ctx *gin.Context) {
+	folder, ok := server.ownedFolder(ctx)
+	if !ok {
+		return
+	}
+
+	if err := server.store.DeleteFolder(ctx, folder.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(folder, http.StatusOK))
+}
+
+type setLinkFolderParams struct {
+	// FolderID is null to take the link out of its folder
+	FolderID *int64 `json:"folder_id" binding:"omitempty,min=1"`
+}
+
+func (server *Server) SetLinkFolder(ctx *gin.Context) {
+	var req setLinkFolderParams
+	var linkReq getLinkByIDParams
+
+	if err := ctx.ShouldBindUri(&linkReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	link, err := server.store.GetLinkById(ctx, linkReq.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err, http.StatusNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if link.UserID != authPayload.UserID {
+		ctx.JSON(http.StatusForbidden, errorResponse(err, http.StatusForbidden))
+		return
+	}
+
+	args := db.SetLinkFolderParams{
+		ID: link.ID,
+	}
+
+	if req.FolderID != nil {
+		folder, err := server.store.GetFolder(ctx, *req.FolderID)
+		if err != nil {
+			if errors.Is(err, db.ErrRecordNotFound) {
+				ctx.JSON(http.StatusNotFound, errorResponse(err, http.StatusNotFound))
+				return
+			}
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+			return
+		}
+
+		if folder.UserID != authPayload.UserID {
+			ctx.JSON(http.StatusForbidden, errorResponse(ErrFolderNotOwned, http.StatusForbidden))
+			return
+		}
+
+		args.FolderID = pgtype.Int8{Int64: folder.ID, Valid: true}
+	}
+
+	link, err = server.store.SetLinkFolder(ctx, args)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(link, http.StatusOK))
+}
+
+// ownedFolder loads the folder named in the uri and writes the error response when the caller cannot use it
+func (server *Server) ownedFolder(ctx *gin.Context) (db.Folder, bool) {
+	var req folderIDParams
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return db.Folder{}, false
+	}
+
+	folder, err := server.store.GetFolder(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err, http.StatusNotFound))
+			return db.Folder{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return db.Folder{}, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if folder.UserID != authPayload.UserID {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrFolderNotOwned, http.StatusForbidden))
+		return db.Folder{}, false
+	}
+
+	return folder, true
+}
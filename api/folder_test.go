@@ -0,0 +1,286 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func createRandomFolder(userId int64) db.Folder {
+	return db.Folder{
+		ID:        util.RandomInt(1, 100),
+		UserID:    userId,
+		Name:      util.RandomString(8),
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestSetLinkFolder(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	link := createRandomLink(user.ID)
+	folder := createRandomFolder(user.ID)
+	otherFolder := createRandomFolder(user.ID + 1)
+
+	testCases := []struct {
+		name          string
+		payload       gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			payload: gin.H{"folder_id": folder.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.SetLinkFolderParams{
+					ID:       link.ID,
+					FolderID: pgtype.Int8{Int64: folder.ID, Valid: true},
+				}
+
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					GetFolder(gomock.Any(), gomock.Eq(folder.ID)).
+					Times(1).
+					Return(folder, nil)
+
+				store.EXPECT().
+					SetLinkFolder(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(link, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "RemoveFromFolder",
+			payload: gin.H{"folder_id": nil},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					GetFolder(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				store.EXPECT().
+					SetLinkFolder(gomock.Any(), gomock.Eq(db.SetLinkFolderParams{ID: link.ID})).
+					Times(1).
+					Return(link, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "FolderNotOwned",
+			payload: gin.H{"folder_id": otherFolder.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					GetFolder(gomock.Any(), gomock.Eq(otherFolder.ID)).
+					Times(1).
+					Return(otherFolder, nil)
+
+				store.EXPECT().
+					SetLinkFolder(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:    "FolderNotFound",
+			payload: gin.H{"folder_id": folder.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					GetFolder(gomock.Any(), gomock.Eq(folder.ID)).
+					Times(1).
+					Return(db.Folder{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:    "InternalServerError",
+			payload: gin.H{"folder_id": folder.ID},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					GetFolder(gomock.Any(), gomock.Eq(folder.ID)).
+					Times(1).
+					Return(folder, nil)
+
+				store.EXPECT().
+					SetLinkFolder(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Link{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/links/%d/folder", link.ID)
+			request, err := http.NewRequest(http.MethodPatch, url, bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestDeleteFolder(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	folder := createRandomFolder(user.ID)
+
+	testCases := []struct {
+		name          string
+		userID        int64
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			userID: user.ID,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetFolder(gomock.Any(), gomock.Eq(folder.ID)).
+					Times(1).
+					Return(folder, nil)
+
+				store.EXPECT().
+					DeleteFolder(gomock.Any(), gomock.Eq(folder.ID)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "NotOwned",
+			userID: user.ID + 1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID+1)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetFolder(gomock.Any(), gomock.Eq(folder.ID)).
+					Times(1).
+					Return(folder, nil)
+
+				store.EXPECT().
+					DeleteFolder(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/folders/%d", folder.ID)
+			request, err := http.NewRequest(http.MethodDelete, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.userID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
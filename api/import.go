@@ -0,0 +1,210 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/importer"
+	"github.com/bolusarz/urlmini/token"
+	"github.com/gin-gonic/gin"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const maxImportSize = 10 << 20
+
+var (
+	ErrImportNotOwned = errors.New("import does not belong to this user")
+	ErrImportTooLarge = fmt.Errorf("import file is larger than %d bytes", maxImportSize)
+)
+
+type createImportParams struct {
+	File   *multipart.FileHeader `form:"file" binding:"required"`
+	Source string                `form:"source" binding:"required,oneof=bitly yourls"`
+	// Format defaults to the file extension
+	Format string `form:"format" binding:"omitempty,oneof=csv json"`
+}
+
+type importJobResponse struct {
+	ID        int64     `json:"id"`
+	Source    string    `json:"source"`
+	Format    string    `json:"format"`
+	Status    string    `json:"status"`
+	Total     int64     `json:"total"`
+	Processed int64     `json:"processed"`
+	Imported  int64     `json:"imported"`
+	Conflicts int64     `json:"conflicts"`
+	Error     string    `json:"error"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func newImportJobResponse(job db.ImportJob) importJobResponse {
+	return importJobResponse{
+		ID:        job.ID,
+		Source:    job.Source,
+		Format:    job.Format,
+		Status:    job.Status,
+		Total:     job.Total,
+		Processed: job.Processed,
+		Imported:  job.Imported,
+		Conflicts: job.Conflicts,
+		Error:     job.Error,
+		CreatedAt: job.CreatedAt,
+		UpdatedAt: job.UpdatedAt,
+	}
+}
+
+// CreateImport stores an export from another shortener and queues it for the import worker
+func (server *Server) CreateImport(ctx *gin.Context) {
+	var req createImportParams
+
+	if err := ctx.ShouldBind(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	if req.Format == "" {
+		req.Format = strings.TrimPrefix(strings.ToLower(filepath.Ext(req.File.Filename)), ".")
+	}
+
+	if req.File.Size > maxImportSize {
+		ctx.JSON(http.StatusRequestEntityTooLarge, errorResponse(ErrImportTooLarge, http.StatusRequestEntityTooLarge))
+		return
+	}
+
+	payload, err := readFormFile(req.File)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	// Reject unreadable files now rather than leaving the caller to poll a failed job
+	if _, err := importer.Parse(req.Source, req.Format, payload); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.CreateImportJobParams{
+		UserID:  authPayload.UserID,
+		Source:  req.Source,
+		Format:  req.Format,
+		Payload: payload,
+	}
+
+	job, err := server.store.CreateImportJob(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, successResponse(newImportJobResponse(job), http.StatusAccepted))
+}
+
+func (server *Server) GetImport(ctx *gin.Context) {
+	job, ok := server.ownedImport(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(newImportJobResponse(job), http.StatusOK))
+}
+
+type importConflictCursor struct {
+	Line int64 `json:"l"`
+}
+
+// GetImportConflicts lists the records that could not keep their code or were skipped, in file order
+func (server *Server) GetImportConflicts(ctx *gin.Context) {
+	var req pageParams
+	var cursor importConflictCursor
+
+	job, ok := server.ownedImport(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	if req.Cursor != "" {
+		if err := server.decodeCursor(req.Cursor, &cursor); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ErrInvalidCursor, http.StatusBadRequest))
+			return
+		}
+	}
+
+	pageSize := pageSizeOrDefault(req.PageSize)
+
+	arg := db.GetImportConflictsParams{
+		JobID:     job.ID,
+		AfterLine: cursor.Line,
+		Limit:     pageSize + 1,
+	}
+
+	conflicts, err := server.store.GetImportConflicts(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	conflicts, hasNext, _ := paginate(conflicts, pageSize, false, false)
+	page := pagination{Total: job.Conflicts}
+
+	if hasNext {
+		page.NextCursor, err = server.encodeCursor(importConflictCursor{Line: conflicts[len(conflicts)-1].Line})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, pageResponse(conflicts, page, http.StatusOK))
+}
+
+// ownedImport loads the import job named in the uri and writes the error response when the caller cannot see it
+func (server *Server) ownedImport(ctx *gin.Context) (db.ImportJob, bool) {
+	var req getLinkByIDParams
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return db.ImportJob{}, false
+	}
+
+	job, err := server.store.GetImportJob(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err, http.StatusNotFound))
+			return db.ImportJob{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return db.ImportJob{}, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if job.UserID != authPayload.UserID {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrImportNotOwned, http.StatusForbidden))
+		return db.ImportJob{}, false
+	}
+
+	return job, true
+}
+
+func readFormFile(header *multipart.FileHeader) ([]byte, error) {
+	file, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return io.ReadAll(io.LimitReader(file, maxImportSize))
+}
@@ -0,0 +1,311 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+const testBitlyExport = "bitlink,long_url,title\nbit.ly/abc,https://example.com,Example\n"
+
+func newImportRequest(t *testing.T, fields map[string]string, filename, content string) *http.Request {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+
+	for key, value := range fields {
+		require.NoError(t, writer.WriteField(key, value))
+	}
+
+	if filename != "" {
+		part, err := writer.CreateFormFile("file", filename)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, writer.Close())
+
+	request, err := http.NewRequest(http.MethodPost, "/imports", body)
+	require.NoError(t, err)
+	request.Header.Set("Content-Type", writer.FormDataContentType())
+	return request
+}
+
+func TestCreateImport(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+
+	job := db.ImportJob{
+		ID:      util.RandomInt(1, 100),
+		UserID:  user.ID,
+		Source:  "bitly",
+		Format:  "csv",
+		Payload: []byte(testBitlyExport),
+		Status:  "pending",
+	}
+
+	testCases := []struct {
+		name          string
+		fields        map[string]string
+		filename      string
+		content       string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:     "OK",
+			fields:   map[string]string{"source": "bitly"},
+			filename: "links.CSV",
+			content:  testBitlyExport,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateImportJob(gomock.Any(), gomock.Eq(db.CreateImportJobParams{
+						UserID:  user.ID,
+						Source:  "bitly",
+						Format:  "csv",
+						Payload: []byte(testBitlyExport),
+					})).
+					Times(1).
+					Return(job, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), "payload")
+
+				var response Response[importJobResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, job.ID, response.Data.ID)
+				require.Equal(t, "pending", response.Data.Status)
+			},
+		},
+		{
+			name:     "UnknownSource",
+			fields:   map[string]string{"source": "tinyurl"},
+			filename: "links.csv",
+			content:  testBitlyExport,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateImportJob(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "MissingFile",
+			fields: map[string]string{"source": "bitly", "format": "csv"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateImportJob(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "UnreadableFile",
+			fields:   map[string]string{"source": "yourls", "format": "json"},
+			filename: "export.txt",
+			content:  "not json",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateImportJob(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:     "InternalServerError",
+			fields:   map[string]string{"source": "bitly"},
+			filename: "links.csv",
+			content:  testBitlyExport,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateImportJob(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ImportJob{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+				Times(1).
+				Return(user, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request := newImportRequest(t, tc.fields, tc.filename, tc.content)
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetImportConflicts(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+
+	job := db.ImportJob{ID: util.RandomInt(1, 100), UserID: user.ID, Status: "completed", Conflicts: 3}
+	conflicts := []db.ImportConflict{
+		{ID: 1, JobID: job.ID, Line: 2, OriginalCode: "a1", Reason: "code format not supported"},
+		{ID: 2, JobID: job.ID, Line: 5, OriginalCode: "taken", Reason: db.ConflictCodeTaken},
+		{ID: 3, JobID: job.ID, Line: 9, OriginalCode: "b2", Reason: "code format not supported"},
+	}
+
+	testCases := []struct {
+		name          string
+		userID        int64
+		query         func(t *testing.T, server *Server) string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "FirstPage",
+			userID: user.ID,
+			query: func(t *testing.T, server *Server) string {
+				return "?page_size=2"
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetImportJob(gomock.Any(), gomock.Eq(job.ID)).
+					Times(1).
+					Return(job, nil)
+
+				store.EXPECT().
+					GetImportConflicts(gomock.Any(), gomock.Eq(db.GetImportConflictsParams{JobID: job.ID, Limit: 3})).
+					Times(1).
+					Return(conflicts, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response Response[[]db.ImportConflict]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Data, 2)
+				require.Equal(t, conflicts[0].OriginalCode, response.Data[0].OriginalCode)
+
+				page := requirePagination(t, recorder)
+				require.NotEmpty(t, page.NextCursor)
+				require.Equal(t, job.Conflicts, page.Total)
+			},
+		},
+		{
+			name:   "NextPage",
+			userID: user.ID,
+			query: func(t *testing.T, server *Server) string {
+				cursor, err := server.encodeCursor(importConflictCursor{Line: conflicts[1].Line})
+				require.NoError(t, err)
+				return "?page_size=2&cursor=" + cursor
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetImportJob(gomock.Any(), gomock.Eq(job.ID)).
+					Times(1).
+					Return(job, nil)
+
+				store.EXPECT().
+					GetImportConflicts(gomock.Any(), gomock.Eq(db.GetImportConflictsParams{
+						JobID:     job.ID,
+						AfterLine: conflicts[1].Line,
+						Limit:     3,
+					})).
+					Times(1).
+					Return(conflicts[2:], nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				page := requirePagination(t, recorder)
+				require.Empty(t, page.NextCursor)
+			},
+		},
+		{
+			name:   "NotOwner",
+			userID: user.ID + 1,
+			query: func(t *testing.T, server *Server) string {
+				return ""
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetImportJob(gomock.Any(), gomock.Eq(job.ID)).
+					Times(1).
+					Return(job, nil)
+
+				store.EXPECT().
+					GetImportConflicts(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "NotFound",
+			userID: user.ID,
+			query: func(t *testing.T, server *Server) string {
+				return ""
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetImportJob(gomock.Any(), gomock.Eq(job.ID)).
+					Times(1).
+					Return(db.ImportJob{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserById(gomock.Any(), gomock.Eq(tc.userID)).
+				Times(1).
+				Return(db.User{ID: tc.userID}, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/imports/%d/conflicts%s", job.ID, tc.query(t, server))
+			request, err := http.NewRequest(http.MethodGet, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, tc.userID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package api
+
+import (
+	"crypto/hmac"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/token"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// interstitialPolicyAll shows the interstitial for every link, whatever its own setting
+	interstitialPolicyAll        = "all"
+	defaultInterstitialCountdown = 5 * time.Second
+	// interstitialContinueKey carries the token that lets the interstitial's continue button through
+	interstitialContinueKey = "continue"
+	// interstitialContinueDuration is how long the continue button keeps working after the page is shown
+	interstitialContinueDuration = 10 * time.Minute
+)
+
+type interstitialPage struct {
+	Title       string
+	Host        string
+	Destination string
+	ContinueURL string
+	Countdown   int
+}
+
+// needsInterstitial reports whether a visitor should see the warning page before being redirected
+func (server *Server) needsInterstitial(ctx *gin.Context, link db.Link) bool {
+	if server.verifyContinueToken(ctx.Query(interstitialContinueKey), link.ID) {
+		return false
+	}
+	return link.Interstitial || server.config.InterstitialPolicy == interstitialPolicyAll
+}
+
+// renderInterstitial shows where the link leads; its continue button comes back through GetLinkByCode
+// so the click is recorded and the link's limits and redirect apply as usual
+func (server *Server) renderInterstitial(ctx *gin.Context, link db.Link) {
+	countdown := server.config.InterstitialCountdown
+	if countdown == 0 {
+		countdown = defaultInterstitialCountdown
+	}
+
+	host := link.Link
+	if destination, err := url.Parse(link.Link); err == nil && destination.Host != "" {
+		host = destination.Hostname()
+	}
+
+	// The request's own query is kept so a click through a bio page stays attributed to it
+	query := ctx.Request.URL.Query()
+	query.Set(interstitialContinueKey, server.continueToken(link.ID, time.Now().Add(interstitialContinueDuration)))
+
+	page := interstitialPage{
+		Title:       firstNonEmpty(link.Title, link.MetaTitle),
+		Host:        host,
+		Destination: link.Link,
+		ContinueURL: server.shortURL(ctx, ctx.Param("code")) + "?" + query.Encode(),
+		Countdown:   int(countdown.Seconds()),
+	}
+
+	// The page must not be cached in place of the redirect or framed to trick a visitor into continuing
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("X-Frame-Options", "DENY")
+	ctx.HTML(http.StatusOK, "interstitial.html", page)
+}
+
+// continueToken signs the link and an expiry, so a continue link cannot be made up to skip the warning
+// or reused on another link
+func (server *Server) continueToken(linkID int64, expiresAt time.Time) string {
+	expires := strconv.FormatInt(expiresAt.Unix(), 10)
+	return expires + "." + base64.RawURLEncoding.EncodeToString(server.signContinue(linkID, expires))
+}
+
+func (server *Server) verifyContinueToken(value string, linkID int64) bool {
+	expires, signature, ok := strings.Cut(value, ".")
+	if !ok {
+		return false
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return false
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	return err == nil && hmac.Equal(mac, server.signContinue(linkID, expires))
+}
+
+// signContinue shares the cursor key; the prefix keeps its signatures from ever matching a cursor's
+func (server *Server) signContinue(linkID int64, expires string) []byte {
+	return server.signCursor(fmt.Sprintf("interstitial:%d:%s", linkID, expires))
+}
+
+type setLinkInterstitialParams struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+func (server *Server) SetLinkInterstitial(ctx *gin.Context) {
+	var req setLinkInterstitialParams
+	var linkReq getLinkByIDParams
+
+	if err := ctx.ShouldBindUri(&linkReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	link, err := server.store.GetLinkById(ctx, linkReq.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err, http.StatusNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if link.UserID != authPayload.UserID {
+		ctx.JSON(http.StatusForbidden, errorResponse(err, http.StatusForbidden))
+		return
+	}
+
+	arg := db.SetLinkInterstitialParams{
+		ID:           link.ID,
+		Interstitial: *req.Enabled,
+	}
+
+	link, err = server.store.SetLinkInterstitial(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(link, http.StatusOK))
+}
@@ -0,0 +1,371 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestInterstitial(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+
+	link := createRandomLink(user.ID)
+	link.Link = "https://downloads.example.com/setup.exe"
+	link.Interstitial = true
+
+	plainLink := createRandomLink(user.ID)
+
+	key := util.RandomString(32)
+	signer := &Server{config: util.Config{TokenSymmetricKey: key}}
+	continueToken := signer.continueToken(link.ID, time.Now().Add(time.Minute))
+
+	testCases := []struct {
+		name          string
+		policy        string
+		url           string
+		userAgent     string
+		link          db.Link
+		buildStubs    func(store *mockdb.MockStore, link db.Link)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "Shown",
+			url:  "/" + link.Code,
+			link: link,
+			buildStubs: func(store *mockdb.MockStore, link db.Link) {
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Eq(link.Code)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					RecordClick(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "no-store", recorder.Header().Get("Cache-Control"))
+
+				body := recorder.Body.String()
+				require.Contains(t, body, "downloads.example.com")
+				require.Regexp(t, fmt.Sprintf(`href="http://example.com/%s\?continue=[0-9]+\.[A-Za-z0-9_-]+"`, link.Code), body)
+				require.Regexp(t, `var remaining = +3 *;`, body)
+			},
+		},
+		{
+			name: "Continue",
+			url:  "/" + link.Code + "?continue=" + continueToken,
+			link: link,
+			buildStubs: func(store *mockdb.MockStore, link db.Link) {
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Eq(link.Code)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					RecordClick(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPermanentRedirect, recorder.Code)
+				require.Equal(t, link.Link, recorder.Header().Get("Location"))
+			},
+		},
+		{
+			name: "ForgedContinue",
+			url:  "/" + link.Code + "?continue=1",
+			link: link,
+			buildStubs: func(store *mockdb.MockStore, link db.Link) {
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Eq(link.Code)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					RecordClick(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "ExpiredContinue",
+			url:  "/" + link.Code + "?continue=" + signer.continueToken(link.ID, time.Now().Add(-time.Minute)),
+			link: link,
+			buildStubs: func(store *mockdb.MockStore, link db.Link) {
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Eq(link.Code)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					RecordClick(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "ContinueForAnotherLink",
+			url:  "/" + link.Code + "?continue=" + signer.continueToken(link.ID+1, time.Now().Add(time.Minute)),
+			link: link,
+			buildStubs: func(store *mockdb.MockStore, link db.Link) {
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Eq(link.Code)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					RecordClick(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:      "Crawler",
+			url:       "/" + link.Code,
+			userAgent: "WhatsApp/2.23.20.0",
+			link:      link,
+			buildStubs: func(store *mockdb.MockStore, link db.Link) {
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Eq(link.Code)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					RecordClick(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				// The card still unfurls, but must not hand a spoofed crawler the way past the warning
+				body := recorder.Body.String()
+				require.Contains(t, body, "og:title")
+				require.NotContains(t, body, "http-equiv=\"refresh\"")
+				require.NotContains(t, body, "downloads.example.com")
+			},
+		},
+		{
+			name: "ThroughBioPage",
+			url:  fmt.Sprintf("/@jane?link=%d", link.ID),
+			link: link,
+			buildStubs: func(store *mockdb.MockStore, link db.Link) {
+				store.EXPECT().
+					GetBioPageBySlug(gomock.Any(), gomock.Eq("jane")).
+					Times(1).
+					Return(db.BioPage{ID: 3, UserID: link.UserID, Slug: "jane"}, nil)
+
+				store.EXPECT().
+					GetBioPageLink(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					RecordClick(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Regexp(t, fmt.Sprintf(`href="http://example.com/@jane\?continue=[^&"]+&amp;link=%d"`, link.ID), recorder.Body.String())
+			},
+		},
+		{
+			name: "NotEnabled",
+			url:  "/" + plainLink.Code,
+			link: plainLink,
+			buildStubs: func(store *mockdb.MockStore, link db.Link) {
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Eq(link.Code)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					RecordClick(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPermanentRedirect, recorder.Code)
+			},
+		},
+		{
+			name:   "GlobalPolicy",
+			policy: interstitialPolicyAll,
+			url:    "/" + plainLink.Code,
+			link:   plainLink,
+			buildStubs: func(store *mockdb.MockStore, link db.Link) {
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Eq(link.Code)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					RecordClick(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), "continue=")
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store, tc.link)
+
+			config := util.Config{
+				TokenSymmetricKey:     key,
+				AccessTokenDuration:   time.Minute,
+				BaseURL:               "http://example.com",
+				Mailer:                mailerLog,
+				InterstitialPolicy:    tc.policy,
+				InterstitialCountdown: 3 * time.Second,
+			}
+
+			server, err := NewServer(store, config)
+			require.NoError(t, err)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+			request.Host = "example.com"
+			if tc.userAgent != "" {
+				request.Header.Set("User-Agent", tc.userAgent)
+			}
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSetLinkInterstitial(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	link := createRandomLink(user.ID)
+
+	testCases := []struct {
+		name          string
+		payload       gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			payload: gin.H{"enabled": true},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(link, nil)
+
+				updated := link
+				updated.Interstitial = true
+
+				store.EXPECT().
+					SetLinkInterstitial(gomock.Any(), gomock.Eq(db.SetLinkInterstitialParams{ID: link.ID, Interstitial: true})).
+					Times(1).
+					Return(updated, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response Response[db.Link]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.True(t, response.Data.Interstitial)
+			},
+		},
+		{
+			name:    "MissingEnabled",
+			payload: gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					SetLinkInterstitial(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "NotOwned",
+			payload: gin.H{"enabled": false},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				other := link
+				other.UserID = user.ID + 1
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(other, nil)
+
+				store.EXPECT().
+					SetLinkInterstitial(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("/links/%d/interstitial", link.ID), bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package api
+
+import (
+	"errors"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/token"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrLinkExpired    = errors.New("link has expired")
+	ErrLinkClickLimit = errors.New("link has reached its click limit")
+)
+
+// setLinkLimitsParams replaces both limits; leaving one out removes it
+type setLinkLimitsParams struct {
+	ExpiresAt *time.Time `json:"expires_at"`
+	MaxClicks *int64     `json:"max_clicks" binding:"omitempty,min=1"`
+}
+
+func (server *Server) SetLinkLimits(ctx *gin.Context) {
+	var req setLinkLimitsParams
+	var linkReq getLinkByIDParams
+
+	if err := ctx.ShouldBindUri(&linkReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	link, err := server.store.GetLinkById(ctx, linkReq.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err, http.StatusNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if link.UserID != authPayload.UserID {
+		ctx.JSON(http.StatusForbidden, errorResponse(err, http.StatusForbidden))
+		return
+	}
+
+	arg := db.SetLinkLimitsParams{
+		ID: link.ID,
+	}
+
+	if req.ExpiresAt != nil {
+		arg.ExpiresAt = pgtype.Timestamptz{Time: *req.ExpiresAt, Valid: true}
+	}
+
+	if req.MaxClicks != nil {
+		arg.MaxClicks = pgtype.Int8{Int64: *req.MaxClicks, Valid: true}
+	}
+
+	link, err = server.store.SetLinkLimits(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(link, http.StatusOK))
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestSetLinkLimits(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	link := createRandomLink(user.ID)
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		payload       gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			payload: gin.H{"expires_at": expiresAt, "max_clicks": 100},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.SetLinkLimitsParams{
+					ID:        link.ID,
+					ExpiresAt: pgtype.Timestamptz{Time: expiresAt, Valid: true},
+					MaxClicks: pgtype.Int8{Int64: 100, Valid: true},
+				}
+
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					SetLinkLimits(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(link, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "ClearLimits",
+			payload: gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					SetLinkLimits(gomock.Any(), gomock.Eq(db.SetLinkLimitsParams{ID: link.ID})).
+					Times(1).
+					Return(link, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "InvalidMaxClicks",
+			payload: gin.H{"max_clicks": 0},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					SetLinkLimits(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "NotOwned",
+			payload: gin.H{"max_clicks": 10},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				other := link
+				other.UserID = user.ID + 1
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(other, nil)
+
+				store.EXPECT().
+					SetLinkLimits(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, fmt.Sprintf("/links/%d/limits", link.ID), bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
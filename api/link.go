@@ -1,17 +1,48 @@
 package api
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	db "github.com/bolusarz/urlmini/db/sqlc"
 	"github.com/bolusarz/urlmini/token"
 	"github.com/bolusarz/urlmini/util"
+	"github.com/bolusarz/urlmini/utm"
+	"github.com/bolusarz/urlmini/webhook"
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/jackc/pgx/v5/pgtype"
+	"log"
 	"net/http"
+	"strings"
+	"time"
 )
 
+var ErrLinkNotOwned = errors.New("link does not belong to this user")
+
 type createLinkParams struct {
-	Link string `json:"link" binding:"required"`
-	Code string `json:"code"`
+	Link  string `json:"link" binding:"required"`
+	Code  string `json:"code"`
+	Title string `json:"title" binding:"max=300"`
+	Notes string `json:"notes" binding:"max=2000"`
+
+	OgTitle       string `json:"og_title" binding:"max=300"`
+	OgDescription string `json:"og_description" binding:"max=1000"`
+	OgImage       string `json:"og_image" binding:"omitempty,http_url,max=2048"`
+
+	// ReuseExisting returns the caller's active link for the same destination instead of creating another,
+	// defaulting to the user's preference. A requested code always gets a new link.
+	ReuseExisting *bool `json:"reuse_existing"`
+
+	// DomainID puts the link on one of the caller's verified custom domains instead of the default one
+	DomainID *int64 `json:"domain_id" binding:"omitempty,min=1"`
+
+	// TemplateID adds the parameters of one of the caller's campaign templates to the destination
+	TemplateID *int64 `json:"template_id" binding:"omitempty,min=1"`
+
+	// Cloak serves the destination in a frame under the short URL, refused when the destination forbids framing
+	Cloak bool `json:"cloak"`
 }
 
 func (server *Server) CreateLink(ctx *gin.Context) {
@@ -22,21 +53,80 @@ func (server *Server) CreateLink(ctx *gin.Context) {
 		return
 	}
 
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	user := ctx.MustGet(authorizedUserKey).(db.User)
+
+	reuse := user.ReuseDuplicateLinks
+	if req.ReuseExisting != nil {
+		reuse = *req.ReuseExisting
+	}
+
+	var domainID pgtype.Int8
+	if req.DomainID != nil {
+		var status int
+		var err error
+
+		domainID, status, err = server.linkDomain(ctx, *req.DomainID)
+		if err != nil {
+			ctx.JSON(status, errorResponse(err, status))
+			return
+		}
+	}
+
+	if req.TemplateID != nil {
+		link, status, err := server.applyCampaignTemplate(ctx, *req.TemplateID, req.Link)
+		if err != nil {
+			ctx.JSON(status, errorResponse(err, status))
+			return
+		}
+		req.Link = link
+	}
+
+	if req.Cloak {
+		if err := server.cloakChecker.Check(ctx, req.Link); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+			return
+		}
+	}
+
+	if reuse && req.Code == "" && !domainID.Valid && !req.Cloak {
+		existing, err := server.store.GetActiveLinkByDestination(ctx, db.GetActiveLinkByDestinationParams{
+			UserID: authPayload.UserID,
+			Link:   req.Link,
+		})
+		if err == nil {
+			ctx.JSON(http.StatusOK, successResponse(existing, http.StatusOK))
+			return
+		}
+		if !errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
 	if req.Code == "" {
 		req.Code = util.RandomCode()
 	}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
-
 	arg := db.CreateLinkParams{
 		Code:   req.Code,
 		Link:   req.Link,
 		UserID: authPayload.UserID,
+		Title:  req.Title,
+		Notes:  req.Notes,
+
+		OgTitle:       req.OgTitle,
+		OgDescription: req.OgDescription,
+		OgImage:       req.OgImage,
+		DomainID:      domainID,
+		UtmCampaign:   utm.Campaign(req.Link),
+		Cloaked:       req.Cloak,
 	}
 
 	link, err := server.store.CreateLink(ctx, arg)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
 	}
 
 	ctx.JSON(http.StatusCreated, successResponse(link, http.StatusCreated))
@@ -48,17 +138,25 @@ type getLinkByCodeParams struct {
 }
 
 func (server *Server) GetLinkByCode(ctx *gin.Context) {
-	var req getLinkByCodeParams
+	// Bio pages share the root with codes; codes are alphabetic so the @ keeps them apart
+	if slug, ok := strings.CutPrefix(ctx.Param("code"), bioPagePrefix); ok {
+		server.serveBioPage(ctx, slug)
+		return
+	}
 
-	if err := ctx.ShouldBindUri(&req); err != nil {
+	// gin cannot route /:code+ next to /:code, so the preview suffix is split off here
+	code, preview := strings.CutSuffix(ctx.Param("code"), previewSuffix)
+	req := getLinkByCodeParams{Code: code}
+
+	if err := binding.Validator.ValidateStruct(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
 		return
 	}
 
-	link, err := server.store.GetLinkByCode(ctx, req.Code)
+	link, err := server.findLink(ctx, req.Code)
 	if err != nil {
 		if errors.Is(err, db.ErrRecordNotFound) {
-			ctx.JSON(http.StatusNotFound, errorResponse(err, http.StatusNotFound))
+			server.respondNotFound(ctx, err)
 			return
 		}
 		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
@@ -70,6 +168,71 @@ func (server *Server) GetLinkByCode(ctx *gin.Context) {
 		return
 	}
 
+	if preview {
+		server.renderPreview(ctx, link)
+		return
+	}
+
+	server.followLink(ctx, link, pgtype.Int8{})
+}
+
+// followLink sends a visitor on to an active link's destination, counting the click for the link
+// and, when it came through one, for the bio page
+func (server *Server) followLink(ctx *gin.Context, link db.Link, bioPageID pgtype.Int8) {
+	if link.ExpiresAt.Valid && !link.ExpiresAt.Time.After(time.Now()) {
+		ctx.JSON(http.StatusGone, errorResponse(ErrLinkExpired, http.StatusGone))
+		return
+	}
+
+	if link.MaxClicks.Valid && link.ClickCount >= link.MaxClicks.Int64 {
+		ctx.JSON(http.StatusGone, errorResponse(ErrLinkClickLimit, http.StatusGone))
+		return
+	}
+
+	interstitial := server.needsInterstitial(ctx, link)
+
+	// Anyone can claim to be a crawler, so the card only leads on to the destination when no interstitial stands in the way
+	if isCrawler(ctx.Request.UserAgent()) {
+		server.renderSocialCard(ctx, link, !interstitial)
+		return
+	}
+
+	if interstitial {
+		server.renderInterstitial(ctx, link)
+		return
+	}
+
+	arg := db.RecordClickParams{
+		LinkID:    link.ID,
+		Referrer:  ctx.Request.Referer(),
+		UserAgent: ctx.Request.UserAgent(),
+		BioPageID: bioPageID,
+	}
+
+	// A failed count must not keep the visitor from their destination
+	clicks, err := server.store.RecordClick(ctx, arg)
+	if err != nil {
+		log.Printf("cannot record click for link %d: %v", link.ID, err)
+	}
+
+	// Only the click that reaches the limit exactly announces it, so the event fires once
+	if err == nil && link.MaxClicks.Valid && clicks == link.MaxClicks.Int64 {
+		link.ClickCount = clicks
+		if err := webhook.Emit(ctx, server.store, link.UserID, webhook.EventLinkClickLimit, webhook.NewLinkData(link)); err != nil {
+			log.Printf("cannot queue click limit event for link %d: %v", link.ID, err)
+		}
+	}
+
+	if link.Cloaked {
+		server.renderCloak(ctx, link)
+		return
+	}
+
+	if link.HasPixels {
+		server.renderPixelPage(ctx, link)
+		return
+	}
+
 	ctx.Redirect(http.StatusPermanentRedirect, link.Link)
 
 }
@@ -108,33 +271,118 @@ func (server *Server) GetLinkById(ctx *gin.Context) {
 }
 
 type getLinksParams struct {
-	PageID   int32 `form:"page_id"`
-	PageSize int32 `form:"page_size"`
+	pageParams
+	TagID    int64 `form:"tag_id" binding:"omitempty,min=1"`
+	FolderID int64 `form:"folder_id" binding:"omitempty,min=1"`
+
+	// Search matches against the code, destination, title and notes
+	Search        string    `form:"q" binding:"max=200"`
+	Active        *bool     `form:"active"`
+	CreatedAfter  time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+	CreatedBefore time.Time `form:"created_before" time_format:"2006-01-02T15:04:05Z07:00"`
+	Domain        string    `form:"domain" binding:"omitempty,hostname"`
+	Campaign      string    `form:"campaign" binding:"max=200"`
+	Sort          string    `form:"sort" binding:"omitempty,oneof=created -created clicks -clicks code -code"`
+}
+
+// linkCursor holds the sort key of the link a page starts after, Backward pages walk towards newer rows
+type linkCursor struct {
+	Backward  bool      `json:"b,omitempty"`
+	Sort      string    `json:"s"`
+	ID        int64     `json:"i"`
+	CreatedAt time.Time `json:"t"`
+	Clicks    int64     `json:"n"`
+	Code      string    `json:"c"`
+	Filters   string    `json:"f"`
 }
 
-// TODO: Add the previous page id and the nextpage id
 func (server *Server) GetLinks(ctx *gin.Context) {
 	var req getLinksParams
+	var cursor linkCursor
 
 	if err := ctx.ShouldBindQuery(&req); err != nil {
 		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
 		return
 	}
 
-	if req.PageID == 0 {
-		req.PageID = 1
+	if req.Sort == "" {
+		req.Sort = "-created"
 	}
 
-	if req.PageSize == 0 {
-		req.PageSize = 10
+	pageSize := pageSizeOrDefault(req.PageSize)
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	filters := db.CountLinksByUserParams{
+		UserID: authPayload.UserID,
 	}
 
-	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+	if req.TagID != 0 {
+		filters.TagID = pgtype.Int8{Int64: req.TagID, Valid: true}
+	}
+
+	if req.FolderID != 0 {
+		filters.FolderID = pgtype.Int8{Int64: req.FolderID, Valid: true}
+	}
+
+	if req.Search != "" {
+		filters.Search = pgtype.Text{String: escapeLike(req.Search), Valid: true}
+	}
+
+	if req.Active != nil {
+		filters.Active = pgtype.Bool{Bool: *req.Active, Valid: true}
+	}
+
+	if !req.CreatedAfter.IsZero() {
+		filters.CreatedAfter = pgtype.Timestamptz{Time: req.CreatedAfter, Valid: true}
+	}
+
+	if !req.CreatedBefore.IsZero() {
+		filters.CreatedBefore = pgtype.Timestamptz{Time: req.CreatedBefore, Valid: true}
+	}
+
+	if req.Domain != "" {
+		filters.Domain = pgtype.Text{String: req.Domain, Valid: true}
+	}
+
+	if req.Campaign != "" {
+		filters.Campaign = pgtype.Text{String: req.Campaign, Valid: true}
+	}
+
+	filterKey := linkFilterKey(filters)
+
+	if req.Cursor != "" {
+		// A cursor is only meaningful for the ordering and filters it was issued under
+		err := server.decodeCursor(req.Cursor, &cursor)
+		if err != nil || cursor.Sort != req.Sort || cursor.Filters != filterKey {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ErrInvalidCursor, http.StatusBadRequest))
+			return
+		}
+	}
 
 	args := db.GetLinksByUserParams{
-		UserID: authPayload.UserID,
-		Offset: (req.PageID - 1) * req.PageSize,
-		Limit:  req.PageSize,
+		UserID:        filters.UserID,
+		FolderID:      filters.FolderID,
+		TagID:         filters.TagID,
+		Active:        filters.Active,
+		CreatedAfter:  filters.CreatedAfter,
+		CreatedBefore: filters.CreatedBefore,
+		Domain:        filters.Domain,
+		Search:        filters.Search,
+		Campaign:      filters.Campaign,
+		Sort:          req.Sort,
+		Limit:         pageSize + 1,
+	}
+
+	if req.Cursor != "" {
+		args.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+		args.CursorCreatedAt = pgtype.Timestamptz{Time: cursor.CreatedAt, Valid: true}
+		args.CursorClicks = pgtype.Int8{Int64: cursor.Clicks, Valid: true}
+		args.CursorCode = pgtype.Text{String: cursor.Code, Valid: true}
+
+		// Walking backward is walking forward under the opposite ordering
+		if cursor.Backward {
+			args.Sort = reverseSort(req.Sort)
+		}
 	}
 
 	links, err := server.store.GetLinksByUser(ctx, args)
@@ -143,8 +391,55 @@ func (server *Server) GetLinks(ctx *gin.Context) {
 		return
 	}
 
-	ctx.JSON(http.StatusOK, successResponse(links, http.StatusOK))
-	return
+	total, err := server.store.CountLinksByUser(ctx, filters)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	links, hasNext, hasPrev := paginate(links, pageSize, req.Cursor != "", cursor.Backward)
+	page := pagination{Total: total}
+
+	if len(links) > 0 {
+		if hasNext {
+			page.NextCursor, err = server.encodeCursor(newLinkCursor(links[len(links)-1], req.Sort, filterKey, false))
+		}
+		if err == nil && hasPrev {
+			page.PrevCursor, err = server.encodeCursor(newLinkCursor(links[0], req.Sort, filterKey, true))
+		}
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, pageResponse(links, page, http.StatusOK))
+}
+
+func newLinkCursor(link db.Link, sort, filterKey string, backward bool) linkCursor {
+	return linkCursor{
+		Backward:  backward,
+		Sort:      sort,
+		Filters:   filterKey,
+		ID:        link.ID,
+		CreatedAt: link.CreatedAt,
+		Clicks:    link.ClickCount,
+		Code:      link.Code,
+	}
+}
+
+// linkFilterKey fingerprints the filters of a listing so a cursor cannot be replayed against a different one
+func linkFilterKey(filters db.CountLinksByUserParams) string {
+	payload, _ := json.Marshal(filters)
+	sum := sha256.Sum256(payload)
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+func reverseSort(sort string) string {
+	if strings.HasPrefix(sort, "-") {
+		return sort[1:]
+	}
+	return "-" + sort
 }
 
 type toggleLinkStatusParams struct {
@@ -239,3 +534,142 @@ func (server *Server) ChangeCode(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, successResponse(link, http.StatusOK))
 	return
 }
+
+type updateLinkDetailsParams struct {
+	Title *string `json:"title" binding:"omitempty,max=300"`
+	Notes *string `json:"notes" binding:"omitempty,max=2000"`
+
+	OgTitle       *string `json:"og_title" binding:"omitempty,max=300"`
+	OgDescription *string `json:"og_description" binding:"omitempty,max=1000"`
+	OgImage       *string `json:"og_image" binding:"omitempty,max=2048,http_url|len=0"`
+}
+
+func (server *Server) UpdateLinkDetails(ctx *gin.Context) {
+	var req updateLinkDetailsParams
+	var linkReq getLinkByIDParams
+
+	if err := ctx.ShouldBindUri(&linkReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	link, err := server.store.GetLinkById(ctx, linkReq.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err, http.StatusNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if link.UserID != authPayload.UserID {
+		ctx.JSON(http.StatusForbidden, errorResponse(err, http.StatusForbidden))
+		return
+	}
+
+	args := db.UpdateLinkDetailsParams{
+		ID: link.ID,
+	}
+
+	if req.Title != nil {
+		args.Title = pgtype.Text{String: *req.Title, Valid: true}
+	}
+
+	if req.Notes != nil {
+		args.Notes = pgtype.Text{String: *req.Notes, Valid: true}
+	}
+
+	if req.OgTitle != nil {
+		args.OgTitle = pgtype.Text{String: *req.OgTitle, Valid: true}
+	}
+
+	if req.OgDescription != nil {
+		args.OgDescription = pgtype.Text{String: *req.OgDescription, Valid: true}
+	}
+
+	if req.OgImage != nil {
+		args.OgImage = pgtype.Text{String: *req.OgImage, Valid: true}
+	}
+
+	link, err = server.store.UpdateLinkDetails(ctx, args)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(link, http.StatusOK))
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike stops user search input from being read as ILIKE wildcards
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+type brokenLinkCursor struct {
+	ID int64 `json:"i"`
+}
+
+// GetBrokenLinks lists the caller's links whose destination failed its last health check, newest first
+func (server *Server) GetBrokenLinks(ctx *gin.Context) {
+	var req pageParams
+	var cursor brokenLinkCursor
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	if req.Cursor != "" {
+		if err := server.decodeCursor(req.Cursor, &cursor); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ErrInvalidCursor, http.StatusBadRequest))
+			return
+		}
+	}
+
+	pageSize := pageSizeOrDefault(req.PageSize)
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.GetBrokenLinksByUserParams{
+		UserID: authPayload.UserID,
+		Limit:  pageSize + 1,
+	}
+
+	if req.Cursor != "" {
+		arg.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+	}
+
+	links, err := server.store.GetBrokenLinksByUser(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	total, err := server.store.CountBrokenLinksByUser(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	links, hasNext, _ := paginate(links, pageSize, false, false)
+	page := pagination{Total: total}
+
+	if hasNext {
+		page.NextCursor, err = server.encodeCursor(brokenLinkCursor{ID: links[len(links)-1].ID})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, pageResponse(links, page, http.StatusOK))
+}
@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	db "github.com/bolusarz/urlmini/db/sqlc"
 	"github.com/bolusarz/urlmini/token"
 	"github.com/bolusarz/urlmini/util"
+	"github.com/bolusarz/urlmini/webhook"
 	"github.com/gin-gonic/gin"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
@@ -16,6 +18,8 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"strings"
 	"testing"
 	"time"
 )
@@ -76,21 +80,34 @@ func TestCreateLink(t *testing.T) {
 			},
 		},
 		{
-			name: "NoCodeProvided",
+			name: "CustomDomain",
 			payload: gin.H{
-				"link": link.Link,
+				"link":      link.Link,
+				"code":      link.Code,
+				"domain_id": 7,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
+				args := db.CreateLinkParams{
+					Code:     link.Code,
+					Link:     link.Link,
+					UserID:   link.UserID,
+					DomainID: pgtype.Int8{Int64: 7, Valid: true},
+				}
 				store.EXPECT().
 					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
 					Times(1).
 					Return(user, nil)
 
 				store.EXPECT().
-					CreateLink(gomock.Any(), gomock.Any()).
+					GetDomain(gomock.Any(), gomock.Eq(int64(7))).
+					Times(1).
+					Return(db.Domain{ID: 7, UserID: user.ID, VerifiedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}}, nil)
+
+				store.EXPECT().
+					CreateLink(gomock.Any(), gomock.Eq(args)).
 					Times(1).
 					Return(link, nil)
 			},
@@ -99,33 +116,52 @@ func TestCreateLink(t *testing.T) {
 			},
 		},
 		{
-			name: "NoLinkProvided",
+			name: "CampaignTemplate",
 			payload: gin.H{
-				"link": "",
-				"code": link.Code,
+				"link":        "https://example.com/shop?ref=home#top",
+				"code":        link.Code,
+				"template_id": 4,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
+				args := db.CreateLinkParams{
+					Code:        link.Code,
+					Link:        "https://example.com/shop?ref=home&utm_source=newsletter&utm_campaign=spring_sale&partner=acme#top",
+					UserID:      link.UserID,
+					UtmCampaign: "spring_sale",
+				}
 				store.EXPECT().
 					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
 					Times(1).
 					Return(user, nil)
 
 				store.EXPECT().
-					CreateLink(gomock.Any(), gomock.Any()).
-					Times(0)
+					GetCampaignTemplate(gomock.Any(), gomock.Eq(int64(4))).
+					Times(1).
+					Return(db.CampaignTemplate{
+						ID:          4,
+						UserID:      user.ID,
+						UtmSource:   "newsletter",
+						UtmCampaign: "spring_sale",
+						Params:      []byte(`{"partner":"acme"}`),
+					}, nil)
+
+				store.EXPECT().
+					CreateLink(gomock.Any(), gomock.Eq(args)).
+					Times(1).
+					Return(link, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Equal(t, http.StatusCreated, recorder.Code)
 			},
 		},
 		{
-			name: "InternalServerError",
+			name: "CampaignTemplateNotOwned",
 			payload: gin.H{
-				"link": link.Link,
-				"code": link.Code,
+				"link":        link.Link,
+				"template_id": 4,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
@@ -137,169 +173,125 @@ func TestCreateLink(t *testing.T) {
 					Return(user, nil)
 
 				store.EXPECT().
-					CreateLink(gomock.Any(), gomock.Any()).
+					GetCampaignTemplate(gomock.Any(), gomock.Eq(int64(4))).
 					Times(1).
-					Return(db.Link{}, sql.ErrConnDone)
-			},
-			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusInternalServerError, recorder.Code)
-			},
-		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			store := mockdb.NewMockStore(ctrl)
-			tc.buildStubs(store)
-
-			server := newTestServer(t, store)
-			recorder := httptest.NewRecorder()
-
-			jsonBody, err := json.Marshal(tc.payload)
-			require.NoError(t, err)
-
-			request, err := http.NewRequest(http.MethodPost, "/links", bytes.NewBuffer(jsonBody))
-			require.NoError(t, err)
-
-			tc.setupAuth(t, request, server.tokenMaker)
-
-			server.router.ServeHTTP(recorder, request)
-			tc.checkResponse(t, recorder)
-		})
-	}
-}
-
-func TestGetLinkByCode(t *testing.T) {
-	user, _ := randomUser(t)
-	user.ID = util.RandomInt(1, 20)
-	link := createRandomLink(user.ID)
-	inActiveLink := createRandomLink(user.ID)
-	inActiveLink.Active = pgtype.Bool{Bool: false, Valid: true}
+					Return(db.CampaignTemplate{ID: 4, UserID: user.ID + 1}, nil)
 
-	testCases := []struct {
-		name          string
-		payload       gin.H
-		buildStubs    func(store *mockdb.MockStore)
-		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
-	}{
-		{
-			name: "OK",
-			payload: gin.H{
-				"code": link.Code,
-			},
-			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetLinkByCode(gomock.Any(), gomock.Eq(link.Code)).
-					Times(1).
-					Return(link, nil)
+					CreateLink(gomock.Any(), gomock.Any()).
+					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, recorder.Code, http.StatusPermanentRedirect)
+				require.Equal(t, http.StatusForbidden, recorder.Code)
 			},
 		},
 		{
-			name: "CodeIsNotActive",
+			name: "DomainNotVerified",
 			payload: gin.H{
-				"code": inActiveLink.Code,
+				"link":      link.Link,
+				"domain_id": 7,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetLinkByCode(gomock.Any(), gomock.Eq(inActiveLink.Code)).
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
 					Times(1).
-					Return(inActiveLink, nil)
+					Return(user, nil)
+
+				store.EXPECT().
+					GetDomain(gomock.Any(), gomock.Eq(int64(7))).
+					Times(1).
+					Return(db.Domain{ID: 7, UserID: user.ID}, nil)
+
+				store.EXPECT().
+					CreateLink(gomock.Any(), gomock.Any()).
+					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, recorder.Code, http.StatusNotFound)
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		{
-			name: "CodeDoesNotExist",
+			name: "DomainNotOwned",
 			payload: gin.H{
-				"code": "notexists",
+				"link":      link.Link,
+				"domain_id": 7,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetLinkByCode(gomock.Any(), gomock.Any()).
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
 					Times(1).
-					Return(db.Link{}, db.ErrRecordNotFound)
+					Return(user, nil)
+
+				store.EXPECT().
+					GetDomain(gomock.Any(), gomock.Eq(int64(7))).
+					Times(1).
+					Return(db.Domain{ID: 7, UserID: user.ID + 1, VerifiedAt: pgtype.Timestamptz{Time: time.Now(), Valid: true}}, nil)
+
+				store.EXPECT().
+					CreateLink(gomock.Any(), gomock.Any()).
+					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, recorder.Code, http.StatusNotFound)
+				require.Equal(t, http.StatusForbidden, recorder.Code)
 			},
 		},
 		{
-			name: "BadRequest",
+			name: "NoCodeProvided",
 			payload: gin.H{
-				"code": "1234ha",
+				"link": link.Link,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetLinkByCode(gomock.Any(), gomock.Any()).
-					Times(0)
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateLink(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(link, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, http.StatusBadRequest, recorder.Code)
+				require.Equal(t, http.StatusCreated, recorder.Code)
 			},
 		},
 		{
-			name: "InternalServerError",
+			name: "NoLinkProvided",
 			payload: gin.H{
+				"link": "",
 				"code": link.Code,
 			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetLinkByCode(gomock.Any(), gomock.Eq(link.Code)).
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
 					Times(1).
-					Return(db.Link{}, sql.ErrConnDone)
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateLink(gomock.Any(), gomock.Any()).
+					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, recorder.Code, http.StatusInternalServerError)
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
-	}
-
-	for _, tc := range testCases {
-		t.Run(tc.name, func(t *testing.T) {
-			ctrl := gomock.NewController(t)
-			defer ctrl.Finish()
-
-			store := mockdb.NewMockStore(ctrl)
-			tc.buildStubs(store)
-
-			server := newTestServer(t, store)
-			recorder := httptest.NewRecorder()
-
-			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/%v", tc.payload["code"]), nil)
-			require.NoError(t, err)
-
-			server.router.ServeHTTP(recorder, request)
-			tc.checkResponse(t, recorder)
-
-		})
-	}
-
-}
-
-func TestGetLinkById(t *testing.T) {
-	user, _ := randomUser(t)
-	user.ID = util.RandomInt(1, 20)
-
-	link := createRandomLink(user.ID)
-
-	testCases := []struct {
-		name          string
-		payload       gin.H
-		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
-		buildStubs    func(store *mockdb.MockStore)
-		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
-	}{
 		{
-			name: "OK",
+			name: "ReuseExisting",
 			payload: gin.H{
-				"id": link.ID,
+				"link":           link.Link + "/",
+				"reuse_existing": true,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
@@ -311,65 +303,90 @@ func TestGetLinkById(t *testing.T) {
 					Return(user, nil)
 
 				store.EXPECT().
-					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					GetActiveLinkByDestination(gomock.Any(), gomock.Eq(db.GetActiveLinkByDestinationParams{
+						UserID: user.ID,
+						Link:   link.Link + "/",
+					})).
 					Times(1).
 					Return(link, nil)
+
+				store.EXPECT().
+					CreateLink(gomock.Any(), gomock.Any()).
+					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, recorder.Code, http.StatusOK)
+				require.Equal(t, http.StatusOK, recorder.Code)
 				requireBodyMatchLink(t, recorder.Body, link)
 			},
 		},
 		{
-			name: "NotFound",
+			name: "ReuseByUserDefaultNoMatch",
 			payload: gin.H{
-				"id": util.RandomInt(1, 1000000),
+				"link": link.Link,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
+				reusing := user
+				reusing.ReuseDuplicateLinks = true
+
 				store.EXPECT().
 					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
 					Times(1).
-					Return(user, nil)
+					Return(reusing, nil)
 
 				store.EXPECT().
-					GetLinkById(gomock.Any(), gomock.Any()).
+					GetActiveLinkByDestination(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(db.Link{}, db.ErrRecordNotFound)
+
+				store.EXPECT().
+					CreateLink(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(link, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, recorder.Code, http.StatusNotFound)
+				require.Equal(t, http.StatusCreated, recorder.Code)
 			},
 		},
 		{
-			name: "AccessForbidden",
+			name: "ReuseOverriddenByRequest",
 			payload: gin.H{
-				"id": link.ID,
+				"link":           link.Link,
+				"reuse_existing": false,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID+1, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
+				reusing := user
+				reusing.ReuseDuplicateLinks = true
+
 				store.EXPECT().
-					GetUserById(gomock.Any(), gomock.Eq(user.ID+1)).
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
 					Times(1).
-					Return(user, nil)
+					Return(reusing, nil)
 
 				store.EXPECT().
-					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					GetActiveLinkByDestination(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				store.EXPECT().
+					CreateLink(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(link, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, recorder.Code, http.StatusForbidden)
+				require.Equal(t, http.StatusCreated, recorder.Code)
 			},
 		},
 		{
-			name: "BadRequest",
+			name: "ReuseIgnoredForRequestedCode",
 			payload: gin.H{
-				"id": "notanumber",
+				"link":           link.Link,
+				"code":           link.Code,
+				"reuse_existing": true,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
@@ -381,17 +398,23 @@ func TestGetLinkById(t *testing.T) {
 					Return(user, nil)
 
 				store.EXPECT().
-					GetLinkById(gomock.Any(), gomock.Any()).
+					GetActiveLinkByDestination(gomock.Any(), gomock.Any()).
 					Times(0)
+
+				store.EXPECT().
+					CreateLink(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(link, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, recorder.Code, http.StatusBadRequest)
+				require.Equal(t, http.StatusCreated, recorder.Code)
 			},
 		},
 		{
 			name: "InternalServerError",
 			payload: gin.H{
-				"id": link.ID,
+				"link": link.Link,
+				"code": link.Code,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
@@ -403,12 +426,12 @@ func TestGetLinkById(t *testing.T) {
 					Return(user, nil)
 
 				store.EXPECT().
-					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					CreateLink(gomock.Any(), gomock.Any()).
 					Times(1).
 					Return(db.Link{}, sql.ErrConnDone)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, recorder.Code, http.StatusInternalServerError)
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
 			},
 		},
 	}
@@ -424,7 +447,10 @@ func TestGetLinkById(t *testing.T) {
 			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
-			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/links/%v", tc.payload["id"]), nil)
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/links", bytes.NewBuffer(jsonBody))
 			require.NoError(t, err)
 
 			tc.setupAuth(t, request, server.tokenMaker)
@@ -435,226 +461,190 @@ func TestGetLinkById(t *testing.T) {
 	}
 }
 
-func TestChangeCode(t *testing.T) {
+func TestGetLinkByCode(t *testing.T) {
 	user, _ := randomUser(t)
 	user.ID = util.RandomInt(1, 20)
-
 	link := createRandomLink(user.ID)
-	updatedLink := db.Link{
-		ID:        link.ID,
-		Link:      link.Link,
-		UserID:    link.UserID,
-		Code:      util.RandomCode(),
-		Active:    link.Active,
-		CreatedAt: link.CreatedAt,
-	}
+	inActiveLink := createRandomLink(user.ID)
+	inActiveLink.Active = pgtype.Bool{Bool: false, Valid: true}
+	expiredLink := createRandomLink(user.ID)
+	expiredLink.ExpiresAt = pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true}
+	exhaustedLink := createRandomLink(user.ID)
+	exhaustedLink.ClickCount = 5
+	exhaustedLink.MaxClicks = pgtype.Int8{Int64: 5, Valid: true}
+	limitedLink := createRandomLink(user.ID)
+	limitedLink.ClickCount = 4
+	limitedLink.MaxClicks = pgtype.Int8{Int64: 5, Valid: true}
+
 	testCases := []struct {
 		name          string
 		payload       gin.H
-		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
 			name: "OK",
 			payload: gin.H{
-				"id":   link.ID,
-				"code": updatedLink.Code,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+				"code": link.Code,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
-					Times(1).
-					Return(user, nil)
-
-				store.EXPECT().
-					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					GetLinkByCode(gomock.Any(), gomock.Eq(link.Code)).
 					Times(1).
 					Return(link, nil)
 
-				args := db.UpdateCodeParams{
-					ID:   link.ID,
-					Code: updatedLink.Code,
-				}
-
 				store.EXPECT().
-					UpdateCode(gomock.Any(), gomock.Eq(args)).
+					RecordClick(gomock.Any(), gomock.Eq(db.RecordClickParams{LinkID: link.ID})).
 					Times(1).
-					Return(updatedLink, nil)
+					Return(int64(1), nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, recorder.Code, http.StatusOK)
-				requireBodyMatchLink(t, recorder.Body, updatedLink)
+				require.Equal(t, recorder.Code, http.StatusPermanentRedirect)
 			},
 		},
 		{
-			name: "UserDoesNotOwnLink",
+			name: "CodeIsNotActive",
 			payload: gin.H{
-				"id":   link.ID,
-				"code": updatedLink.Code,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID+1, time.Minute)
+				"code": inActiveLink.Code,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetUserById(gomock.Any(), gomock.Eq(user.ID+1)).
-					Times(1).
-					Return(user, nil)
-
-				store.EXPECT().
-					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					GetLinkByCode(gomock.Any(), gomock.Eq(inActiveLink.Code)).
 					Times(1).
-					Return(link, nil)
+					Return(inActiveLink, nil)
 
 				store.EXPECT().
-					UpdateCode(gomock.Any(), gomock.Any()).
+					RecordClick(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, recorder.Code, http.StatusForbidden)
+				require.Equal(t, recorder.Code, http.StatusNotFound)
 			},
 		},
 		{
-			name: "LinkDoesNotExist",
+			name: "Expired",
 			payload: gin.H{
-				"id":   link.ID,
-				"code": updatedLink.Code,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+				"code": expiredLink.Code,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
-					Times(1).
-					Return(user, nil)
-
-				store.EXPECT().
-					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					GetLinkByCode(gomock.Any(), gomock.Eq(expiredLink.Code)).
 					Times(1).
-					Return(db.Link{}, db.ErrRecordNotFound)
+					Return(expiredLink, nil)
 
 				store.EXPECT().
-					UpdateCode(gomock.Any(), gomock.Any()).
+					RecordClick(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, recorder.Code, http.StatusNotFound)
+				require.Equal(t, http.StatusGone, recorder.Code)
 			},
 		},
 		{
-			name: "InvalidID",
+			name: "ClickLimitExhausted",
 			payload: gin.H{
-				"id":   "notANumber",
-				"code": updatedLink.Code,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+				"code": exhaustedLink.Code,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					GetLinkByCode(gomock.Any(), gomock.Eq(exhaustedLink.Code)).
 					Times(1).
-					Return(user, nil)
-
-				store.EXPECT().
-					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
-					Times(0)
+					Return(exhaustedLink, nil)
 
 				store.EXPECT().
-					UpdateCode(gomock.Any(), gomock.Any()).
+					RecordClick(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, recorder.Code, http.StatusBadRequest)
+				require.Equal(t, http.StatusGone, recorder.Code)
 			},
 		},
 		{
-			name: "InvalidCode",
+			name: "ClickLimitReached",
 			payload: gin.H{
-				"id":   link.ID,
-				"code": "&**yuh12",
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+				"code": limitedLink.Code,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					GetLinkByCode(gomock.Any(), gomock.Eq(limitedLink.Code)).
 					Times(1).
-					Return(user, nil)
+					Return(limitedLink, nil)
 
 				store.EXPECT().
-					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
-					Times(0)
+					RecordClick(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(5), nil)
 
 				store.EXPECT().
-					UpdateCode(gomock.Any(), gomock.Any()).
-					Times(0)
+					CreateWebhookDeliveries(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ context.Context, arg db.CreateWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+						require.Equal(t, limitedLink.UserID, arg.UserID)
+						require.Equal(t, webhook.EventLinkClickLimit, arg.Event)
+						return nil, nil
+					})
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, recorder.Code, http.StatusBadRequest)
+				require.Equal(t, http.StatusPermanentRedirect, recorder.Code)
 			},
 		},
 		{
-			name: "CouldNotFetchLink",
+			name: "CodeDoesNotExist",
 			payload: gin.H{
-				"id":   link.ID,
-				"code": updatedLink.Code,
+				"code": "notexists",
 			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Link{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, recorder.Code, http.StatusNotFound)
+			},
+		},
+		{
+			name: "ClickNotRecorded",
+			payload: gin.H{
+				"code": link.Code,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					GetLinkByCode(gomock.Any(), gomock.Eq(link.Code)).
 					Times(1).
-					Return(user, nil)
+					Return(link, nil)
 
 				store.EXPECT().
-					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					RecordClick(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(db.Link{}, sql.ErrConnDone)
-
+					Return(int64(0), sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPermanentRedirect, recorder.Code)
+			},
+		},
+		{
+			name: "BadRequest",
+			payload: gin.H{
+				"code": "1234ha",
+			},
+			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					UpdateCode(gomock.Any(), gomock.Any()).
+					GetLinkByCode(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, recorder.Code, http.StatusInternalServerError)
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
 			},
 		},
 		{
-			name: "CouldNotUpdateLink",
+			name: "InternalServerError",
 			payload: gin.H{
-				"id":   link.ID,
-				"code": updatedLink.Code,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+				"code": link.Code,
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
-					Times(1).
-					Return(user, nil)
-
-				store.EXPECT().
-					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
-					Times(1).
-					Return(link, nil)
-
-				args := db.UpdateCodeParams{
-					ID:   link.ID,
-					Code: updatedLink.Code,
-				}
-
-				store.EXPECT().
-					UpdateCode(gomock.Any(), gomock.Eq(args)).
+					GetLinkByCode(gomock.Any(), gomock.Eq(link.Code)).
 					Times(1).
 					Return(db.Link{}, sql.ErrConnDone)
 			},
@@ -675,40 +665,23 @@ func TestChangeCode(t *testing.T) {
 			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
-			jsonBody, err := json.Marshal(tc.payload)
-			require.NoError(t, err)
-
-			request, err := http.NewRequest(
-				http.MethodPatch,
-				fmt.Sprintf("/links/%v", tc.payload["id"]),
-				bytes.NewBuffer(jsonBody),
-			)
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/%v", tc.payload["code"]), nil)
 			require.NoError(t, err)
 
-			tc.setupAuth(t, request, server.tokenMaker)
-
 			server.router.ServeHTTP(recorder, request)
 			tc.checkResponse(t, recorder)
+
 		})
 	}
+
 }
 
-func TestToggleLinkStatus(t *testing.T) {
+func TestGetLinkById(t *testing.T) {
 	user, _ := randomUser(t)
 	user.ID = util.RandomInt(1, 20)
 
 	link := createRandomLink(user.ID)
-	updatedLink := db.Link{
-		ID:     link.ID,
-		Link:   link.Link,
-		UserID: link.UserID,
-		Code:   link.Code,
-		Active: pgtype.Bool{
-			Bool:  !link.Active.Bool,
-			Valid: true,
-		},
-		CreatedAt: link.CreatedAt,
-	}
+
 	testCases := []struct {
 		name          string
 		payload       gin.H
@@ -734,79 +707,62 @@ func TestToggleLinkStatus(t *testing.T) {
 					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
 					Times(1).
 					Return(link, nil)
-
-				args := db.ToggleStatusParams{
-					ID: link.ID,
-				}
-
-				store.EXPECT().
-					ToggleStatus(gomock.Any(), gomock.Eq(args)).
-					Times(1).
-					Return(updatedLink, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, recorder.Code, http.StatusOK)
-				requireBodyMatchLink(t, recorder.Body, updatedLink)
+				requireBodyMatchLink(t, recorder.Body, link)
 			},
 		},
 		{
-			name: "UserDoesNotOwnLink",
+			name: "NotFound",
 			payload: gin.H{
-				"id": link.ID,
+				"id": util.RandomInt(1, 1000000),
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID+1, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetUserById(gomock.Any(), gomock.Eq(user.ID+1)).
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
 					Times(1).
 					Return(user, nil)
 
 				store.EXPECT().
-					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					GetLinkById(gomock.Any(), gomock.Any()).
 					Times(1).
-					Return(link, nil)
-
-				store.EXPECT().
-					ToggleStatus(gomock.Any(), gomock.Any()).
-					Times(0)
+					Return(db.Link{}, db.ErrRecordNotFound)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, recorder.Code, http.StatusForbidden)
+				require.Equal(t, recorder.Code, http.StatusNotFound)
 			},
 		},
 		{
-			name: "LinkDoesNotExist",
+			name: "AccessForbidden",
 			payload: gin.H{
 				"id": link.ID,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID+1, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
-					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					GetUserById(gomock.Any(), gomock.Eq(user.ID+1)).
 					Times(1).
 					Return(user, nil)
 
 				store.EXPECT().
 					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
 					Times(1).
-					Return(db.Link{}, db.ErrRecordNotFound)
-
-				store.EXPECT().
-					ToggleStatus(gomock.Any(), gomock.Any()).
-					Times(0)
+					Return(link, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, recorder.Code, http.StatusNotFound)
+				require.Equal(t, recorder.Code, http.StatusForbidden)
 			},
 		},
 		{
-			name: "InvalidID",
+			name: "BadRequest",
 			payload: gin.H{
-				"id": "notANumber",
+				"id": "notanumber",
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
@@ -818,11 +774,7 @@ func TestToggleLinkStatus(t *testing.T) {
 					Return(user, nil)
 
 				store.EXPECT().
-					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
-					Times(0)
-
-				store.EXPECT().
-					ToggleStatus(gomock.Any(), gomock.Any()).
+					GetLinkById(gomock.Any(), gomock.Any()).
 					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
@@ -830,7 +782,7 @@ func TestToggleLinkStatus(t *testing.T) {
 			},
 		},
 		{
-			name: "CouldNotFetchLink",
+			name: "InternalServerError",
 			payload: gin.H{
 				"id": link.ID,
 			},
@@ -847,42 +799,6 @@ func TestToggleLinkStatus(t *testing.T) {
 					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
 					Times(1).
 					Return(db.Link{}, sql.ErrConnDone)
-
-				store.EXPECT().
-					ToggleStatus(gomock.Any(), gomock.Any()).
-					Times(0)
-			},
-			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, recorder.Code, http.StatusInternalServerError)
-			},
-		},
-		{
-			name: "CouldNotUpdateLink",
-			payload: gin.H{
-				"id": link.ID,
-			},
-			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
-				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
-			},
-			buildStubs: func(store *mockdb.MockStore) {
-				store.EXPECT().
-					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
-					Times(1).
-					Return(user, nil)
-
-				store.EXPECT().
-					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
-					Times(1).
-					Return(link, nil)
-
-				args := db.ToggleStatusParams{
-					ID: link.ID,
-				}
-
-				store.EXPECT().
-					ToggleStatus(gomock.Any(), gomock.Eq(args)).
-					Times(1).
-					Return(db.Link{}, sql.ErrConnDone)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, recorder.Code, http.StatusInternalServerError)
@@ -901,11 +817,7 @@ func TestToggleLinkStatus(t *testing.T) {
 			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
-			request, err := http.NewRequest(
-				http.MethodPatch,
-				fmt.Sprintf("/links/%v/toggle", tc.payload["id"]),
-				nil,
-			)
+			request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/links/%v", tc.payload["id"]), nil)
 			require.NoError(t, err)
 
 			tc.setupAuth(t, request, server.tokenMaker)
@@ -916,91 +828,205 @@ func TestToggleLinkStatus(t *testing.T) {
 	}
 }
 
-func TestGetLinks(t *testing.T) {
+func TestChangeCode(t *testing.T) {
 	user, _ := randomUser(t)
 	user.ID = util.RandomInt(1, 20)
 
-	n := 10
-	links := make([]db.Link, n)
-
-	for i := 0; i < n; i++ {
-		links[i] = createRandomLink(user.ID)
-	}
-
-	type Query struct {
-		PageID   int32
-		PageSize int32
+	link := createRandomLink(user.ID)
+	updatedLink := db.Link{
+		ID:        link.ID,
+		Link:      link.Link,
+		UserID:    link.UserID,
+		Code:      util.RandomCode(),
+		Active:    link.Active,
+		CreatedAt: link.CreatedAt,
 	}
-
 	testCases := []struct {
 		name          string
-		query         *Query
+		payload       gin.H
 		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
 		buildStubs    func(store *mockdb.MockStore)
 		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
 	}{
 		{
 			name: "OK",
-			query: &Query{
-				PageID:   1,
-				PageSize: 10,
+			payload: gin.H{
+				"id":   link.ID,
+				"code": updatedLink.Code,
 			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				args := db.GetLinksByUserParams{
-					UserID: user.ID,
-					Offset: 0,
-					Limit:  int32(n),
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(link, nil)
+
+				args := db.UpdateCodeParams{
+					ID:   link.ID,
+					Code: updatedLink.Code,
 				}
 
+				store.EXPECT().
+					UpdateCode(gomock.Any(), gomock.Eq(args)).
+					Times(1).
+					Return(updatedLink, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, recorder.Code, http.StatusOK)
+				requireBodyMatchLink(t, recorder.Body, updatedLink)
+			},
+		},
+		{
+			name: "UserDoesNotOwnLink",
+			payload: gin.H{
+				"id":   link.ID,
+				"code": updatedLink.Code,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID+1, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID+1)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					UpdateCode(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, recorder.Code, http.StatusForbidden)
+			},
+		},
+		{
+			name: "LinkDoesNotExist",
+			payload: gin.H{
+				"id":   link.ID,
+				"code": updatedLink.Code,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
 					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
 					Times(1).
 					Return(user, nil)
 
 				store.EXPECT().
-					GetLinksByUser(gomock.Any(), gomock.Eq(args)).
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
 					Times(1).
-					Return(links, nil)
+					Return(db.Link{}, db.ErrRecordNotFound)
+
+				store.EXPECT().
+					UpdateCode(gomock.Any(), gomock.Any()).
+					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, recorder.Code, http.StatusOK)
-				requireBodyMatchLinks(t, recorder.Body, links)
+				require.Equal(t, recorder.Code, http.StatusNotFound)
 			},
 		},
 		{
-			name:  "NoQueryPassed",
-			query: nil,
+			name: "InvalidID",
+			payload: gin.H{
+				"id":   "notANumber",
+				"code": updatedLink.Code,
+			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
 			},
 			buildStubs: func(store *mockdb.MockStore) {
-				args := db.GetLinksByUserParams{
-					UserID: user.ID,
-					Offset: 0,
-					Limit:  10,
-				}
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(0)
 
+				store.EXPECT().
+					UpdateCode(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, recorder.Code, http.StatusBadRequest)
+			},
+		},
+		{
+			name: "InvalidCode",
+			payload: gin.H{
+				"id":   link.ID,
+				"code": "&**yuh12",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
 				store.EXPECT().
 					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
 					Times(1).
 					Return(user, nil)
 
 				store.EXPECT().
-					GetLinksByUser(gomock.Any(), gomock.Eq(args)).
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(0)
+
+				store.EXPECT().
+					UpdateCode(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, recorder.Code, http.StatusBadRequest)
+			},
+		},
+		{
+			name: "CouldNotFetchLink",
+			payload: gin.H{
+				"id":   link.ID,
+				"code": updatedLink.Code,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
 					Times(1).
-					Return(links, nil)
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(db.Link{}, sql.ErrConnDone)
+
+				store.EXPECT().
+					UpdateCode(gomock.Any(), gomock.Any()).
+					Times(0)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
-				require.Equal(t, recorder.Code, http.StatusOK)
-				requireBodyMatchLinks(t, recorder.Body, links)
+				require.Equal(t, recorder.Code, http.StatusInternalServerError)
 			},
 		},
 		{
-			name:  "InternalServerError",
-			query: nil,
+			name: "CouldNotUpdateLink",
+			payload: gin.H{
+				"id":   link.ID,
+				"code": updatedLink.Code,
+			},
 			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
 				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
 			},
@@ -1011,9 +1037,19 @@ func TestGetLinks(t *testing.T) {
 					Return(user, nil)
 
 				store.EXPECT().
-					GetLinksByUser(gomock.Any(), gomock.Any()).
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
 					Times(1).
-					Return(nil, sql.ErrConnDone)
+					Return(link, nil)
+
+				args := db.UpdateCodeParams{
+					ID:   link.ID,
+					Code: updatedLink.Code,
+				}
+
+				store.EXPECT().
+					UpdateCode(gomock.Any(), gomock.Eq(args)).
+					Times(1).
+					Return(db.Link{}, sql.ErrConnDone)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, recorder.Code, http.StatusInternalServerError)
@@ -1032,38 +1068,937 @@ func TestGetLinks(t *testing.T) {
 			server := newTestServer(t, store)
 			recorder := httptest.NewRecorder()
 
-			request, err := http.NewRequest(http.MethodGet, "/links", nil)
+			jsonBody, err := json.Marshal(tc.payload)
 			require.NoError(t, err)
 
-			if tc.query != nil {
-				q := request.URL.Query()
-				q.Add("page_id", fmt.Sprintf("%d", tc.query.PageID))
-				q.Add("page_size", fmt.Sprintf("%d", tc.query.PageSize))
-				request.URL.RawQuery = q.Encode()
-			}
+			request, err := http.NewRequest(
+				http.MethodPatch,
+				fmt.Sprintf("/links/%v", tc.payload["id"]),
+				bytes.NewBuffer(jsonBody),
+			)
+			require.NoError(t, err)
 
 			tc.setupAuth(t, request, server.tokenMaker)
+
 			server.router.ServeHTTP(recorder, request)
 			tc.checkResponse(t, recorder)
 		})
 	}
 }
 
-func requireBodyMatchLink(t *testing.T, body *bytes.Buffer, link db.Link) {
-	data, err := io.ReadAll(body)
-	require.NoError(t, err)
-
-	var response map[string]interface{}
-	err = json.Unmarshal(data, &response)
-	require.NoError(t, err)
-
-	gotLink := response["data"].(map[string]interface{})
-
-	compareMapToLink(t, gotLink, link)
-}
+func TestToggleLinkStatus(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
 
-func requireBodyMatchLinks(t *testing.T, body *bytes.Buffer, links []db.Link) {
-	data, err := io.ReadAll(body)
+	link := createRandomLink(user.ID)
+	updatedLink := db.Link{
+		ID:     link.ID,
+		Link:   link.Link,
+		UserID: link.UserID,
+		Code:   link.Code,
+		Active: pgtype.Bool{
+			Bool:  !link.Active.Bool,
+			Valid: true,
+		},
+		CreatedAt: link.CreatedAt,
+	}
+	testCases := []struct {
+		name          string
+		payload       gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			payload: gin.H{
+				"id": link.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(link, nil)
+
+				args := db.ToggleStatusParams{
+					ID: link.ID,
+				}
+
+				store.EXPECT().
+					ToggleStatus(gomock.Any(), gomock.Eq(args)).
+					Times(1).
+					Return(updatedLink, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, recorder.Code, http.StatusOK)
+				requireBodyMatchLink(t, recorder.Body, updatedLink)
+			},
+		},
+		{
+			name: "UserDoesNotOwnLink",
+			payload: gin.H{
+				"id": link.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID+1, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID+1)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					ToggleStatus(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, recorder.Code, http.StatusForbidden)
+			},
+		},
+		{
+			name: "LinkDoesNotExist",
+			payload: gin.H{
+				"id": link.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(db.Link{}, db.ErrRecordNotFound)
+
+				store.EXPECT().
+					ToggleStatus(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, recorder.Code, http.StatusNotFound)
+			},
+		},
+		{
+			name: "InvalidID",
+			payload: gin.H{
+				"id": "notANumber",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(0)
+
+				store.EXPECT().
+					ToggleStatus(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, recorder.Code, http.StatusBadRequest)
+			},
+		},
+		{
+			name: "CouldNotFetchLink",
+			payload: gin.H{
+				"id": link.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(db.Link{}, sql.ErrConnDone)
+
+				store.EXPECT().
+					ToggleStatus(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, recorder.Code, http.StatusInternalServerError)
+			},
+		},
+		{
+			name: "CouldNotUpdateLink",
+			payload: gin.H{
+				"id": link.ID,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(link, nil)
+
+				args := db.ToggleStatusParams{
+					ID: link.ID,
+				}
+
+				store.EXPECT().
+					ToggleStatus(gomock.Any(), gomock.Eq(args)).
+					Times(1).
+					Return(db.Link{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, recorder.Code, http.StatusInternalServerError)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(
+				http.MethodPatch,
+				fmt.Sprintf("/links/%v/toggle", tc.payload["id"]),
+				nil,
+			)
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetLinks(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+
+	n := 10
+	links := make([]db.Link, n)
+
+	for i := 0; i < n; i++ {
+		links[i] = createRandomLink(user.ID)
+	}
+
+	unfiltered := linkFilterKey(db.CountLinksByUserParams{UserID: user.ID})
+
+	type Query struct {
+		PageSize int32
+		Sort     string
+		Search   string
+		Cursor   func(t *testing.T, server *Server) string
+	}
+
+	testCases := []struct {
+		name          string
+		query         *Query
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			query: &Query{
+				PageSize: 10,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				args := db.GetLinksByUserParams{
+					UserID: user.ID,
+					Sort:   "-created",
+					Limit:  int32(n) + 1,
+				}
+
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinksByUser(gomock.Any(), gomock.Eq(args)).
+					Times(1).
+					Return(links, nil)
+
+				store.EXPECT().
+					CountLinksByUser(gomock.Any(), gomock.Eq(db.CountLinksByUserParams{UserID: user.ID})).
+					Times(1).
+					Return(int64(n), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, recorder.Code, http.StatusOK)
+				requireBodyMatchLinks(t, recorder.Body, links)
+			},
+		},
+		{
+			name:  "NoQueryPassed",
+			query: nil,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				args := db.GetLinksByUserParams{
+					UserID: user.ID,
+					Sort:   "-created",
+					Limit:  11,
+				}
+
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinksByUser(gomock.Any(), gomock.Eq(args)).
+					Times(1).
+					Return(links, nil)
+
+				store.EXPECT().
+					CountLinksByUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(n), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, recorder.Code, http.StatusOK)
+
+				page := requirePagination(t, recorder)
+				require.Empty(t, page.NextCursor)
+				require.Empty(t, page.PrevCursor)
+				require.Equal(t, int64(n), page.Total)
+			},
+		},
+		{
+			name: "HasNextPage",
+			query: &Query{
+				PageSize: 5,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinksByUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(links[:6], nil)
+
+				store.EXPECT().
+					CountLinksByUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(n), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, recorder.Code, http.StatusOK)
+
+				page := requirePagination(t, recorder)
+				require.NotEmpty(t, page.NextCursor)
+				require.Empty(t, page.PrevCursor)
+			},
+		},
+		{
+			name: "FollowNextCursor",
+			query: &Query{
+				PageSize: 5,
+				Cursor: func(t *testing.T, server *Server) string {
+					cursor, err := server.encodeCursor(newLinkCursor(links[4], "-created", unfiltered, false))
+					require.NoError(t, err)
+					return cursor
+				},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				args := db.GetLinksByUserParams{
+					UserID:          user.ID,
+					Sort:            "-created",
+					CursorID:        pgtype.Int8{Int64: links[4].ID, Valid: true},
+					CursorCreatedAt: pgtype.Timestamptz{Time: links[4].CreatedAt, Valid: true},
+					CursorClicks:    pgtype.Int8{Int64: links[4].ClickCount, Valid: true},
+					CursorCode:      pgtype.Text{String: links[4].Code, Valid: true},
+					Limit:           6,
+				}
+
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinksByUser(gomock.Any(), linkCursorArgs(args)).
+					Times(1).
+					Return(links[5:], nil)
+
+				store.EXPECT().
+					CountLinksByUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(n), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, recorder.Code, http.StatusOK)
+
+				page := requirePagination(t, recorder)
+				require.Empty(t, page.NextCursor)
+				require.NotEmpty(t, page.PrevCursor)
+			},
+		},
+		{
+			name: "FollowPrevCursor",
+			query: &Query{
+				PageSize: 5,
+				Sort:     "code",
+				Cursor: func(t *testing.T, server *Server) string {
+					cursor, err := server.encodeCursor(newLinkCursor(links[5], "code", unfiltered, true))
+					require.NoError(t, err)
+					return cursor
+				},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				// The page before is fetched under the reversed order and flipped back
+				store.EXPECT().
+					GetLinksByUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.GetLinksByUserParams) ([]db.Link, error) {
+						require.Equal(t, "-code", arg.Sort)
+						return []db.Link{links[4], links[3], links[2], links[1], links[0]}, nil
+					})
+
+				store.EXPECT().
+					CountLinksByUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(n), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, recorder.Code, http.StatusOK)
+
+				var response Response[[]db.Link]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Data, 5)
+				require.Equal(t, links[0].ID, response.Data[0].ID)
+
+				page := requirePagination(t, recorder)
+				require.NotEmpty(t, page.NextCursor)
+				require.Empty(t, page.PrevCursor)
+			},
+		},
+		{
+			name: "TamperedCursor",
+			query: &Query{
+				Cursor: func(t *testing.T, server *Server) string {
+					cursor, err := server.encodeCursor(newLinkCursor(links[4], "-created", unfiltered, false))
+					require.NoError(t, err)
+
+					forged, err := server.encodeCursor(newLinkCursor(links[0], "-created", unfiltered, false))
+					require.NoError(t, err)
+
+					payload, _, _ := strings.Cut(forged, ".")
+					_, signature, _ := strings.Cut(cursor, ".")
+					return payload + "." + signature
+				},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinksByUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "CursorFromOtherSort",
+			query: &Query{
+				Sort: "clicks",
+				Cursor: func(t *testing.T, server *Server) string {
+					cursor, err := server.encodeCursor(newLinkCursor(links[4], "-created", unfiltered, false))
+					require.NoError(t, err)
+					return cursor
+				},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinksByUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "CursorFromOtherFilters",
+			query: &Query{
+				Search: "promo",
+				Cursor: func(t *testing.T, server *Server) string {
+					cursor, err := server.encodeCursor(newLinkCursor(links[4], "-created", unfiltered, false))
+					require.NoError(t, err)
+					return cursor
+				},
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinksByUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "PageSizeTooLarge",
+			query: &Query{
+				PageSize: 1000,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinksByUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InternalServerError",
+			query: nil,
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinksByUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, recorder.Code, http.StatusInternalServerError)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/links", nil)
+			require.NoError(t, err)
+
+			if tc.query != nil {
+				q := request.URL.Query()
+				if tc.query.PageSize != 0 {
+					q.Add("page_size", fmt.Sprintf("%d", tc.query.PageSize))
+				}
+				if tc.query.Sort != "" {
+					q.Add("sort", tc.query.Sort)
+				}
+				if tc.query.Search != "" {
+					q.Add("q", tc.query.Search)
+				}
+				if tc.query.Cursor != nil {
+					q.Add("cursor", tc.query.Cursor(t, server))
+				}
+				request.URL.RawQuery = q.Encode()
+			}
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+// linkCursorArgs matches listing params whose cursor time survived a JSON round trip
+func linkCursorArgs(expected db.GetLinksByUserParams) gomock.Matcher {
+	return gomock.Cond(func(x any) bool {
+		arg, ok := x.(db.GetLinksByUserParams)
+		if !ok || !arg.CursorCreatedAt.Time.Equal(expected.CursorCreatedAt.Time) {
+			return false
+		}
+		arg.CursorCreatedAt = expected.CursorCreatedAt
+		return arg == expected
+	})
+}
+
+func requirePagination(t *testing.T, recorder *httptest.ResponseRecorder) pagination {
+	var response struct {
+		Pagination pagination `json:"pagination"`
+	}
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	return response.Pagination
+}
+
+func TestGetLinksSearch(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	links := []db.Link{createRandomLink(user.ID)}
+
+	createdAfter := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	createdBefore := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name          string
+		query         url.Values
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "AllFilters",
+			query: url.Values{
+				"q":              {"50%_off"},
+				"active":         {"true"},
+				"created_after":  {createdAfter.Format(time.RFC3339)},
+				"created_before": {createdBefore.Format(time.RFC3339)},
+				"domain":         {"example.com"},
+				"campaign":       {"spring_sale"},
+				"sort":           {"-clicks"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				args := db.GetLinksByUserParams{
+					UserID:        user.ID,
+					Search:        pgtype.Text{String: `50\%\_off`, Valid: true},
+					Active:        pgtype.Bool{Bool: true, Valid: true},
+					CreatedAfter:  pgtype.Timestamptz{Time: createdAfter, Valid: true},
+					CreatedBefore: pgtype.Timestamptz{Time: createdBefore, Valid: true},
+					Domain:        pgtype.Text{String: "example.com", Valid: true},
+					Campaign:      pgtype.Text{String: "spring_sale", Valid: true},
+					Sort:          "-clicks",
+					Limit:         11,
+				}
+
+				store.EXPECT().
+					GetLinksByUser(gomock.Any(), gomock.Eq(args)).
+					Times(1).
+					Return(links, nil)
+
+				store.EXPECT().
+					CountLinksByUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(len(links)), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "InactiveOnly",
+			query: url.Values{"active": {"false"}, "sort": {"code"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				args := db.GetLinksByUserParams{
+					UserID: user.ID,
+					Active: pgtype.Bool{Bool: false, Valid: true},
+					Sort:   "code",
+					Limit:  11,
+				}
+
+				store.EXPECT().
+					GetLinksByUser(gomock.Any(), gomock.Eq(args)).
+					Times(1).
+					Return(links, nil)
+
+				store.EXPECT().
+					CountLinksByUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(len(links)), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidSort",
+			query: url.Values{"sort": {"id"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLinksByUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:  "InvalidCreatedAfter",
+			query: url.Values{"created_after": {"yesterday"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLinksByUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+				Times(1).
+				Return(user, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/links?"+tc.query.Encode(), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetBrokenLinks(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+
+	links := make([]db.Link, 3)
+	for i := range links {
+		links[i] = createRandomLink(user.ID)
+		links[i].ID = int64(30 - i)
+		links[i].HealthBroken = true
+		links[i].HealthStatus = http.StatusNotFound
+	}
+
+	testCases := []struct {
+		name          string
+		query         func(t *testing.T, server *Server) string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "FirstPage",
+			query: func(t *testing.T, server *Server) string {
+				return "?page_size=2"
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBrokenLinksByUser(gomock.Any(), gomock.Eq(db.GetBrokenLinksByUserParams{UserID: user.ID, Limit: 3})).
+					Times(1).
+					Return(links, nil)
+
+				store.EXPECT().
+					CountBrokenLinksByUser(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(int64(len(links)), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response Response[[]map[string]any]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Data, 2)
+				require.Equal(t, true, response.Data[0]["health_broken"])
+				require.Equal(t, float64(http.StatusNotFound), response.Data[0]["health_status"])
+
+				page := requirePagination(t, recorder)
+				require.NotEmpty(t, page.NextCursor)
+				require.Equal(t, int64(3), page.Total)
+			},
+		},
+		{
+			name: "NextPage",
+			query: func(t *testing.T, server *Server) string {
+				cursor, err := server.encodeCursor(brokenLinkCursor{ID: links[1].ID})
+				require.NoError(t, err)
+				return "?page_size=2&cursor=" + cursor
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBrokenLinksByUser(gomock.Any(), gomock.Eq(db.GetBrokenLinksByUserParams{
+						UserID:   user.ID,
+						CursorID: pgtype.Int8{Int64: links[1].ID, Valid: true},
+						Limit:    3,
+					})).
+					Times(1).
+					Return(links[2:], nil)
+
+				store.EXPECT().
+					CountBrokenLinksByUser(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(int64(len(links)), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				page := requirePagination(t, recorder)
+				require.Empty(t, page.NextCursor)
+			},
+		},
+		{
+			name: "InvalidCursor",
+			query: func(t *testing.T, server *Server) string {
+				return "?cursor=abc.def"
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBrokenLinksByUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalServerError",
+			query: func(t *testing.T, server *Server) string {
+				return ""
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetBrokenLinksByUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+				Times(1).
+				Return(user, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/links/broken"+tc.query(t, server), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func requireBodyMatchLink(t *testing.T, body *bytes.Buffer, link db.Link) {
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+
+	var response map[string]interface{}
+	err = json.Unmarshal(data, &response)
+	require.NoError(t, err)
+
+	gotLink := response["data"].(map[string]interface{})
+
+	compareMapToLink(t, gotLink, link)
+}
+
+func requireBodyMatchLinks(t *testing.T, body *bytes.Buffer, links []db.Link) {
+	data, err := io.ReadAll(body)
 	require.NoError(t, err)
 
 	var response map[string]interface{}
@@ -1081,6 +2016,286 @@ func requireBodyMatchLinks(t *testing.T, body *bytes.Buffer, links []db.Link) {
 func compareMapToLink(t *testing.T, payload map[string]any, link db.Link) {
 	require.Equal(t, link.Link, payload["link"])
 	require.Equal(t, link.Code, payload["code"])
+	require.Equal(t, link.Title, payload["title"])
 	require.Equal(t, link.UserID, int64(payload["user_id"].(float64)))
 	require.NotZero(t, payload["created_at"])
 }
+
+func TestUpdateLinkDetails(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+
+	link := createRandomLink(user.ID)
+	title := util.RandomString(12)
+	notes := util.RandomString(40)
+
+	updatedLink := link
+	updatedLink.Title = title
+	updatedLink.Notes = notes
+
+	testCases := []struct {
+		name          string
+		linkID        int64
+		payload       gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:   "OK",
+			linkID: link.ID,
+			payload: gin.H{
+				"title": title,
+				"notes": notes,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				args := db.UpdateLinkDetailsParams{
+					ID:    link.ID,
+					Title: pgtype.Text{String: title, Valid: true},
+					Notes: pgtype.Text{String: notes, Valid: true},
+				}
+
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					UpdateLinkDetails(gomock.Any(), gomock.Eq(args)).
+					Times(1).
+					Return(updatedLink, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchLink(t, recorder.Body, updatedLink)
+			},
+		},
+		{
+			name:   "OnlyNotes",
+			linkID: link.ID,
+			payload: gin.H{
+				"notes": notes,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				args := db.UpdateLinkDetailsParams{
+					ID:    link.ID,
+					Notes: pgtype.Text{String: notes, Valid: true},
+				}
+
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					UpdateLinkDetails(gomock.Any(), gomock.Eq(args)).
+					Times(1).
+					Return(updatedLink, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "ClearsPreviewImage",
+			linkID: link.ID,
+			payload: gin.H{
+				"og_image": "",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				args := db.UpdateLinkDetailsParams{
+					ID:      link.ID,
+					OgImage: pgtype.Text{String: "", Valid: true},
+				}
+
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					UpdateLinkDetails(gomock.Any(), gomock.Eq(args)).
+					Times(1).
+					Return(link, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:   "InvalidPreviewImage",
+			linkID: link.ID,
+			payload: gin.H{
+				"og_image": "javascript:alert(1)",
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					UpdateLinkDetails(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "TitleTooLong",
+			linkID: link.ID,
+			payload: gin.H{
+				"title": util.RandomString(301),
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					UpdateLinkDetails(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:   "AccessForbidden",
+			linkID: link.ID,
+			payload: gin.H{
+				"title": title,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID+1, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID+1)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					UpdateLinkDetails(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:   "NotFound",
+			linkID: link.ID,
+			payload: gin.H{
+				"title": title,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(db.Link{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name:   "InternalServerError",
+			linkID: link.ID,
+			payload: gin.H{
+				"title": title,
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					UpdateLinkDetails(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Link{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/links/%d/details", tc.linkID)
+			request, err := http.NewRequest(http.MethodPatch, url, bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
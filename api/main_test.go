@@ -14,6 +14,7 @@ func newTestServer(t *testing.T, store db.Store) *Server {
 	config := util.Config{
 		TokenSymmetricKey:   util.RandomString(32),
 		AccessTokenDuration: time.Minute,
+		Mailer:              mailerLog,
 	}
 
 	server, err := NewServer(store, config)
@@ -13,6 +13,7 @@ const (
 	authorizationHeaderKey  = "Authorization"
 	authorizationTypeBearer = "bearer"
 	authorizationPayloadKey = "authorization_payload"
+	authorizedUserKey       = "authorized_user"
 )
 
 func authMiddleware(tokenMaker token.Maker) gin.HandlerFunc {
@@ -57,10 +58,33 @@ func userExistsMiddleware(store db.Store) gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 		authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
 
-		_, err := store.GetUserById(ctx, authPayload.UserID)
+		user, err := store.GetUserById(ctx, authPayload.UserID)
 		if err != nil {
 			err := errors.New("auth: invalid token")
 			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err, http.StatusUnauthorized))
+			return
+		}
+
+		// Changing the password signs out every access token handed out before it
+		if authPayload.IssuedAt.Before(user.PasswordChangedAt.Time) {
+			err := errors.New("auth: token was issued before the password changed")
+			ctx.AbortWithStatusJSON(http.StatusUnauthorized, errorResponse(err, http.StatusUnauthorized))
+			return
+		}
+
+		ctx.Set(authorizedUserKey, user)
+		ctx.Next()
+	}
+}
+
+// verifiedEmailMiddleware turns away users whose email is not verified yet, when the policy asks for it
+func verifiedEmailMiddleware(policy string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		user := ctx.MustGet(authorizedUserKey).(db.User)
+
+		if policy == emailVerificationPolicyRequired && !user.EmailVerifiedAt.Valid {
+			ctx.AbortWithStatusJSON(http.StatusForbidden, errorResponse(ErrEmailNotVerified, http.StatusForbidden))
+			return
 		}
 
 		ctx.Next()
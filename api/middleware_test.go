@@ -6,6 +6,7 @@ import (
 	db "github.com/bolusarz/urlmini/db/sqlc"
 	"github.com/bolusarz/urlmini/token"
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"net/http"
@@ -147,6 +148,36 @@ func TestUserExistsMiddleware(t *testing.T) {
 				require.Equal(t, http.StatusUnauthorized, response.Code)
 			},
 		},
+		{
+			name: "TokenIssuedBeforePasswordChange",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{PasswordChangedAt: pgtype.Timestamp{Time: time.Now().Add(time.Second).UTC(), Valid: true}}, nil)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, 1, time.Minute)
+			},
+			checkResponse: func(t *testing.T, response *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusUnauthorized, response.Code)
+			},
+		},
+		{
+			name: "TokenIssuedAfterPasswordChange",
+			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{PasswordChangedAt: pgtype.Timestamp{Time: time.Now().Add(-time.Second).UTC(), Valid: true}}, nil)
+			},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, 1, time.Minute)
+			},
+			checkResponse: func(t *testing.T, response *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, response.Code)
+			},
+		},
 		{
 			name: "NoAuthorization",
 			buildStubs: func(t *testing.T, store *mockdb.MockStore) {
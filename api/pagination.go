@@ -0,0 +1,96 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"strings"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+const defaultPageSize = 10
+
+type pageParams struct {
+	Cursor   string `form:"cursor"`
+	PageSize int32  `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+type pagination struct {
+	NextCursor string `json:"next_cursor"`
+	PrevCursor string `json:"prev_cursor"`
+	Total      int64  `json:"total"`
+}
+
+func pageResponse(data interface{}, page pagination, statusCode int) gin.H {
+	rsp := successResponse(data, statusCode)
+	rsp["pagination"] = page
+	return rsp
+}
+
+// encodeCursor signs the cursor so clients can pass it back but cannot forge or edit one
+func (server *Server) encodeCursor(cursor interface{}) (string, error) {
+	payload, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(server.signCursor(encoded)), nil
+}
+
+func (server *Server) decodeCursor(value string, cursor interface{}) error {
+	encoded, signature, ok := strings.Cut(value, ".")
+	if !ok {
+		return ErrInvalidCursor
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, server.signCursor(encoded)) {
+		return ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(payload, cursor); err != nil {
+		return ErrInvalidCursor
+	}
+	return nil
+}
+
+func (server *Server) signCursor(encoded string) []byte {
+	mac := hmac.New(sha256.New, []byte("cursor:"+server.config.TokenSymmetricKey))
+	mac.Write([]byte(encoded))
+	return mac.Sum(nil)
+}
+
+// paginate trims the extra row fetched to detect another page and puts backward pages back in display order.
+// It reports whether there are rows after the last item and before the first one.
+func paginate[T any](items []T, pageSize int32, hasCursor, backward bool) (page []T, hasNext, hasPrev bool) {
+	more := len(items) > int(pageSize)
+	if more {
+		items = items[:pageSize]
+	}
+
+	if backward {
+		for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+			items[i], items[j] = items[j], items[i]
+		}
+		return items, hasCursor, more
+	}
+
+	return items, more, hasCursor
+}
+
+func pageSizeOrDefault(size int32) int32 {
+	if size == 0 {
+		return defaultPageSize
+	}
+	return size
+}
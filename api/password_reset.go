@@ -0,0 +1,204 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/mail"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultPasswordResetTokenDuration = time.Hour
+	// passwordResetTimeout bounds the lookup and email that follow a reset request
+	passwordResetTimeout = 30 * time.Second
+
+	// Reset emails are rationed per account, quietly, so the limit says nothing about whether the account exists
+	passwordResetWindow             = time.Hour
+	passwordResetRequestsPerAccount = 3
+	// and per client, openly, to slow down anyone walking through a list of addresses
+	passwordResetRequestsPerClient = 10
+
+	// requestLimiterMinSweep is how many clients a limiter tracks before it first looks for finished windows
+	requestLimiterMinSweep = 1024
+)
+
+var (
+	ErrTooManyRequests  = errors.New("too many requests, try again later")
+	ErrInvalidResetLink = errors.New("reset token is invalid or has expired")
+)
+
+type forgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type resetPasswordRequest struct {
+	Token    string `json:"token" binding:"required,max=100"`
+	Password string `json:"password" binding:"required,min=8,max=72"`
+}
+
+// ForgotPassword emails a reset token to the account with the given address.
+// The response is the same whether or not there is such an account.
+func (server *Server) ForgotPassword(ctx *gin.Context) {
+	if !server.passwordResetLimiter.allow(ctx.ClientIP()) {
+		ctx.JSON(http.StatusTooManyRequests, errorResponse(ErrTooManyRequests, http.StatusTooManyRequests))
+		return
+	}
+
+	var req forgotPasswordRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	// The lookup and the email happen after responding, so how long the request takes says nothing about the account
+	server.background.Add(1)
+	go server.requestPasswordReset(req.Email)
+
+	ctx.JSON(http.StatusAccepted, successResponse(nil, http.StatusAccepted))
+}
+
+// requestPasswordReset emails a reset token if email belongs to an account. It runs detached from the request,
+// whose context is gone by the time it gets going.
+func (server *Server) requestPasswordReset(email string) {
+	defer server.background.Done()
+
+	ctx, cancel := context.WithTimeout(context.Background(), passwordResetTimeout)
+	defer cancel()
+
+	user, err := server.store.GetUser(ctx, email)
+	if err == nil {
+		err = server.sendPasswordReset(ctx, user)
+	} else if errors.Is(err, db.ErrRecordNotFound) {
+		err = nil
+	}
+	if err != nil {
+		log.Printf("cannot send password reset email: %v", err)
+	}
+}
+
+func (server *Server) sendPasswordReset(ctx context.Context, user db.User) error {
+	recent, err := server.store.CountRecentUserTokens(ctx, db.CountRecentUserTokensParams{
+		UserID:    user.ID,
+		Purpose:   db.UserTokenPasswordReset,
+		CreatedAt: time.Now().Add(-passwordResetWindow),
+	})
+	if err != nil {
+		return err
+	}
+	if recent >= passwordResetRequestsPerAccount {
+		return nil
+	}
+
+	duration := server.config.PasswordResetTokenDuration
+	if duration == 0 {
+		duration = defaultPasswordResetTokenDuration
+	}
+
+	token, err := server.issueUserToken(ctx, user.ID, db.UserTokenPasswordReset, duration)
+	if err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Hi %s,\n\nSomeone asked to reset the password of your account. Choose a new password here:\n\n%s\n\nThis expires in %s. If it was not you, you can ignore this email and your password stays the same.\n",
+		user.FirstName, userTokenLink(server.config.PasswordResetURL, token), duration)
+
+	return server.mailer.Send(ctx, mail.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body:    body,
+	})
+}
+
+// ResetPassword sets a new password with the token from a reset email and signs the user out everywhere
+func (server *Server) ResetPassword(ctx *gin.Context) {
+	var req resetPasswordRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	hashedPassword, err := util.HashPassword(req.Password)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	user, err := server.store.ResetPasswordTx(ctx, db.ResetPasswordTxParams{
+		TokenHash:      hashUserToken(req.Token),
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ErrInvalidResetLink, http.StatusBadRequest))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(newUserResponse(user), http.StatusOK))
+}
+
+// requestLimiter allows each key a number of requests per fixed window
+type requestLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu      sync.Mutex
+	windows map[string]requestWindow
+	sweepAt int
+}
+
+type requestWindow struct {
+	start time.Time
+	count int
+}
+
+func newRequestLimiter(limit int, window time.Duration) *requestLimiter {
+	return &requestLimiter{
+		limit:   limit,
+		window:  window,
+		windows: make(map[string]requestWindow),
+		sweepAt: requestLimiterMinSweep,
+	}
+}
+
+// allow counts a request for key and reports whether it is within the limit
+func (limiter *requestLimiter) allow(key string) bool {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+
+	now := time.Now()
+
+	// Forget finished windows once the map has doubled since the last sweep, so it does not grow with every
+	// client ever seen and the sweep costs each request a constant amount on average
+	if len(limiter.windows) >= limiter.sweepAt {
+		for k, w := range limiter.windows {
+			if now.Sub(w.start) >= limiter.window {
+				delete(limiter.windows, k)
+			}
+		}
+		limiter.sweepAt = max(requestLimiterMinSweep, 2*len(limiter.windows))
+	}
+
+	w, ok := limiter.windows[key]
+	if !ok || now.Sub(w.start) >= limiter.window {
+		w = requestWindow{start: now}
+	}
+	if w.count >= limiter.limit {
+		return false
+	}
+
+	w.count++
+	limiter.windows[key] = w
+	return true
+}
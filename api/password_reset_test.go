@@ -0,0 +1,347 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type eqResetPasswordTxParamsMatcher struct {
+	tokenHash string
+	password  string
+}
+
+func (e eqResetPasswordTxParamsMatcher) Matches(x any) bool {
+	arg, ok := x.(db.ResetPasswordTxParams)
+	if !ok {
+		return false
+	}
+
+	return arg.TokenHash == e.tokenHash && util.CheckPassword(e.password, arg.HashedPassword) == nil
+}
+
+func (e eqResetPasswordTxParamsMatcher) String() string {
+	return fmt.Sprintf("matches token hash %v and password %v", e.tokenHash, e.password)
+}
+
+func TestForgotPassword(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+
+	testCases := []struct {
+		name          string
+		payload       gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *recordingMailer)
+	}{
+		{
+			name:    "OK",
+			payload: gin.H{"email": user.Email},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Eq(user.Email)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CountRecentUserTokens(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(0), nil)
+
+				store.EXPECT().
+					RevokeUserTokens(gomock.Any(), gomock.Eq(db.RevokeUserTokensParams{UserID: user.ID, Purpose: db.UserTokenPasswordReset})).
+					Times(1).
+					Return(nil)
+
+				store.EXPECT().
+					CreateUserToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UserToken{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *recordingMailer) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				require.Len(t, mailer.messages, 1)
+				require.Equal(t, user.Email, mailer.messages[0].To)
+			},
+		},
+		{
+			name:    "UnknownEmail",
+			payload: gin.H{"email": user.Email},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, db.ErrRecordNotFound)
+
+				store.EXPECT().
+					CreateUserToken(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *recordingMailer) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				require.Empty(t, mailer.messages)
+			},
+		},
+		{
+			name:    "AccountLimitReached",
+			payload: gin.H{"email": user.Email},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CountRecentUserTokens(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(passwordResetRequestsPerAccount), nil)
+
+				store.EXPECT().
+					CreateUserToken(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *recordingMailer) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+				require.Empty(t, mailer.messages)
+			},
+		},
+		{
+			name:    "InternalErrorLooksTheSame",
+			payload: gin.H{"email": user.Email},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *recordingMailer) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name:    "InvalidEmail",
+			payload: gin.H{"email": "not-an-email"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUser(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, mailer *recordingMailer) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			mailer := &recordingMailer{}
+			server.mailer = mailer
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/password/forgot", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			server.background.Wait()
+			tc.checkResponse(t, recorder, mailer)
+		})
+	}
+}
+
+func TestForgotPasswordClientLimit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Any()).
+		Times(passwordResetRequestsPerClient).
+		Return(db.User{}, db.ErrRecordNotFound)
+
+	server := newTestServer(t, store)
+
+	for i := 0; i <= passwordResetRequestsPerClient; i++ {
+		recorder := httptest.NewRecorder()
+
+		request, err := http.NewRequest(http.MethodPost, "/password/forgot", bytes.NewBufferString(`{"email":"ada@example.com"}`))
+		require.NoError(t, err)
+		// A forwarded address is the client's to pick, so it must not earn a fresh allowance
+		request.RemoteAddr = "203.0.113.7:40000"
+		request.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i))
+
+		server.router.ServeHTTP(recorder, request)
+		server.background.Wait()
+
+		if i < passwordResetRequestsPerClient {
+			require.Equal(t, http.StatusAccepted, recorder.Code)
+		} else {
+			require.Equal(t, http.StatusTooManyRequests, recorder.Code)
+		}
+	}
+}
+
+func TestForgotPasswordTrustedProxy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Any()).
+		Times(passwordResetRequestsPerClient+1).
+		Return(db.User{}, db.ErrRecordNotFound)
+
+	config := util.Config{
+		TokenSymmetricKey: util.RandomString(32),
+		Mailer:            mailerLog,
+		TrustedProxies:    []string{"203.0.113.7"},
+	}
+	server, err := NewServer(store, config)
+	require.NoError(t, err)
+
+	// Behind a trusted proxy each forwarded client gets its own allowance
+	for i := 0; i <= passwordResetRequestsPerClient; i++ {
+		recorder := httptest.NewRecorder()
+
+		request, err := http.NewRequest(http.MethodPost, "/password/forgot", bytes.NewBufferString(`{"email":"ada@example.com"}`))
+		require.NoError(t, err)
+		request.RemoteAddr = "203.0.113.7:40000"
+		request.Header.Set("X-Forwarded-For", fmt.Sprintf("198.51.100.%d", i))
+
+		server.router.ServeHTTP(recorder, request)
+		server.background.Wait()
+
+		require.Equal(t, http.StatusAccepted, recorder.Code)
+	}
+}
+
+func TestRequestLimiter(t *testing.T) {
+	limiter := newRequestLimiter(2, time.Minute)
+
+	require.True(t, limiter.allow("a"))
+	require.True(t, limiter.allow("a"))
+	require.False(t, limiter.allow("a"))
+	require.True(t, limiter.allow("b"))
+
+	// A finished window starts over even before a sweep has dropped it
+	w := limiter.windows["a"]
+	w.start = time.Now().Add(-time.Minute)
+	limiter.windows["a"] = w
+	require.True(t, limiter.allow("a"))
+
+	// Growing past the sweep size drops the finished windows
+	for i := 0; i < requestLimiterMinSweep; i++ {
+		limiter.windows[fmt.Sprintf("old-%d", i)] = requestWindow{start: time.Now().Add(-time.Hour), count: 1}
+	}
+	require.True(t, limiter.allow("c"))
+	require.Len(t, limiter.windows, 3)
+}
+
+func TestResetPassword(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	token := util.RandomString(43)
+	password := util.RandomString(12)
+
+	testCases := []struct {
+		name          string
+		payload       gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			payload: gin.H{"token": token, "password": password},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), eqResetPasswordTxParamsMatcher{tokenHash: hashUserToken(token), password: password}).
+					Times(1).
+					Return(user, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchUser(t, recorder.Body, user)
+			},
+		},
+		{
+			name:    "InvalidToken",
+			payload: gin.H{"token": token, "password": password},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "ShortPassword",
+			payload: gin.H{"token": token, "password": "short"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "InternalError",
+			payload: gin.H{"token": token, "password": password},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ResetPasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/password/reset", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
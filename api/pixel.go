@@ -0,0 +1,261 @@
+package api
+
+import (
+	"errors"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/token"
+	"github.com/gin-gonic/gin"
+	"log"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	pixelProviderMeta      = "meta"
+	pixelProviderGoogleAds = "google_ads"
+	pixelProviderLinkedIn  = "linkedin"
+	// Script and image pixels load a snippet from one of the configured allowed hosts
+	pixelProviderScript = "script"
+	pixelProviderImage  = "image"
+
+	// pixelPageMaxWaitMs caps how long the pixel page holds a visitor before redirecting
+	pixelPageMaxWaitMs = 1500
+)
+
+var (
+	ErrPixelNotOwned        = errors.New("pixel does not belong to this user")
+	ErrPixelAccountID       = errors.New("account_id is not valid for this provider")
+	ErrPixelSrcRequired     = errors.New("src is required for script and image pixels")
+	ErrPixelSrcNotAllowed   = errors.New("src must be an https url on one of the allowed pixel hosts")
+	ErrPixelSrcNotSupported = errors.New("src is only used by script and image pixels")
+)
+
+// pixelAccountIDs are the shapes of the ids each ad platform hands out
+var pixelAccountIDs = map[string]*regexp.Regexp{
+	pixelProviderMeta:      regexp.MustCompile(`^[0-9]{1,20}$`),
+	pixelProviderGoogleAds: regexp.MustCompile(`^AW-[0-9]{1,20}$`),
+	pixelProviderLinkedIn:  regexp.MustCompile(`^[0-9]{1,20}$`),
+}
+
+type createPixelParams struct {
+	Name      string `json:"name" binding:"required,max=100"`
+	Provider  string `json:"provider" binding:"required,oneof=meta google_ads linkedin script image"`
+	AccountID string `json:"account_id" binding:"max=50"`
+	Src       string `json:"src" binding:"omitempty,http_url,max=2048"`
+}
+
+type setLinkPixelsParams struct {
+	// An empty list turns the link back into a plain redirect
+	PixelIDs []int64 `json:"pixel_ids" binding:"max=20,unique,dive,min=1"`
+}
+
+type pixelPage struct {
+	Pixels      []db.Pixel
+	Destination string
+	MaxWaitMs   int
+}
+
+func (server *Server) CreatePixel(ctx *gin.Context) {
+	var req createPixelParams
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	if err := server.validatePixel(req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	pixel, err := server.store.CreatePixel(ctx, db.CreatePixelParams{
+		UserID:    authPayload.UserID,
+		Name:      req.Name,
+		Provider:  req.Provider,
+		AccountID: req.AccountID,
+		Src:       req.Src,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, successResponse(pixel, http.StatusCreated))
+}
+
+// validatePixel checks a pixel only carries what its provider's snippet needs, so the page
+// never loads anything but the platforms' own scripts and the allowed hosts
+func (server *Server) validatePixel(req createPixelParams) error {
+	if pattern, ok := pixelAccountIDs[req.Provider]; ok {
+		if !pattern.MatchString(req.AccountID) {
+			return ErrPixelAccountID
+		}
+		if req.Src != "" {
+			return ErrPixelSrcNotSupported
+		}
+		return nil
+	}
+
+	if req.AccountID != "" {
+		return ErrPixelAccountID
+	}
+	if req.Src == "" {
+		return ErrPixelSrcRequired
+	}
+
+	src, err := url.Parse(req.Src)
+	if err != nil || src.Scheme != "https" || !server.pixelHostAllowed(src.Hostname()) {
+		return ErrPixelSrcNotAllowed
+	}
+
+	return nil
+}
+
+func (server *Server) pixelHostAllowed(host string) bool {
+	for _, allowed := range server.config.PixelAllowedHosts {
+		if strings.EqualFold(strings.TrimSpace(allowed), host) {
+			return true
+		}
+	}
+	return false
+}
+
+func (server *Server) GetPixels(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	pixels, err := server.store.GetPixelsByUser(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(pixels, http.StatusOK))
+}
+
+func (server *Server) DeletePixel(ctx *gin.Context) {
+	var req getLinkByIDParams
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	pixel, err := server.store.GetPixel(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err, http.StatusNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if pixel.UserID != authPayload.UserID {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrPixelNotOwned, http.StatusForbidden))
+		return
+	}
+
+	if err := server.store.DeletePixel(ctx, pixel.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(nil, http.StatusOK))
+}
+
+// SetLinkPixels replaces the pixels fired before a link redirects
+func (server *Server) SetLinkPixels(ctx *gin.Context) {
+	var req setLinkPixelsParams
+	var linkReq getLinkByIDParams
+
+	if err := ctx.ShouldBindUri(&linkReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	link, err := server.store.GetLinkById(ctx, linkReq.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err, http.StatusNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if link.UserID != authPayload.UserID {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrLinkNotOwned, http.StatusForbidden))
+		return
+	}
+
+	link, err = server.store.SetLinkPixelsTx(ctx, db.SetLinkPixelsTxParams{
+		LinkID:   link.ID,
+		UserID:   link.UserID,
+		PixelIDs: req.PixelIDs,
+	})
+	if err != nil {
+		if errors.Is(err, db.ErrLinkPixelNotOwned) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(link, http.StatusOK))
+}
+
+// renderPixelPage fires a link's pixels and sends the visitor on from the browser.
+// Anything that keeps the page from working falls back to the plain redirect.
+func (server *Server) renderPixelPage(ctx *gin.Context, link db.Link) {
+	pixels := server.linkPixels(ctx, link)
+
+	// The destination is handed to a script, which must only ever navigate to a web page
+	if len(pixels) == 0 || !isWebURL(link.Link) {
+		ctx.Redirect(http.StatusPermanentRedirect, link.Link)
+		return
+	}
+
+	page := pixelPage{
+		Pixels:      pixels,
+		Destination: link.Link,
+		MaxWaitMs:   pixelPageMaxWaitMs,
+	}
+
+	// Every visit has to load the page for the pixels to fire
+	ctx.Header("Cache-Control", "no-store")
+	ctx.Header("X-Frame-Options", "DENY")
+	ctx.HTML(http.StatusOK, "pixel_page.html", page)
+}
+
+// linkPixels loads the pixels a link fires; a failure only costs the pixels, never the visit
+func (server *Server) linkPixels(ctx *gin.Context, link db.Link) []db.Pixel {
+	if !link.HasPixels {
+		return nil
+	}
+
+	pixels, err := server.store.GetPixelsByLink(ctx, link.ID)
+	if err != nil {
+		log.Printf("cannot load pixels for link %d: %v", link.ID, err)
+		return nil
+	}
+	return pixels
+}
+
+func isWebURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https")
+}
@@ -0,0 +1,348 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestCreatePixel(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+
+	testCases := []struct {
+		name          string
+		payload       gin.H
+		created       bool
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "Meta",
+			payload: gin.H{"name": "Retargeting", "provider": "meta", "account_id": "1234567890"},
+			created: true,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:    "GoogleAds",
+			payload: gin.H{"name": "Ads", "provider": "google_ads", "account_id": "AW-123456"},
+			created: true,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:    "AllowedImage",
+			payload: gin.H{"name": "Partner", "provider": "image", "src": "https://pixels.example.com/p.gif?id=1"},
+			created: true,
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:    "InvalidAccountID",
+			payload: gin.H{"name": "Retargeting", "provider": "meta", "account_id": "1');alert(1);//"},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "HostNotAllowed",
+			payload: gin.H{"name": "Partner", "provider": "script", "src": "https://evil.example.net/x.js"},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "PlainHTTP",
+			payload: gin.H{"name": "Partner", "provider": "script", "src": "http://pixels.example.com/x.js"},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "MissingSrc",
+			payload: gin.H{"name": "Partner", "provider": "image"},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "UnknownProvider",
+			payload: gin.H{"name": "Partner", "provider": "html", "src": "https://pixels.example.com/x"},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+				Times(1).
+				Return(user, nil)
+
+			if tc.created {
+				store.EXPECT().
+					CreatePixel(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.CreatePixelParams) (db.Pixel, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.Equal(t, tc.payload["provider"], arg.Provider)
+						return db.Pixel{ID: 1, UserID: arg.UserID, Provider: arg.Provider, AccountID: arg.AccountID, Src: arg.Src}, nil
+					})
+			} else {
+				store.EXPECT().
+					CreatePixel(gomock.Any(), gomock.Any()).
+					Times(0)
+			}
+
+			server := newTestServer(t, store)
+			server.config.PixelAllowedHosts = []string{"pixels.example.com"}
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/pixels", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestSetLinkPixels(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	link := createRandomLink(user.ID)
+
+	testCases := []struct {
+		name          string
+		payload       gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			payload: gin.H{"pixel_ids": []int64{3, 4}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(link, nil)
+
+				updated := link
+				updated.HasPixels = true
+
+				store.EXPECT().
+					SetLinkPixelsTx(gomock.Any(), gomock.Eq(db.SetLinkPixelsTxParams{
+						LinkID:   link.ID,
+						UserID:   user.ID,
+						PixelIDs: []int64{3, 4},
+					})).
+					Times(1).
+					Return(updated, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response Response[db.Link]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.True(t, response.Data.HasPixels)
+			},
+		},
+		{
+			name:    "DuplicatePixel",
+			payload: gin.H{"pixel_ids": []int64{3, 3}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SetLinkPixelsTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "PixelNotOwned",
+			payload: gin.H{"pixel_ids": []int64{3}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					SetLinkPixelsTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Link{}, db.ErrLinkPixelNotOwned)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "LinkNotOwned",
+			payload: gin.H{"pixel_ids": []int64{3}},
+			buildStubs: func(store *mockdb.MockStore) {
+				other := link
+				other.UserID = user.ID + 1
+
+				store.EXPECT().
+					GetLinkById(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(other, nil)
+
+				store.EXPECT().
+					SetLinkPixelsTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+				Times(1).
+				Return(user, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPut, fmt.Sprintf("/links/%d/pixels", link.ID), bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestPixelPage(t *testing.T) {
+	link := createRandomLink(util.RandomInt(1, 20))
+	link.Link = "https://shop.example.com/sale?ref=ad"
+	link.HasPixels = true
+
+	plainLink := createRandomLink(link.UserID)
+
+	pixels := []db.Pixel{
+		{ID: 1, Provider: pixelProviderMeta, AccountID: "1234567890"},
+		{ID: 2, Provider: pixelProviderGoogleAds, AccountID: "AW-42"},
+		{ID: 3, Provider: pixelProviderImage, Src: "https://pixels.example.com/p.gif?a=1&b=2"},
+	}
+
+	testCases := []struct {
+		name          string
+		link          db.Link
+		buildStubs    func(store *mockdb.MockStore, link db.Link)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "FiresPixels",
+			link: link,
+			buildStubs: func(store *mockdb.MockStore, link db.Link) {
+				store.EXPECT().
+					GetPixelsByLink(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return(pixels, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Equal(t, "no-store", recorder.Header().Get("Cache-Control"))
+
+				body := recorder.Body.String()
+				require.Contains(t, body, `fbq("init", "1234567890")`)
+				require.Contains(t, body, `https://www.googletagmanager.com/gtag/js?id=AW-42`)
+				require.Contains(t, body, `<img src="https://pixels.example.com/p.gif?a=1&amp;b=2"`)
+				require.Contains(t, body, `window.location.replace("https://shop.example.com/sale?ref=ad")`)
+			},
+		},
+		{
+			name: "PixelsRemoved",
+			link: link,
+			buildStubs: func(store *mockdb.MockStore, link db.Link) {
+				store.EXPECT().
+					GetPixelsByLink(gomock.Any(), gomock.Eq(link.ID)).
+					Times(1).
+					Return([]db.Pixel{}, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPermanentRedirect, recorder.Code)
+				require.Equal(t, link.Link, recorder.Header().Get("Location"))
+			},
+		},
+		{
+			name: "NoPixels",
+			link: plainLink,
+			buildStubs: func(store *mockdb.MockStore, link db.Link) {
+				store.EXPECT().
+					GetPixelsByLink(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPermanentRedirect, recorder.Code)
+				require.Equal(t, plainLink.Link, recorder.Header().Get("Location"))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetLinkByCode(gomock.Any(), gomock.Eq(tc.link.Code)).
+				Times(1).
+				Return(tc.link, nil)
+
+			store.EXPECT().
+				RecordClick(gomock.Any(), gomock.Any()).
+				Times(1).
+				Return(int64(1), nil)
+			tc.buildStubs(store, tc.link)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/"+tc.link.Code, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"time"
+)
+
+// previewSuffix appended to a code shows where the link leads instead of following it
+const previewSuffix = "+"
+
+type previewPage struct {
+	ShortURL    string
+	Destination string
+	Title       string
+	CreatedAt   string
+	Clicks      int64
+	Unavailable string
+}
+
+// renderPreview shows a link's destination and stats without redirecting or counting a click
+func (server *Server) renderPreview(ctx *gin.Context, link db.Link) {
+	page := previewPage{
+		ShortURL:    server.shortURL(ctx, link.Code),
+		Destination: link.Link,
+		Title:       firstNonEmpty(link.Title, link.OgTitle, link.MetaTitle),
+		CreatedAt:   link.CreatedAt.UTC().Format("January 2, 2006"),
+		Clicks:      link.ClickCount,
+	}
+
+	switch {
+	case link.ExpiresAt.Valid && !link.ExpiresAt.Time.After(time.Now()):
+		page.Unavailable = ErrLinkExpired.Error()
+	case link.MaxClicks.Valid && link.ClickCount >= link.MaxClicks.Int64:
+		page.Unavailable = ErrLinkClickLimit.Error()
+	}
+
+	ctx.HTML(http.StatusOK, "preview.html", page)
+}
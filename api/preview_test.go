@@ -0,0 +1,132 @@
+package api
+
+import (
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPreview(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+
+	link := createRandomLink(user.ID)
+	link.Title = `Spring <sale>`
+	link.ClickCount = 42
+	link.CreatedAt = time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)
+
+	expiredLink := createRandomLink(user.ID)
+	expiredLink.ExpiresAt = pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true}
+
+	inactiveLink := createRandomLink(user.ID)
+	inactiveLink.Active = pgtype.Bool{Bool: false, Valid: true}
+
+	testCases := []struct {
+		name          string
+		url           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			url:  "/" + link.Code + "+",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Eq(link.Code)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					RecordClick(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				body := recorder.Body.String()
+				require.Contains(t, body, link.Link)
+				require.Contains(t, body, "Spring &lt;sale&gt;")
+				require.Contains(t, body, "March 5, 2024")
+				require.Contains(t, body, "<dd>42</dd>")
+			},
+		},
+		{
+			name: "Expired",
+			url:  "/" + expiredLink.Code + "+",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Eq(expiredLink.Code)).
+					Times(1).
+					Return(expiredLink, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Body.String(), ErrLinkExpired.Error())
+			},
+		},
+		{
+			name: "Inactive",
+			url:  "/" + inactiveLink.Code + "+",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Eq(inactiveLink.Code)).
+					Times(1).
+					Return(inactiveLink, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			url:  "/nothere+",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Eq("nothere")).
+					Times(1).
+					Return(db.Link{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+		{
+			name: "OnlySuffix",
+			url:  "/+",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"errors"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/qr"
+	"github.com/bolusarz/urlmini/token"
+	"github.com/gin-gonic/gin"
+	"github.com/skip2/go-qrcode"
+	"net/http"
+)
+
+var ErrQRLogoNotConfigured = errors.New("qr: no logo is configured on this server")
+
+type getLinkQRCodeParams struct {
+	Format     string `form:"format" binding:"omitempty,oneof=png svg"`
+	Size       int    `form:"size" binding:"omitempty,min=64,max=2048"`
+	Level      string `form:"level" binding:"omitempty,oneof=L M Q H l m q h"`
+	Margin     *int   `form:"margin" binding:"omitempty,min=0,max=16"`
+	Foreground string `form:"fg"`
+	Background string `form:"bg"`
+	Logo       bool   `form:"logo"`
+}
+
+func (server *Server) GetLinkQRCode(ctx *gin.Context) {
+	var linkReq getLinkByIDParams
+	var req getLinkQRCodeParams
+
+	if err := ctx.ShouldBindUri(&linkReq); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	opts, err := server.qrOptions(req)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	link, err := server.store.GetLinkById(ctx, linkReq.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err, http.StatusNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if link.UserID != authPayload.UserID {
+		ctx.JSON(http.StatusForbidden, errorResponse(err, http.StatusForbidden))
+		return
+	}
+
+	content, err := server.linkShortURL(ctx, link, nil)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	if req.Format == "svg" {
+		svg, err := qr.SVG(content, opts)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+			return
+		}
+		ctx.Data(http.StatusOK, "image/svg+xml", svg)
+		return
+	}
+
+	png, err := qr.PNG(content, opts)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+	ctx.Data(http.StatusOK, "image/png", png)
+}
+
+func (server *Server) qrOptions(req getLinkQRCodeParams) (qr.Options, error) {
+	opts := qr.DefaultOptions()
+
+	if req.Size != 0 {
+		opts.Size = req.Size
+	}
+
+	if req.Margin != nil {
+		opts.Margin = *req.Margin
+	}
+
+	if req.Level != "" {
+		level, err := qr.ParseLevel(req.Level)
+		if err != nil {
+			return opts, err
+		}
+		opts.Level = level
+	}
+
+	if req.Foreground != "" {
+		fg, err := qr.ParseColor(req.Foreground)
+		if err != nil {
+			return opts, err
+		}
+		opts.Foreground = fg
+	}
+
+	if req.Background != "" {
+		bg, err := qr.ParseColor(req.Background)
+		if err != nil {
+			return opts, err
+		}
+		opts.Background = bg
+	}
+
+	if req.Logo {
+		if server.qrLogo == nil {
+			return opts, ErrQRLogoNotConfigured
+		}
+		opts.Logo = server.qrLogo
+		// The logo hides part of the symbol so it needs the highest recovery level to stay readable
+		opts.Level = qrcode.Highest
+	}
+
+	return opts, nil
+}
@@ -2,18 +2,36 @@ package api
 
 import (
 	"fmt"
+	"github.com/bolusarz/urlmini/cloak"
 	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/domain"
+	"github.com/bolusarz/urlmini/mail"
 	"github.com/bolusarz/urlmini/token"
 	"github.com/bolusarz/urlmini/util"
 	"github.com/gin-gonic/gin"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
 	"net/http"
+	"os"
+	"strings"
+	"sync"
 )
 
 type Server struct {
-	store      db.Store
-	tokenMaker token.Maker
-	router     *gin.Engine
-	config     util.Config
+	store          db.Store
+	tokenMaker     token.Maker
+	router         *gin.Engine
+	config         util.Config
+	qrLogo         image.Image
+	domainVerifier *domain.Verifier
+	cloakChecker   *cloak.Checker
+	mailer         mail.Mailer
+	notFoundPage   []byte
+
+	passwordResetLimiter *requestLimiter
+	// background tracks work that outlives its request, so tests can wait for it
+	background sync.WaitGroup
 }
 
 func NewServer(store db.Store, config util.Config) (*Server, error) {
@@ -23,23 +41,58 @@ func NewServer(store db.Store, config util.Config) (*Server, error) {
 	}
 
 	server := &Server{
-		config:     config,
-		store:      store,
-		tokenMaker: tokenMaker,
+		config:         config,
+		store:          store,
+		tokenMaker:     tokenMaker,
+		domainVerifier: domain.NewVerifier(domain.Options{}),
+		cloakChecker:   cloak.NewChecker(cloak.Options{}),
+
+		passwordResetLimiter: newRequestLimiter(passwordResetRequestsPerClient, passwordResetWindow),
+	}
+
+	server.mailer, err = newMailer(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create mailer: %w", err)
 	}
 
-	server.setupRouter()
+	if config.QRLogoPath != "" {
+		server.qrLogo, err = loadImage(config.QRLogoPath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load qr logo: %w", err)
+		}
+	}
+
+	if config.NotFoundPagePath != "" {
+		server.notFoundPage, err = os.ReadFile(config.NotFoundPagePath)
+		if err != nil {
+			return nil, fmt.Errorf("cannot load not found page: %w", err)
+		}
+	}
+
+	if err := server.setupRouter(); err != nil {
+		return nil, err
+	}
 
 	return server, nil
 }
 
-func (server *Server) setupRouter() {
+func (server *Server) setupRouter() error {
 	router := gin.Default()
+	router.SetHTMLTemplate(templates)
+
+	// Only proxies named in the config may set the client address, so no one else can pick their own
+	if err := router.SetTrustedProxies(server.config.TrustedProxies); err != nil {
+		return fmt.Errorf("cannot set trusted proxies: %w", err)
+	}
 
 	router.POST("/users", server.createUser)
 	router.POST("/login", server.loginUser)
 	router.POST("/token/refresh", server.renewAccessToken)
+	router.POST("/email/verify", server.VerifyEmail)
+	router.POST("/password/forgot", server.ForgotPassword)
+	router.POST("/password/reset", server.ResetPassword)
 
+	router.GET("/", server.GetRoot)
 	router.GET("/:code", server.GetLinkByCode)
 
 	authRoutes := router.Group("/").
@@ -48,13 +101,83 @@ func (server *Server) setupRouter() {
 			userExistsMiddleware(server.store),
 		)
 
-	authRoutes.POST("/links", server.CreateLink)
+	authRoutes.GET("/sessions", server.GetSessions)
+	authRoutes.PATCH("/users/me/preferences", server.UpdatePreferences)
+	authRoutes.POST("/users/me/password", server.ChangePassword)
+	authRoutes.POST("/users/me/email/verification", server.ResendEmailVerification)
+
+	verifiedEmail := verifiedEmailMiddleware(server.config.EmailVerificationPolicy)
+
+	authRoutes.POST("/links", verifiedEmail, server.CreateLink)
+	authRoutes.POST("/links/bulk", verifiedEmail, server.BulkCreateLinks)
 	authRoutes.GET("/links", server.GetLinks)
+	authRoutes.GET("/links/export", server.ExportLinks)
+	authRoutes.GET("/links/broken", server.GetBrokenLinks)
 	authRoutes.GET("/links/:id", server.GetLinkById)
 	authRoutes.PATCH("/links/:id", server.ChangeCode)
 	authRoutes.PATCH("/links/:id/toggle", server.ToggleLinkStatus)
+	authRoutes.PATCH("/links/:id/details", server.UpdateLinkDetails)
+	authRoutes.GET("/links/:id/qr", server.GetLinkQRCode)
+	authRoutes.GET("/links/:id/tags", server.GetLinkTags)
+	authRoutes.PUT("/links/:id/limits", server.SetLinkLimits)
+	authRoutes.PATCH("/links/:id/interstitial", server.SetLinkInterstitial)
+	authRoutes.PATCH("/links/:id/folder", server.SetLinkFolder)
+	authRoutes.PUT("/links/:id/pixels", server.SetLinkPixels)
+	authRoutes.PATCH("/links/:id/cloak", server.SetLinkCloak)
+
+	authRoutes.POST("/tags", server.CreateTag)
+	authRoutes.GET("/tags", server.GetTags)
+	authRoutes.PATCH("/tags/:id", server.RenameTag)
+	authRoutes.DELETE("/tags/:id", server.DeleteTag)
+	authRoutes.POST("/tags/:id/links", server.AttachTag)
+	authRoutes.DELETE("/tags/:id/links", server.DetachTag)
+
+	authRoutes.POST("/imports", verifiedEmail, server.CreateImport)
+	authRoutes.GET("/imports/:id", server.GetImport)
+	authRoutes.GET("/imports/:id/conflicts", server.GetImportConflicts)
+
+	authRoutes.POST("/webhooks", server.CreateWebhook)
+	authRoutes.GET("/webhooks", server.GetWebhooks)
+	authRoutes.DELETE("/webhooks/:id", server.DeleteWebhook)
+	authRoutes.GET("/webhooks/:id/deliveries", server.GetWebhookDeliveries)
+	authRoutes.POST("/webhooks/:id/deliveries/:delivery_id/redeliver", server.RedeliverWebhookDelivery)
+
+	authRoutes.POST("/domains", server.CreateDomain)
+	authRoutes.GET("/domains", server.GetDomains)
+	authRoutes.POST("/domains/:id/verify", server.VerifyDomain)
+	authRoutes.PUT("/domains/:id/fallbacks", server.SetDomainFallbacks)
+	authRoutes.DELETE("/domains/:id", server.DeleteDomain)
+
+	authRoutes.POST("/bio-pages", server.CreateBioPage)
+	authRoutes.GET("/bio-pages", server.GetBioPages)
+	authRoutes.GET("/bio-pages/:id", server.GetBioPage)
+	authRoutes.PUT("/bio-pages/:id", server.UpdateBioPage)
+	authRoutes.PUT("/bio-pages/:id/links", server.SetBioPageLinks)
+	authRoutes.GET("/bio-pages/:id/stats", server.GetBioPageStats)
+	authRoutes.DELETE("/bio-pages/:id", server.DeleteBioPage)
+
+	authRoutes.POST("/pixels", server.CreatePixel)
+	authRoutes.GET("/pixels", server.GetPixels)
+	authRoutes.DELETE("/pixels/:id", server.DeletePixel)
+
+	authRoutes.POST("/campaigns", server.CreateCampaign)
+	authRoutes.GET("/campaigns", server.GetCampaigns)
+	authRoutes.DELETE("/campaigns/:id", server.DeleteCampaign)
+	authRoutes.POST("/campaigns/:id/links", server.AddCampaignLinks)
+	authRoutes.DELETE("/campaigns/:id/links", server.RemoveCampaignLinks)
+	authRoutes.GET("/campaigns/:id/stats", server.GetCampaignStats)
+
+	authRoutes.POST("/campaign-templates", server.CreateCampaignTemplate)
+	authRoutes.GET("/campaign-templates", server.GetCampaignTemplates)
+	authRoutes.DELETE("/campaign-templates/:id", server.DeleteCampaignTemplate)
+
+	authRoutes.POST("/folders", server.CreateFolder)
+	authRoutes.GET("/folders", server.GetFolders)
+	authRoutes.PATCH("/folders/:id", server.RenameFolder)
+	authRoutes.DELETE("/folders/:id", server.DeleteFolder)
 
 	server.router = router
+	return nil
 }
 
 // Start runs the HTTP server on a specific address
@@ -62,6 +185,61 @@ func (server *Server) Start(address string) error {
 	return server.router.Run(address)
 }
 
+// shortURL builds the public URL for a code, falling back to the request host when no base URL is configured.
+// Requests served on a custom domain keep that domain.
+func (server *Server) shortURL(ctx *gin.Context, code string) string {
+	if value, ok := ctx.Get(requestDomainKey); ok {
+		return fmt.Sprintf("%s://%s/%s", requestScheme(ctx), value.(db.Domain).Hostname, code)
+	}
+
+	if server.config.BaseURL != "" {
+		return strings.TrimRight(server.config.BaseURL, "/") + "/" + code
+	}
+
+	return fmt.Sprintf("%s://%s/%s", requestScheme(ctx), ctx.Request.Host, code)
+}
+
+// linkShortURL builds the public URL of a link, on its own custom domain when it has one.
+// API requests never carry the link's domain, so it is looked up, once per domain when hostnames is given.
+func (server *Server) linkShortURL(ctx *gin.Context, link db.Link, hostnames map[int64]string) (string, error) {
+	if !link.DomainID.Valid {
+		return server.shortURL(ctx, link.Code), nil
+	}
+
+	hostname, ok := hostnames[link.DomainID.Int64]
+	if !ok {
+		d, err := server.store.GetDomain(ctx, link.DomainID.Int64)
+		if err != nil {
+			return "", err
+		}
+		hostname = d.Hostname
+
+		if hostnames != nil {
+			hostnames[d.ID] = hostname
+		}
+	}
+
+	return fmt.Sprintf("%s://%s/%s", requestScheme(ctx), hostname, link.Code), nil
+}
+
+func requestScheme(ctx *gin.Context) string {
+	if ctx.Request.TLS != nil {
+		return "https"
+	}
+	return "http"
+}
+
+func loadImage(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	return img, err
+}
+
 type Response[T any] struct {
 	Code   int    `json:"code"`
 	Status string `json:"status"`
@@ -0,0 +1,105 @@
+package api
+
+import (
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/token"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"net/http"
+	"time"
+)
+
+type sessionResponse struct {
+	ID        uuid.UUID `json:"id"`
+	ClientIp  string    `json:"client_ip"`
+	UserAgent string    `json:"user_agent"`
+	IsBlocked bool      `json:"is_blocked"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func newSessionResponse(session db.Session) sessionResponse {
+	return sessionResponse{
+		ID:        session.ID,
+		ClientIp:  session.ClientIp,
+		UserAgent: session.UserAgent,
+		IsBlocked: session.IsBlocked,
+		ExpiresAt: session.ExpiresAt,
+		CreatedAt: session.CreatedAt,
+	}
+}
+
+type sessionCursor struct {
+	Backward  bool      `json:"b,omitempty"`
+	ID        uuid.UUID `json:"i"`
+	CreatedAt time.Time `json:"t"`
+}
+
+// GetSessions lists the caller's sessions, newest first
+func (server *Server) GetSessions(ctx *gin.Context) {
+	var req pageParams
+	var cursor sessionCursor
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	if req.Cursor != "" {
+		if err := server.decodeCursor(req.Cursor, &cursor); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ErrInvalidCursor, http.StatusBadRequest))
+			return
+		}
+	}
+
+	pageSize := pageSizeOrDefault(req.PageSize)
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	args := db.GetSessionsParams{
+		UserID: authPayload.UserID,
+		Limit:  pageSize + 1,
+	}
+
+	if req.Cursor != "" {
+		args.CursorID = pgtype.UUID{Bytes: cursor.ID, Valid: true}
+		args.CursorCreatedAt = pgtype.Timestamptz{Time: cursor.CreatedAt, Valid: true}
+		args.Backward = cursor.Backward
+	}
+
+	sessions, err := server.store.GetSessions(ctx, args)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	total, err := server.store.CountSessions(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	sessions, hasNext, hasPrev := paginate(sessions, pageSize, req.Cursor != "", cursor.Backward)
+	page := pagination{Total: total}
+
+	if len(sessions) > 0 {
+		if hasNext {
+			last := sessions[len(sessions)-1]
+			page.NextCursor, err = server.encodeCursor(sessionCursor{ID: last.ID, CreatedAt: last.CreatedAt})
+		}
+		if err == nil && hasPrev {
+			page.PrevCursor, err = server.encodeCursor(sessionCursor{Backward: true, ID: sessions[0].ID, CreatedAt: sessions[0].CreatedAt})
+		}
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	rsp := make([]sessionResponse, len(sessions))
+	for i, session := range sessions {
+		rsp[i] = newSessionResponse(session)
+	}
+
+	ctx.JSON(http.StatusOK, pageResponse(rsp, page, http.StatusOK))
+}
@@ -0,0 +1,161 @@
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func createRandomSession(userId int64, createdAt time.Time) db.Session {
+	return db.Session{
+		ID:           uuid.New(),
+		UserID:       userId,
+		RefreshToken: util.RandomString(32),
+		ClientIp:     util.RandomIP(),
+		UserAgent:    util.RandomString(10),
+		ExpiresAt:    createdAt.Add(time.Hour),
+		CreatedAt:    createdAt,
+	}
+}
+
+func TestGetSessions(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+
+	now := time.Now().UTC()
+	sessions := make([]db.Session, 3)
+	for i := range sessions {
+		sessions[i] = createRandomSession(user.ID, now.Add(-time.Duration(i)*time.Minute))
+	}
+
+	testCases := []struct {
+		name          string
+		query         func(t *testing.T, server *Server) string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "FirstPage",
+			query: func(t *testing.T, server *Server) string {
+				return "?page_size=2"
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSessions(gomock.Any(), gomock.Eq(db.GetSessionsParams{UserID: user.ID, Limit: 3})).
+					Times(1).
+					Return(sessions, nil)
+
+				store.EXPECT().
+					CountSessions(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(int64(len(sessions)), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.NotContains(t, recorder.Body.String(), sessions[0].RefreshToken)
+
+				var response Response[[]sessionResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Len(t, response.Data, 2)
+				require.Equal(t, sessions[0].ID, response.Data[0].ID)
+
+				page := requirePagination(t, recorder)
+				require.NotEmpty(t, page.NextCursor)
+				require.Empty(t, page.PrevCursor)
+				require.Equal(t, int64(3), page.Total)
+			},
+		},
+		{
+			name: "NextPage",
+			query: func(t *testing.T, server *Server) string {
+				cursor, err := server.encodeCursor(sessionCursor{ID: sessions[1].ID, CreatedAt: sessions[1].CreatedAt})
+				require.NoError(t, err)
+				return "?page_size=2&cursor=" + cursor
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSessions(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.GetSessionsParams) ([]db.Session, error) {
+						require.False(t, arg.Backward)
+						require.Equal(t, sessions[1].ID, uuid.UUID(arg.CursorID.Bytes))
+						return sessions[2:], nil
+					})
+
+				store.EXPECT().
+					CountSessions(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(int64(len(sessions)), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				page := requirePagination(t, recorder)
+				require.Empty(t, page.NextCursor)
+				require.NotEmpty(t, page.PrevCursor)
+			},
+		},
+		{
+			name: "InvalidCursor",
+			query: func(t *testing.T, server *Server) string {
+				return "?cursor=abc.def"
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSessions(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalServerError",
+			query: func(t *testing.T, server *Server) string {
+				return ""
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetSessions(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+				Times(1).
+				Return(user, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/sessions"+tc.query(t, server), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
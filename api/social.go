@@ -0,0 +1,74 @@
+package api
+
+import (
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strings"
+)
+
+// crawlerAgents are substrings of the user agents used by link unfurlers
+var crawlerAgents = []string{
+	"facebookexternalhit",
+	"facebot",
+	"twitterbot",
+	"linkedinbot",
+	"slackbot",
+	"discordbot",
+	"whatsapp",
+	"telegrambot",
+	"pinterest",
+	"redditbot",
+	"applebot",
+	"skypeuripreview",
+	"embedly",
+	"vkshare",
+	"iframely",
+	"mastodon",
+}
+
+func isCrawler(userAgent string) bool {
+	userAgent = strings.ToLower(userAgent)
+	for _, agent := range crawlerAgents {
+		if strings.Contains(userAgent, agent) {
+			return true
+		}
+	}
+	return false
+}
+
+type socialCard struct {
+	URL         string
+	Title       string
+	Description string
+	Image       string
+	// Destination is left empty when the card must not reveal or redirect to it
+	Destination string
+}
+
+// renderSocialCard serves crawlers the link's preview tags instead of the destination's
+func (server *Server) renderSocialCard(ctx *gin.Context, link db.Link, showDestination bool) {
+	card := socialCard{
+		URL:         server.shortURL(ctx, link.Code),
+		Description: firstNonEmpty(link.OgDescription, link.MetaDescription),
+		Image:       link.OgImage,
+	}
+
+	if showDestination {
+		card.Title = firstNonEmpty(link.OgTitle, link.Title, link.MetaTitle, link.Link)
+		card.Destination = link.Link
+	} else {
+		card.Title = firstNonEmpty(link.OgTitle, link.Title, link.MetaTitle, card.URL)
+	}
+
+	ctx.HTML(http.StatusOK, "social_card.html", card)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,137 @@
+package api
+
+import (
+	"fmt"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsCrawler(t *testing.T) {
+	testCases := []struct {
+		userAgent string
+		crawler   bool
+	}{
+		{userAgent: "facebookexternalhit/1.1 (+http://www.facebook.com/externalhit_uatext.php)", crawler: true},
+		{userAgent: "Twitterbot/1.0", crawler: true},
+		{userAgent: "Mozilla/5.0 (compatible; Discordbot/2.0; +https://discordapp.com)", crawler: true},
+		{userAgent: "Slackbot-LinkExpanding 1.0 (+https://api.slack.com/robots)", crawler: true},
+		{userAgent: "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 Chrome/120.0 Safari/537.36", crawler: false},
+		// Search engines should index the destination, so they get the redirect
+		{userAgent: "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", crawler: false},
+		{userAgent: "Mozilla/5.0 (compatible; bingbot/2.0; +http://www.bing.com/bingbot.htm)", crawler: false},
+		{userAgent: "", crawler: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.userAgent, func(t *testing.T) {
+			require.Equal(t, tc.crawler, isCrawler(tc.userAgent))
+		})
+	}
+}
+
+func TestSocialCard(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+
+	link := createRandomLink(user.ID)
+	link.OgTitle = `Launch "day" <announcement>`
+	link.OgDescription = util.RandomString(20)
+	link.OgImage = "https://cdn.example.com/card.png"
+
+	bareLink := createRandomLink(user.ID)
+	bareLink.MetaTitle = util.RandomString(12)
+
+	testCases := []struct {
+		name          string
+		userAgent     string
+		code          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:      "Crawler",
+			userAgent: "Twitterbot/1.0",
+			code:      link.Code,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Eq(link.Code)).
+					Times(1).
+					Return(link, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				require.Contains(t, recorder.Header().Get("Content-Type"), "text/html")
+
+				body := recorder.Body.String()
+				require.Contains(t, body, `<meta property="og:title" content="Launch &#34;day&#34; &lt;announcement&gt;">`)
+				require.Contains(t, body, fmt.Sprintf(`<meta property="og:description" content="%s">`, link.OgDescription))
+				require.Contains(t, body, `<meta property="og:image" content="https://cdn.example.com/card.png">`)
+				require.Contains(t, body, fmt.Sprintf(`<meta http-equiv="refresh" content="0; url=%s">`, link.Link))
+			},
+		},
+		{
+			name:      "CrawlerFallsBackToFetchedTitle",
+			userAgent: "facebookexternalhit/1.1",
+			code:      bareLink.Code,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Eq(bareLink.Code)).
+					Times(1).
+					Return(bareLink, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				body := recorder.Body.String()
+				require.Contains(t, body, fmt.Sprintf(`<meta property="og:title" content="%s">`, bareLink.MetaTitle))
+				require.Contains(t, body, `<meta name="twitter:card" content="summary">`)
+				require.NotContains(t, body, "og:image")
+			},
+		},
+		{
+			name:      "Browser",
+			userAgent: "Mozilla/5.0 (X11; Linux x86_64) Chrome/120.0",
+			code:      link.Code,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLinkByCode(gomock.Any(), gomock.Eq(link.Code)).
+					Times(1).
+					Return(link, nil)
+
+				store.EXPECT().
+					RecordClick(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(int64(1), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusPermanentRedirect, recorder.Code)
+				require.Equal(t, link.Link, recorder.Header().Get("Location"))
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodGet, "/"+tc.code, nil)
+			require.NoError(t, err)
+			request.Header.Set("User-Agent", tc.userAgent)
+
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
@@ -0,0 +1,231 @@
+package api
+
+import (
+	"errors"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/token"
+	"github.com/gin-gonic/gin"
+	"net/http"
+)
+
+var ErrTagNotOwned = errors.New("tag does not belong to this user")
+
+type tagNameParams struct {
+	Name string `json:"name" binding:"required,max=64"`
+}
+
+type tagIDParams struct {
+	ID int64 `uri:"id" binding:"required,number,min=1"`
+}
+
+type tagLinksParams struct {
+	LinkIDs []int64 `json:"link_ids" binding:"required,min=1,max=1000,dive,min=1"`
+}
+
+type tagLinksResponse struct {
+	Affected int64 `json:"affected"`
+}
+
+func (server *Server) CreateTag(ctx *gin.Context) {
+	var req tagNameParams
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	arg := db.CreateTagParams{
+		UserID: authPayload.UserID,
+		Name:   req.Name,
+	}
+
+	tag, err := server.store.CreateTag(ctx, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, successResponse(tag, http.StatusCreated))
+}
+
+func (server *Server) GetTags(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	tags, err := server.store.GetTagsByUser(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(tags, http.StatusOK))
+}
+
+func (server *Server) RenameTag(ctx *gin.Context) {
+	var req tagNameParams
+
+	tag, ok := server.ownedTag(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	arg := db.UpdateTagParams{
+		ID:   tag.ID,
+		Name: req.Name,
+	}
+
+	tag, err := server.store.UpdateTag(ctx, arg)
+	if err != nil {
+		if db.ErrorCode(err) == db.UniqueViolation {
+			ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(tag, http.StatusOK))
+}
+
+func (server *Server) DeleteTag(ctx *gin.Context) {
+	tag, ok := server.ownedTag(ctx)
+	if !ok {
+		return
+	}
+
+	if err := server.store.DeleteTag(ctx, tag.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(tag, http.StatusOK))
+}
+
+// AttachTag adds the tag to every listed link the caller owns, links already carrying it are skipped
+func (server *Server) AttachTag(ctx *gin.Context) {
+	var req tagLinksParams
+
+	tag, ok := server.ownedTag(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	arg := db.AttachTagParams{
+		TagID:   tag.ID,
+		LinkIds: req.LinkIDs,
+		UserID:  tag.UserID,
+	}
+
+	affected, err := server.store.AttachTag(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(tagLinksResponse{Affected: affected}, http.StatusOK))
+}
+
+func (server *Server) DetachTag(ctx *gin.Context) {
+	var req tagLinksParams
+
+	tag, ok := server.ownedTag(ctx)
+	if !ok {
+		return
+	}
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	arg := db.DetachTagParams{
+		TagID:   tag.ID,
+		LinkIds: req.LinkIDs,
+	}
+
+	affected, err := server.store.DetachTag(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(tagLinksResponse{Affected: affected}, http.StatusOK))
+}
+
+func (server *Server) GetLinkTags(ctx *gin.Context) {
+	var req getLinkByIDParams
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	link, err := server.store.GetLinkById(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err, http.StatusNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if link.UserID != authPayload.UserID {
+		ctx.JSON(http.StatusForbidden, errorResponse(err, http.StatusForbidden))
+		return
+	}
+
+	tags, err := server.store.GetTagsByLink(ctx, link.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(tags, http.StatusOK))
+}
+
+// ownedTag loads the tag named in the uri and writes the error response when the caller cannot use it
+func (server *Server) ownedTag(ctx *gin.Context) (db.Tag, bool) {
+	var req tagIDParams
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return db.Tag{}, false
+	}
+
+	tag, err := server.store.GetTag(ctx, req.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err, http.StatusNotFound))
+			return db.Tag{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return db.Tag{}, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if tag.UserID != authPayload.UserID {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrTagNotOwned, http.StatusForbidden))
+		return db.Tag{}, false
+	}
+
+	return tag, true
+}
@@ -0,0 +1,370 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/token"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func createRandomTag(userId int64) db.Tag {
+	return db.Tag{
+		ID:        util.RandomInt(1, 100),
+		UserID:    userId,
+		Name:      util.RandomString(8),
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestCreateTag(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	tag := createRandomTag(user.ID)
+
+	testCases := []struct {
+		name          string
+		payload       gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			payload: gin.H{"name": tag.Name},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateTag(gomock.Any(), gomock.Eq(db.CreateTagParams{UserID: user.ID, Name: tag.Name})).
+					Times(1).
+					Return(tag, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name:    "NoName",
+			payload: gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateTag(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "DuplicateName",
+			payload: gin.H{"name": tag.Name},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateTag(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Tag{}, &pgconn.PgError{Code: db.UniqueViolation})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "InternalServerError",
+			payload: gin.H{"name": tag.Name},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateTag(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Tag{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/tags", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestAttachTag(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	tag := createRandomTag(user.ID)
+	linkIDs := []int64{1, 2, 3}
+
+	testCases := []struct {
+		name          string
+		method        string
+		tagID         int64
+		payload       gin.H
+		setupAuth     func(t *testing.T, request *http.Request, tokenMaker token.Maker)
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "Attach",
+			method:  http.MethodPost,
+			tagID:   tag.ID,
+			payload: gin.H{"link_ids": linkIDs},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.AttachTagParams{
+					TagID:   tag.ID,
+					LinkIds: linkIDs,
+					UserID:  user.ID,
+				}
+
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetTag(gomock.Any(), gomock.Eq(tag.ID)).
+					Times(1).
+					Return(tag, nil)
+
+				store.EXPECT().
+					AttachTag(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(int64(2), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response Response[tagLinksResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, int64(2), response.Data.Affected)
+			},
+		},
+		{
+			name:    "Detach",
+			method:  http.MethodDelete,
+			tagID:   tag.ID,
+			payload: gin.H{"link_ids": linkIDs},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				arg := db.DetachTagParams{
+					TagID:   tag.ID,
+					LinkIds: linkIDs,
+				}
+
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetTag(gomock.Any(), gomock.Eq(tag.ID)).
+					Times(1).
+					Return(tag, nil)
+
+				store.EXPECT().
+					DetachTag(gomock.Any(), gomock.Eq(arg)).
+					Times(1).
+					Return(int64(3), nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:    "EmptyLinkIDs",
+			method:  http.MethodPost,
+			tagID:   tag.ID,
+			payload: gin.H{"link_ids": []int64{}},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetTag(gomock.Any(), gomock.Eq(tag.ID)).
+					Times(1).
+					Return(tag, nil)
+
+				store.EXPECT().
+					AttachTag(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "TagNotOwned",
+			method:  http.MethodPost,
+			tagID:   tag.ID,
+			payload: gin.H{"link_ids": linkIDs},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID+1, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID+1)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetTag(gomock.Any(), gomock.Eq(tag.ID)).
+					Times(1).
+					Return(tag, nil)
+
+				store.EXPECT().
+					AttachTag(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:    "TagNotFound",
+			method:  http.MethodPost,
+			tagID:   tag.ID,
+			payload: gin.H{"link_ids": linkIDs},
+			setupAuth: func(t *testing.T, request *http.Request, tokenMaker token.Maker) {
+				addAuthorization(t, request, tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetTag(gomock.Any(), gomock.Eq(tag.ID)).
+					Times(1).
+					Return(db.Tag{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			url := fmt.Sprintf("/tags/%d/links", tc.tagID)
+			request, err := http.NewRequest(tc.method, url, bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			tc.setupAuth(t, request, server.tokenMaker)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetLinksFilteredByTag(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	links := []db.Link{createRandomLink(user.ID)}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().
+		GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+		Times(1).
+		Return(user, nil)
+
+	store.EXPECT().
+		GetLinksByUser(gomock.Any(), gomock.Eq(db.GetLinksByUserParams{
+			UserID:   user.ID,
+			TagID:    pgtype.Int8{Int64: 7, Valid: true},
+			FolderID: pgtype.Int8{Int64: 3, Valid: true},
+			Sort:     "-created",
+			Limit:    11,
+		})).
+		Times(1).
+		Return(links, nil)
+
+	store.EXPECT().
+		CountLinksByUser(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(int64(len(links)), nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/links?tag_id=7&folder_id=3", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+}
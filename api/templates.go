@@ -0,0 +1,12 @@
+package api
+
+import (
+	"embed"
+	"html/template"
+)
+
+//go:embed templates/*.html
+var templateFS embed.FS
+
+// templates holds every server-rendered page; handlers render them with ctx.HTML
+var templates = template.Must(template.ParseFS(templateFS, "templates/*.html"))
@@ -4,8 +4,11 @@ import (
 	"errors"
 	"fmt"
 	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/token"
 	"github.com/bolusarz/urlmini/util"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"log"
 	"net/http"
 	"time"
 )
@@ -25,6 +28,9 @@ type userResponse struct {
 	LastName          string    `json:"last_name"`
 	CreatedAt         time.Time `json:"created_at"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
+	EmailVerified     bool      `json:"email_verified"`
+
+	ReuseDuplicateLinks bool `json:"reuse_duplicate_links"`
 }
 
 func newUserResponse(user db.User) userResponse {
@@ -35,6 +41,9 @@ func newUserResponse(user db.User) userResponse {
 		LastName:          user.LastName,
 		CreatedAt:         user.CreatedAt,
 		PasswordChangedAt: user.PasswordChangedAt.Time,
+		EmailVerified:     user.EmailVerifiedAt.Valid,
+
+		ReuseDuplicateLinks: user.ReuseDuplicateLinks,
 	}
 }
 
@@ -72,6 +81,11 @@ func (server *Server) createUser(ctx *gin.Context) {
 		return
 	}
 
+	// A lost email can be sent again, so signing up does not depend on it
+	if err := server.sendEmailVerification(ctx, user); err != nil {
+		log.Printf("cannot send verification email to user %d: %v", user.ID, err)
+	}
+
 	userResponse := newUserResponse(user)
 
 	ctx.JSON(http.StatusCreated, successResponse(userResponse, http.StatusCreated))
@@ -127,6 +141,7 @@ func (server *Server) loginUser(ctx *gin.Context) {
 	}
 
 	arg := db.CreateSessionParams{
+		ID:           refreshPayload.ID,
 		UserID:       user.ID,
 		UserAgent:    ctx.Request.UserAgent(),
 		ClientIp:     ctx.RemoteIP(),
@@ -151,3 +166,110 @@ func (server *Server) loginUser(ctx *gin.Context) {
 	}, http.StatusOK))
 
 }
+
+type updatePreferencesRequest struct {
+	ReuseDuplicateLinks *bool `json:"reuse_duplicate_links" binding:"required"`
+}
+
+// UpdatePreferences changes the defaults applied to the caller's requests
+func (server *Server) UpdatePreferences(ctx *gin.Context) {
+	var req updatePreferencesRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	user, err := server.store.UpdateUserPreferences(ctx, db.UpdateUserPreferencesParams{
+		ID:                  authPayload.UserID,
+		ReuseDuplicateLinks: *req.ReuseDuplicateLinks,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(newUserResponse(user), http.StatusOK))
+}
+
+type changePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8,max=72"`
+	// RefreshToken names the caller's session, which stays signed in while every other one is blocked
+	RefreshToken string `json:"refresh_token"`
+}
+
+type changePasswordResponse struct {
+	AccessToken          string       `json:"access_token"`
+	AccessTokenExpiresAt time.Time    `json:"access_token_expires_at"`
+	User                 userResponse `json:"user"`
+}
+
+var (
+	ErrWrongPassword       = errors.New("current password is incorrect")
+	ErrInvalidRefreshToken = errors.New("refresh token does not belong to an active session of this user")
+)
+
+// ChangePassword replaces the caller's password. Access tokens issued before the change stop working,
+// so a fresh one comes back with the response.
+func (server *Server) ChangePassword(ctx *gin.Context) {
+	var req changePasswordRequest
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	user := ctx.MustGet(authorizedUserKey).(db.User)
+
+	if err := util.CheckPassword(req.CurrentPassword, user.HashedPassword); err != nil {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrWrongPassword, http.StatusForbidden))
+		return
+	}
+
+	var keepSessionID uuid.UUID
+	if req.RefreshToken != "" {
+		payload, err := server.tokenMaker.VerifyToken(req.RefreshToken)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ErrInvalidRefreshToken, http.StatusBadRequest))
+			return
+		}
+
+		session, err := server.store.GetSession(ctx, payload.ID)
+		if err != nil || session.UserID != user.ID || session.IsBlocked || time.Now().After(session.ExpiresAt) {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ErrInvalidRefreshToken, http.StatusBadRequest))
+			return
+		}
+		keepSessionID = session.ID
+	}
+
+	hashedPassword, err := util.HashPassword(req.NewPassword)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	user, err = server.store.ChangePasswordTx(ctx, db.ChangePasswordTxParams{
+		UserID:         user.ID,
+		HashedPassword: hashedPassword,
+		KeepSessionID:  keepSessionID,
+	})
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	accessToken, accessPayload, err := server.tokenMaker.CreateToken(user.ID, server.config.AccessTokenDuration)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(changePasswordResponse{
+		AccessToken:          accessToken,
+		AccessTokenExpiresAt: accessPayload.ExpireAt,
+		User:                 newUserResponse(user),
+	}, http.StatusOK))
+}
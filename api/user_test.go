@@ -2,6 +2,7 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
@@ -9,6 +10,7 @@ import (
 	db "github.com/bolusarz/urlmini/db/sqlc"
 	"github.com/bolusarz/urlmini/util"
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/mock/gomock"
 	"io"
@@ -16,6 +18,7 @@ import (
 	"net/http/httptest"
 	"reflect"
 	"testing"
+	"time"
 )
 
 type eqCreateUserParamsMatcher struct {
@@ -95,12 +98,46 @@ func TestCreateAccount(t *testing.T) {
 					CreateUser(gomock.Any(), EqCreateUserParams(args, password)).
 					Times(1).
 					Return(user, nil)
+
+				store.EXPECT().
+					RevokeUserTokens(gomock.Any(), gomock.Eq(db.RevokeUserTokensParams{UserID: user.ID, Purpose: db.UserTokenEmailVerification})).
+					Times(1).
+					Return(nil)
+
+				store.EXPECT().
+					CreateUserToken(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.UserToken{}, nil)
 			},
 			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
 				require.Equal(t, http.StatusCreated, recorder.Code)
 				requireBodyMatchUser(t, recorder.Body, user)
 			},
 		},
+		{
+			name: "VerificationEmailFails",
+			payload: gin.H{
+				"username":   user.Username,
+				"password":   password,
+				"first_name": user.FirstName,
+				"last_name":  user.LastName,
+				"email":      user.Email,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					CreateUser(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					RevokeUserTokens(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
 		{
 			name: "UserAlreadyExists",
 			payload: gin.H{
@@ -365,6 +402,140 @@ func TestLogin(t *testing.T) {
 	}
 }
 
+func TestLoginStoresSessionUnderRefreshToken(t *testing.T) {
+	user, password := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var session db.CreateSessionParams
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		GetUser(gomock.Any(), gomock.Eq(user.Username)).
+		Times(1).
+		Return(user, nil)
+
+	store.EXPECT().
+		CreateSession(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ context.Context, arg db.CreateSessionParams) (db.Session, error) {
+			session = arg
+			return db.Session{ID: arg.ID, UserID: arg.UserID}, nil
+		})
+
+	server := newTestServer(t, store)
+	server.config.RefreshTokenDuration = time.Hour
+	recorder := httptest.NewRecorder()
+
+	jsonBody, err := json.Marshal(gin.H{"username": user.Username, "password": password})
+	require.NoError(t, err)
+
+	request, err := http.NewRequest(http.MethodPost, "/login", bytes.NewBuffer(jsonBody))
+	require.NoError(t, err)
+
+	server.router.ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response Response[loginUserResponse]
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+
+	// Refreshing looks the session up by the refresh token's ID
+	payload, err := server.tokenMaker.VerifyToken(response.Data.RefreshToken)
+	require.NoError(t, err)
+	require.NotEqual(t, uuid.Nil, session.ID)
+	require.Equal(t, payload.ID, session.ID)
+}
+
+func TestUpdatePreferences(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+
+	testCases := []struct {
+		name          string
+		payload       gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			payload: gin.H{"reuse_duplicate_links": true},
+			buildStubs: func(store *mockdb.MockStore) {
+				updated := user
+				updated.ReuseDuplicateLinks = true
+
+				store.EXPECT().
+					UpdateUserPreferences(gomock.Any(), gomock.Eq(db.UpdateUserPreferencesParams{
+						ID:                  user.ID,
+						ReuseDuplicateLinks: true,
+					})).
+					Times(1).
+					Return(updated, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response Response[userResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.True(t, response.Data.ReuseDuplicateLinks)
+			},
+		},
+		{
+			name:    "Missing",
+			payload: gin.H{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserPreferences(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "InternalServerError",
+			payload: gin.H{"reuse_duplicate_links": false},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserPreferences(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+				Times(1).
+				Return(user, nil)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPatch, "/users/me/preferences", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
 func requireBodyMatchUser(t *testing.T, body *bytes.Buffer, user db.User) {
 	data, err := io.ReadAll(body)
 	require.NoError(t, err)
@@ -401,3 +572,178 @@ func requireBodyMatchLoggedInUser(t *testing.T, body *bytes.Buffer, user db.User
 	require.NotEmpty(t, responseData["access_token"])
 	require.NotEmpty(t, responseData["refresh_token"])
 }
+
+type eqChangePasswordTxParamsMatcher struct {
+	userID        int64
+	password      string
+	keepSessionID uuid.UUID
+}
+
+func (e eqChangePasswordTxParamsMatcher) Matches(x any) bool {
+	arg, ok := x.(db.ChangePasswordTxParams)
+	if !ok {
+		return false
+	}
+
+	return arg.UserID == e.userID &&
+		arg.KeepSessionID == e.keepSessionID &&
+		util.CheckPassword(e.password, arg.HashedPassword) == nil
+}
+
+func (e eqChangePasswordTxParamsMatcher) String() string {
+	return fmt.Sprintf("matches user %v, password %v and kept session %v", e.userID, e.password, e.keepSessionID)
+}
+
+func TestChangePassword(t *testing.T) {
+	user, password := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	newPassword := util.RandomString(12)
+
+	testCases := []struct {
+		name          string
+		password      string
+		newPassword   string
+		refreshUserID int64
+		buildStubs    func(store *mockdb.MockStore, session db.Session)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder, server *Server)
+	}{
+		{
+			name:        "OK",
+			password:    password,
+			newPassword: newPassword,
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					ChangePasswordTx(gomock.Any(), eqChangePasswordTxParamsMatcher{userID: user.ID, password: newPassword}).
+					Times(1).
+					Return(user, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, server *Server) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+
+				var response Response[changePasswordResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+
+				payload, err := server.tokenMaker.VerifyToken(response.Data.AccessToken)
+				require.NoError(t, err)
+				require.Equal(t, user.ID, payload.UserID)
+			},
+		},
+		{
+			name:          "KeepsCurrentSession",
+			password:      password,
+			newPassword:   newPassword,
+			refreshUserID: user.ID,
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+
+				store.EXPECT().
+					ChangePasswordTx(gomock.Any(), eqChangePasswordTxParamsMatcher{userID: user.ID, password: newPassword, keepSessionID: session.ID}).
+					Times(1).
+					Return(user, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, server *Server) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name:          "SessionOfAnotherUser",
+			password:      password,
+			newPassword:   newPassword,
+			refreshUserID: user.ID + 1,
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					GetSession(gomock.Any(), gomock.Eq(session.ID)).
+					Times(1).
+					Return(session, nil)
+
+				store.EXPECT().
+					ChangePasswordTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, server *Server) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "WrongPassword",
+			password:    "wrong-password",
+			newPassword: newPassword,
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					ChangePasswordTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, server *Server) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name:        "ShortNewPassword",
+			password:    password,
+			newPassword: "short",
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					ChangePasswordTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, server *Server) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:        "InternalError",
+			password:    password,
+			newPassword: newPassword,
+			buildStubs: func(store *mockdb.MockStore, session db.Session) {
+				store.EXPECT().
+					ChangePasswordTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder, server *Server) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().
+				GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+				Times(1).
+				Return(user, nil)
+
+			server := newTestServer(t, store)
+			payload := gin.H{"current_password": tc.password, "new_password": tc.newPassword}
+
+			var session db.Session
+			if tc.refreshUserID != 0 {
+				refreshToken, refreshPayload, err := server.tokenMaker.CreateToken(tc.refreshUserID, time.Hour)
+				require.NoError(t, err)
+
+				session = db.Session{ID: refreshPayload.ID, UserID: tc.refreshUserID, ExpiresAt: refreshPayload.ExpireAt}
+				payload["refresh_token"] = refreshToken
+			}
+			tc.buildStubs(store, session)
+
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/users/me/password", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder, server)
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/token"
+	"github.com/bolusarz/urlmini/webhook"
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgtype"
+	"net/http"
+	"time"
+)
+
+var (
+	ErrWebhookNotOwned  = errors.New("webhook does not belong to this user")
+	ErrDeliveryNotFound = errors.New("delivery does not belong to this webhook")
+)
+
+type createWebhookParams struct {
+	Url    string   `json:"url" binding:"required,http_url,max=2048"`
+	Events []string `json:"events" binding:"required,min=1,dive,oneof=link.expired link.click_limit_reached link.unhealthy link.clicked"`
+
+	// Clicks are sent once this many are queued or the oldest has waited this long, whichever comes first
+	BatchSize           int32 `json:"batch_size" binding:"omitempty,min=1,max=1000"`
+	BatchMaxWaitSeconds int32 `json:"batch_max_wait_seconds" binding:"omitempty,min=1,max=3600"`
+}
+
+// webhookResponse leaves out the secret, which is only shown once when the webhook is created
+type webhookResponse struct {
+	ID                  int64     `json:"id"`
+	Url                 string    `json:"url"`
+	Events              []string  `json:"events"`
+	Active              bool      `json:"active"`
+	BatchSize           int32     `json:"batch_size"`
+	BatchMaxWaitSeconds int32     `json:"batch_max_wait_seconds"`
+	CreatedAt           time.Time `json:"created_at"`
+}
+
+type createWebhookResponse struct {
+	webhookResponse
+	Secret string `json:"secret"`
+}
+
+type deliveryResponse struct {
+	ID             int64              `json:"id"`
+	Event          string             `json:"event"`
+	Payload        json.RawMessage    `json:"payload"`
+	Status         string             `json:"status"`
+	Attempts       int32              `json:"attempts"`
+	NextAttemptAt  time.Time          `json:"next_attempt_at"`
+	ResponseStatus int32              `json:"response_status"`
+	Error          string             `json:"error"`
+	DeliveredAt    pgtype.Timestamptz `json:"delivered_at"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+type webhookDeliveryCursor struct {
+	ID int64 `json:"i"`
+}
+
+type webhookDeliveryParams struct {
+	ID         int64 `uri:"id" binding:"required,number,min=1"`
+	DeliveryID int64 `uri:"delivery_id" binding:"required,number,min=1"`
+}
+
+func newWebhookResponse(hook db.Webhook) webhookResponse {
+	return webhookResponse{
+		ID:                  hook.ID,
+		Url:                 hook.Url,
+		Events:              hook.Events,
+		Active:              hook.Active,
+		BatchSize:           hook.BatchSize,
+		BatchMaxWaitSeconds: hook.BatchMaxWaitSeconds,
+		CreatedAt:           hook.CreatedAt,
+	}
+}
+
+func newDeliveryResponse(delivery db.WebhookDelivery) deliveryResponse {
+	return deliveryResponse{
+		ID:             delivery.ID,
+		Event:          delivery.Event,
+		Payload:        delivery.Payload,
+		Status:         delivery.Status,
+		Attempts:       delivery.Attempts,
+		NextAttemptAt:  delivery.NextAttemptAt,
+		ResponseStatus: delivery.ResponseStatus,
+		Error:          delivery.Error,
+		DeliveredAt:    delivery.DeliveredAt,
+		CreatedAt:      delivery.CreatedAt,
+	}
+}
+
+func (server *Server) CreateWebhook(ctx *gin.Context) {
+	var req createWebhookParams
+
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	arg := db.CreateWebhookParams{
+		UserID:              authPayload.UserID,
+		Url:                 req.Url,
+		Secret:              secret,
+		Events:              req.Events,
+		BatchSize:           req.BatchSize,
+		BatchMaxWaitSeconds: req.BatchMaxWaitSeconds,
+	}
+
+	if arg.BatchSize == 0 {
+		arg.BatchSize = webhook.DefaultClickBatchSize
+	}
+
+	if arg.BatchMaxWaitSeconds == 0 {
+		arg.BatchMaxWaitSeconds = int32(webhook.DefaultClickBatchMaxWait.Seconds())
+	}
+
+	hook, err := server.store.CreateWebhook(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	rsp := createWebhookResponse{
+		webhookResponse: newWebhookResponse(hook),
+		Secret:          hook.Secret,
+	}
+
+	ctx.JSON(http.StatusCreated, successResponse(rsp, http.StatusCreated))
+}
+
+func (server *Server) GetWebhooks(ctx *gin.Context) {
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	hooks, err := server.store.GetWebhooksByUser(ctx, authPayload.UserID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	rsp := make([]webhookResponse, len(hooks))
+	for i, hook := range hooks {
+		rsp[i] = newWebhookResponse(hook)
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(rsp, http.StatusOK))
+}
+
+func (server *Server) DeleteWebhook(ctx *gin.Context) {
+	var req getLinkByIDParams
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	hook, ok := server.ownedWebhook(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	if err := server.store.DeleteWebhook(ctx, hook.ID); err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, successResponse(nil, http.StatusOK))
+}
+
+func (server *Server) GetWebhookDeliveries(ctx *gin.Context) {
+	var uri getLinkByIDParams
+	var req pageParams
+	var cursor webhookDeliveryCursor
+
+	if err := ctx.ShouldBindUri(&uri); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	hook, ok := server.ownedWebhook(ctx, uri.ID)
+	if !ok {
+		return
+	}
+
+	pageSize := pageSizeOrDefault(req.PageSize)
+
+	arg := db.GetWebhookDeliveriesParams{
+		WebhookID: hook.ID,
+		Limit:     pageSize + 1,
+	}
+
+	if req.Cursor != "" {
+		if err := server.decodeCursor(req.Cursor, &cursor); err != nil {
+			ctx.JSON(http.StatusBadRequest, errorResponse(ErrInvalidCursor, http.StatusBadRequest))
+			return
+		}
+		arg.CursorID = pgtype.Int8{Int64: cursor.ID, Valid: true}
+	}
+
+	deliveries, err := server.store.GetWebhookDeliveries(ctx, arg)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	total, err := server.store.CountWebhookDeliveries(ctx, hook.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	deliveries, hasNext, _ := paginate(deliveries, pageSize, false, false)
+	page := pagination{Total: total}
+
+	if hasNext {
+		page.NextCursor, err = server.encodeCursor(webhookDeliveryCursor{ID: deliveries[len(deliveries)-1].ID})
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+			return
+		}
+	}
+
+	rsp := make([]deliveryResponse, len(deliveries))
+	for i, delivery := range deliveries {
+		rsp[i] = newDeliveryResponse(delivery)
+	}
+
+	ctx.JSON(http.StatusOK, pageResponse(rsp, page, http.StatusOK))
+}
+
+// RedeliverWebhookDelivery queues a delivery again with a fresh set of attempts, whatever its status
+func (server *Server) RedeliverWebhookDelivery(ctx *gin.Context) {
+	var req webhookDeliveryParams
+
+	if err := ctx.ShouldBindUri(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, errorResponse(err, http.StatusBadRequest))
+		return
+	}
+
+	hook, ok := server.ownedWebhook(ctx, req.ID)
+	if !ok {
+		return
+	}
+
+	delivery, err := server.store.GetWebhookDelivery(ctx, req.DeliveryID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err, http.StatusNotFound))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	if delivery.WebhookID != hook.ID {
+		ctx.JSON(http.StatusNotFound, errorResponse(ErrDeliveryNotFound, http.StatusNotFound))
+		return
+	}
+
+	delivery, err = server.store.RedeliverWebhookDelivery(ctx, delivery.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, successResponse(newDeliveryResponse(delivery), http.StatusAccepted))
+}
+
+// ownedWebhook loads a webhook and writes the error response when the caller cannot see it
+func (server *Server) ownedWebhook(ctx *gin.Context, id int64) (db.Webhook, bool) {
+	hook, err := server.store.GetWebhook(ctx, id)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			ctx.JSON(http.StatusNotFound, errorResponse(err, http.StatusNotFound))
+			return db.Webhook{}, false
+		}
+		ctx.JSON(http.StatusInternalServerError, errorResponse(err, http.StatusInternalServerError))
+		return db.Webhook{}, false
+	}
+
+	authPayload := ctx.MustGet(authorizationPayloadKey).(*token.Payload)
+
+	if hook.UserID != authPayload.UserID {
+		ctx.JSON(http.StatusForbidden, errorResponse(ErrWebhookNotOwned, http.StatusForbidden))
+		return db.Webhook{}, false
+	}
+
+	return hook, true
+}
@@ -0,0 +1,518 @@
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/bolusarz/urlmini/webhook"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func createRandomWebhook(userId int64) db.Webhook {
+	return db.Webhook{
+		ID:        util.RandomInt(1, 100),
+		UserID:    userId,
+		Url:       util.RandomLink(),
+		Secret:    util.RandomString(32),
+		Events:    []string{webhook.EventLinkExpired},
+		Active:    true,
+		CreatedAt: time.Now(),
+		BatchSize: webhook.DefaultClickBatchSize,
+	}
+}
+
+func TestCreateWebhook(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	hook := createRandomWebhook(user.ID)
+
+	testCases := []struct {
+		name          string
+		payload       gin.H
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name:    "OK",
+			payload: gin.H{"url": hook.Url, "events": hook.Events},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateWebhook(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.CreateWebhookParams) (db.Webhook, error) {
+						require.Equal(t, user.ID, arg.UserID)
+						require.Equal(t, hook.Url, arg.Url)
+						require.Equal(t, hook.Events, arg.Events)
+						require.True(t, strings.HasPrefix(arg.Secret, "whsec_"))
+						require.Equal(t, int32(webhook.DefaultClickBatchSize), arg.BatchSize)
+						require.Equal(t, int32(60), arg.BatchMaxWaitSeconds)
+						hook.Secret = arg.Secret
+						return hook, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+
+				var response Response[createWebhookResponse]
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+				require.Equal(t, hook.ID, response.Data.ID)
+				require.Equal(t, hook.Secret, response.Data.Secret)
+			},
+		},
+		{
+			name: "ClickBatches",
+			payload: gin.H{
+				"url":                    hook.Url,
+				"events":                 []string{webhook.EventLinkClicked},
+				"batch_size":             500,
+				"batch_max_wait_seconds": 5,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateWebhook(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.CreateWebhookParams) (db.Webhook, error) {
+						require.Equal(t, []string{webhook.EventLinkClicked}, arg.Events)
+						require.Equal(t, int32(500), arg.BatchSize)
+						require.Equal(t, int32(5), arg.BatchMaxWaitSeconds)
+						return hook, nil
+					})
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusCreated, recorder.Code)
+			},
+		},
+		{
+			name: "BatchTooLarge",
+			payload: gin.H{
+				"url":        hook.Url,
+				"events":     []string{webhook.EventLinkClicked},
+				"batch_size": 5000,
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateWebhook(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "UnknownEvent",
+			payload: gin.H{"url": hook.Url, "events": []string{"link.created"}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateWebhook(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "NoEvents",
+			payload: gin.H{"url": hook.Url, "events": []string{}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateWebhook(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "InvalidURL",
+			payload: gin.H{"url": "ftp://example.com", "events": hook.Events},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateWebhook(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name:    "InternalServerError",
+			payload: gin.H{"url": hook.Url, "events": hook.Events},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					CreateWebhook(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Webhook{}, sql.ErrConnDone)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			jsonBody, err := json.Marshal(tc.payload)
+			require.NoError(t, err)
+
+			request, err := http.NewRequest(http.MethodPost, "/webhooks", bytes.NewBuffer(jsonBody))
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetWebhooks(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	hooks := []db.Webhook{createRandomWebhook(user.ID), createRandomWebhook(user.ID)}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().
+		GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+		Times(1).
+		Return(user, nil)
+
+	store.EXPECT().
+		GetWebhooksByUser(gomock.Any(), gomock.Eq(user.ID)).
+		Times(1).
+		Return(hooks, nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, "/webhooks", nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+	require.NotContains(t, recorder.Body.String(), hooks[0].Secret)
+
+	var response Response[[]webhookResponse]
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response.Data, 2)
+}
+
+func TestDeleteWebhook(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	hook := createRandomWebhook(user.ID)
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetWebhook(gomock.Any(), gomock.Eq(hook.ID)).
+					Times(1).
+					Return(hook, nil)
+
+				store.EXPECT().
+					DeleteWebhook(gomock.Any(), gomock.Eq(hook.ID)).
+					Times(1).
+					Return(nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+			},
+		},
+		{
+			name: "NotOwned",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				other := hook
+				other.UserID = user.ID + 1
+
+				store.EXPECT().
+					GetWebhook(gomock.Any(), gomock.Eq(hook.ID)).
+					Times(1).
+					Return(other, nil)
+
+				store.EXPECT().
+					DeleteWebhook(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusForbidden, recorder.Code)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetWebhook(gomock.Any(), gomock.Eq(hook.ID)).
+					Times(1).
+					Return(db.Webhook{}, db.ErrRecordNotFound)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			request, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/webhooks/%d", hook.ID), nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
+
+func TestGetWebhookDeliveries(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	hook := createRandomWebhook(user.ID)
+
+	deliveries := make([]db.WebhookDelivery, 3)
+	for i := range deliveries {
+		deliveries[i] = db.WebhookDelivery{
+			ID:        int64(30 - i),
+			WebhookID: hook.ID,
+			Event:     webhook.EventLinkExpired,
+			Payload:   []byte(`{"event":"link.expired"}`),
+			Status:    webhook.StatusPending,
+		}
+	}
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	store.EXPECT().
+		GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+		Times(1).
+		Return(user, nil)
+
+	store.EXPECT().
+		GetWebhook(gomock.Any(), gomock.Eq(hook.ID)).
+		Times(1).
+		Return(hook, nil)
+
+	store.EXPECT().
+		GetWebhookDeliveries(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ any, arg db.GetWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+			require.Equal(t, hook.ID, arg.WebhookID)
+			require.False(t, arg.CursorID.Valid)
+			require.Equal(t, int32(3), arg.Limit)
+			return deliveries, nil
+		})
+
+	store.EXPECT().
+		CountWebhookDeliveries(gomock.Any(), gomock.Eq(hook.ID)).
+		Times(1).
+		Return(int64(7), nil)
+
+	server := newTestServer(t, store)
+	recorder := httptest.NewRecorder()
+
+	request, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/webhooks/%d/deliveries?page_size=2", hook.ID), nil)
+	require.NoError(t, err)
+
+	addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+	server.router.ServeHTTP(recorder, request)
+
+	require.Equal(t, http.StatusOK, recorder.Code)
+
+	var response Response[[]deliveryResponse]
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	require.Len(t, response.Data, 2)
+	require.JSONEq(t, `{"event":"link.expired"}`, string(response.Data[0].Payload))
+
+	page := requirePagination(t, recorder)
+	require.Equal(t, int64(7), page.Total)
+	require.NotEmpty(t, page.NextCursor)
+
+	var cursor webhookDeliveryCursor
+	require.NoError(t, server.decodeCursor(page.NextCursor, &cursor))
+	require.Equal(t, deliveries[1].ID, cursor.ID)
+}
+
+func TestRedeliverWebhookDelivery(t *testing.T) {
+	user, _ := randomUser(t)
+	user.ID = util.RandomInt(1, 20)
+	hook := createRandomWebhook(user.ID)
+	delivery := db.WebhookDelivery{ID: 12, WebhookID: hook.ID, Payload: []byte(`{}`), Status: webhook.StatusFailed}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetWebhook(gomock.Any(), gomock.Eq(hook.ID)).
+					Times(1).
+					Return(hook, nil)
+
+				store.EXPECT().
+					GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).
+					Times(1).
+					Return(delivery, nil)
+
+				redelivered := delivery
+				redelivered.Status = webhook.StatusPending
+
+				store.EXPECT().
+					RedeliverWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).
+					Times(1).
+					Return(redelivered, nil)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusAccepted, recorder.Code)
+			},
+		},
+		{
+			name: "DeliveryOfOtherWebhook",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetUserById(gomock.Any(), gomock.Eq(user.ID)).
+					Times(1).
+					Return(user, nil)
+
+				store.EXPECT().
+					GetWebhook(gomock.Any(), gomock.Eq(hook.ID)).
+					Times(1).
+					Return(hook, nil)
+
+				other := delivery
+				other.WebhookID = hook.ID + 1
+
+				store.EXPECT().
+					GetWebhookDelivery(gomock.Any(), gomock.Eq(delivery.ID)).
+					Times(1).
+					Return(other, nil)
+
+				store.EXPECT().
+					RedeliverWebhookDelivery(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			checkResponse: func(t *testing.T, recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusNotFound, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			url := fmt.Sprintf("/webhooks/%d/deliveries/%d/redeliver", hook.ID, delivery.ID)
+			request, err := http.NewRequest(http.MethodPost, url, nil)
+			require.NoError(t, err)
+
+			addAuthorization(t, request, server.tokenMaker, authorizationTypeBearer, user.ID, time.Minute)
+			server.router.ServeHTTP(recorder, request)
+			tc.checkResponse(t, recorder)
+		})
+	}
+}
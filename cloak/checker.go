@@ -0,0 +1,147 @@
+package cloak
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/bolusarz/urlmini/metadata"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultTimeout      = 10 * time.Second
+	DefaultMaxRedirects = 5
+)
+
+var (
+	// ErrInsecureDestination is returned for plain http pages, which browsers refuse to frame in an https page
+	ErrInsecureDestination = errors.New("cloak: only https destinations can be framed")
+	ErrFramingRefused      = errors.New("cloak: destination does not allow being framed")
+	ErrTooManyRedirects    = errors.New("cloak: too many redirects")
+	ErrUnexpectedStatus    = errors.New("cloak: unexpected response status")
+)
+
+type Options struct {
+	Timeout      time.Duration
+	MaxRedirects int
+	// AllowPrivateNetworks is handed to metadata.NewClient
+	AllowPrivateNetworks bool
+	// AllowInsecure lets plain http destinations through; it only exists so tests can reach httptest servers
+	AllowInsecure bool
+}
+
+// Checker finds out whether a destination lets other sites show it in a frame
+type Checker struct {
+	client        *http.Client
+	allowInsecure bool
+}
+
+func NewChecker(opts Options) *Checker {
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxRedirects == 0 {
+		opts.MaxRedirects = DefaultMaxRedirects
+	}
+
+	checker := &Checker{allowInsecure: opts.AllowInsecure}
+
+	checker.client = metadata.NewClient(metadata.ClientOptions{
+		Timeout: opts.Timeout,
+		// The frame follows redirects too, so the page it finally lands on is the one that must allow framing
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= opts.MaxRedirects {
+				return ErrTooManyRedirects
+			}
+			return checker.checkScheme(req.URL)
+		},
+		AllowPrivateNetworks: opts.AllowPrivateNetworks,
+	})
+
+	return checker
+}
+
+// Check requests the destination and returns ErrFramingRefused when its X-Frame-Options or
+// Content-Security-Policy frame-ancestors would keep it from loading in a frame on another site
+func (checker *Checker) Check(ctx context.Context, destination string) error {
+	target, err := url.Parse(destination)
+	if err != nil {
+		return fmt.Errorf("cloak: invalid url: %w", err)
+	}
+	if err := checker.checkScheme(target); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("User-Agent", "urlmini-cloak/1.0")
+
+	resp, err := checker.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+
+	if !Frameable(resp.Header) {
+		return ErrFramingRefused
+	}
+	return nil
+}
+
+// Frameable reports whether response headers let any other site frame the page.
+// Any X-Frame-Options value refuses it, since the only one naming other origins is obsolete.
+func Frameable(header http.Header) bool {
+	for _, value := range header.Values("X-Frame-Options") {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+
+	// Every policy sent applies, so a single restrictive frame-ancestors refuses framing
+	for _, policy := range header.Values("Content-Security-Policy") {
+		for _, directive := range strings.Split(policy, ";") {
+			fields := strings.Fields(strings.ToLower(directive))
+			if len(fields) == 0 || fields[0] != "frame-ancestors" {
+				continue
+			}
+			if !allowsAnyAncestor(fields[1:]) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func allowsAnyAncestor(sources []string) bool {
+	for _, source := range sources {
+		if source == "*" || source == "https:" {
+			return true
+		}
+	}
+	return false
+}
+
+func (checker *Checker) checkScheme(target *url.URL) error {
+	switch target.Scheme {
+	case "https":
+		return nil
+	case "http":
+		if checker.allowInsecure {
+			return nil
+		}
+		return ErrInsecureDestination
+	default:
+		return ErrInsecureDestination
+	}
+}
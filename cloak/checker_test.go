@@ -0,0 +1,65 @@
+package cloak
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFrameable(t *testing.T) {
+	testCases := []struct {
+		name   string
+		header http.Header
+		ok     bool
+	}{
+		{name: "NoHeaders", header: http.Header{}, ok: true},
+		{name: "Deny", header: http.Header{"X-Frame-Options": {"DENY"}}, ok: false},
+		{name: "SameOrigin", header: http.Header{"X-Frame-Options": {"sameorigin"}}, ok: false},
+		{name: "AncestorsSelf", header: http.Header{"Content-Security-Policy": {"default-src 'self'; frame-ancestors 'self'"}}, ok: false},
+		{name: "AncestorsNone", header: http.Header{"Content-Security-Policy": {"frame-ancestors 'none'"}}, ok: false},
+		{name: "AncestorsAny", header: http.Header{"Content-Security-Policy": {"frame-ancestors *"}}, ok: true},
+		{name: "AncestorsHTTPS", header: http.Header{"Content-Security-Policy": {"Frame-Ancestors https:"}}, ok: true},
+		{name: "OtherDirectives", header: http.Header{"Content-Security-Policy": {"default-src 'self'; img-src *"}}, ok: true},
+		{
+			name:   "OneOfManyPolicies",
+			header: http.Header{"Content-Security-Policy": {"frame-ancestors *", "frame-ancestors https://example.com"}},
+			ok:     false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.ok, Frameable(tc.header))
+		})
+	}
+}
+
+func TestChecker_Check(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/denied":
+			w.Header().Set("X-Frame-Options", "DENY")
+		case "/moved":
+			http.Redirect(w, r, "/denied", http.StatusFound)
+			return
+		case "/missing":
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("<html></html>"))
+	}))
+	defer server.Close()
+
+	checker := NewChecker(Options{AllowPrivateNetworks: true, AllowInsecure: true})
+
+	require.NoError(t, checker.Check(context.Background(), server.URL+"/open"))
+	require.ErrorIs(t, checker.Check(context.Background(), server.URL+"/denied"), ErrFramingRefused)
+	require.ErrorIs(t, checker.Check(context.Background(), server.URL+"/moved"), ErrFramingRefused)
+	require.ErrorIs(t, checker.Check(context.Background(), server.URL+"/missing"), ErrUnexpectedStatus)
+
+	strict := NewChecker(Options{AllowPrivateNetworks: true})
+	require.ErrorIs(t, strict.Check(context.Background(), server.URL+"/open"), ErrInsecureDestination)
+	require.ErrorIs(t, strict.Check(context.Background(), "javascript:alert(1)"), ErrInsecureDestination)
+}
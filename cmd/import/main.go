@@ -0,0 +1,101 @@
+// Command import loads a Bitly or YOURLS export for a user without going through the API.
+//
+//	go run ./cmd/import -user alice -source bitly -file links.csv
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/importer"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func main() {
+	user := flag.String("user", "", "username or email of the owner of the imported links")
+	source := flag.String("source", "", "shortener the export comes from (bitly or yourls)")
+	format := flag.String("format", "", "csv or json, defaults to the file extension")
+	file := flag.String("file", "", "path to the export")
+	config := flag.String("config", ".", "directory holding app.env")
+	flag.Parse()
+
+	if *user == "" || *source == "" || *file == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if *format == "" {
+		*format = strings.TrimPrefix(strings.ToLower(filepath.Ext(*file)), ".")
+	}
+
+	payload, err := os.ReadFile(*file)
+	if err != nil {
+		log.Fatalf("cannot read export: %v", err)
+	}
+
+	if _, err := importer.Parse(*source, *format, payload); err != nil {
+		log.Fatalf("cannot parse export: %v", err)
+	}
+
+	cfg, err := util.LoadConfig(*config)
+	if err != nil {
+		log.Fatalf("cannot load config: %v", err)
+	}
+
+	ctx := context.Background()
+
+	conn, err := pgxpool.New(ctx, cfg.DBSource)
+	if err != nil {
+		log.Fatalf("cannot connect to database: %v", err)
+	}
+	defer conn.Close()
+
+	store := db.NewStore(conn)
+
+	owner, err := store.GetUser(ctx, *user)
+	if err != nil {
+		log.Fatalf("cannot find user %q: %v", *user, err)
+	}
+
+	job, err := store.CreateImportJob(ctx, db.CreateImportJobParams{
+		UserID:  owner.ID,
+		Source:  *source,
+		Format:  *format,
+		Payload: payload,
+	})
+	if err != nil {
+		log.Fatalf("cannot create import job: %v", err)
+	}
+
+	worker := importer.NewWorker(store, 0)
+
+	// The server's workers look for new jobs too; whoever claims the job first imports it
+	claimed, err := worker.ClaimJob(ctx, job.ID)
+	if err != nil {
+		if errors.Is(err, db.ErrRecordNotFound) {
+			fmt.Printf("job %d was picked up by the server, which will import it\n", job.ID)
+			return
+		}
+		log.Fatalf("cannot claim import job: %v", err)
+	}
+
+	job, err = worker.RunJob(ctx, claimed, func(job db.ImportJob) {
+		fmt.Printf("\rimported %d of %d (%d conflicts)", job.Processed, job.Total, job.Conflicts)
+	})
+	fmt.Println()
+	if err != nil {
+		log.Fatalf("import job %d stopped, the server will resume it: %v", job.ID, err)
+	}
+
+	fmt.Printf("job %d %s: %d imported, %d conflicts\n", job.ID, job.Status, job.Imported, job.Conflicts)
+	if job.Error != "" {
+		fmt.Println(job.Error)
+	}
+}
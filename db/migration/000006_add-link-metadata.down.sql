@@ -0,0 +1,7 @@
+alter table if exists links
+    drop column title,
+    drop column notes,
+    drop column meta_title,
+    drop column meta_description,
+    drop column meta_favicon,
+    drop column meta_fetched_at;
@@ -0,0 +1,9 @@
+alter table if exists links
+    add column title               varchar not null default '',
+    add column notes               varchar not null default '',
+    add column meta_title          varchar not null default '',
+    add column meta_description    varchar not null default '',
+    add column meta_favicon        varchar not null default '',
+    add column meta_fetched_at     timestamptz;
+
+CREATE INDEX ON "links" ("meta_fetched_at") WHERE "meta_fetched_at" IS NULL;
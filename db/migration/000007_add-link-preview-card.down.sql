@@ -0,0 +1,4 @@
+alter table if exists links
+    drop column og_title,
+    drop column og_description,
+    drop column og_image;
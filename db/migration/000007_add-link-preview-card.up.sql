@@ -0,0 +1,4 @@
+alter table if exists links
+    add column og_title       varchar not null default '',
+    add column og_description varchar not null default '',
+    add column og_image       varchar not null default '';
@@ -0,0 +1,8 @@
+alter table if exists links
+    drop column folder_id;
+
+DROP TABLE IF EXISTS link_tags;
+
+DROP TABLE IF EXISTS tags;
+
+DROP TABLE IF EXISTS folders;
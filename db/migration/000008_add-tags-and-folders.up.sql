@@ -0,0 +1,32 @@
+CREATE TABLE "folders"
+(
+    "id"         bigserial PRIMARY KEY,
+    "user_id"    bigint      NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "name"       varchar     NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    UNIQUE ("user_id", "name")
+);
+
+CREATE TABLE "tags"
+(
+    "id"         bigserial PRIMARY KEY,
+    "user_id"    bigint      NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "name"       varchar     NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    UNIQUE ("user_id", "name")
+);
+
+CREATE TABLE "link_tags"
+(
+    "link_id"    bigint      NOT NULL REFERENCES "links" ("id") ON DELETE CASCADE,
+    "tag_id"     bigint      NOT NULL REFERENCES "tags" ("id") ON DELETE CASCADE,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    PRIMARY KEY ("link_id", "tag_id")
+);
+
+CREATE INDEX ON "link_tags" ("tag_id");
+
+alter table if exists links
+    add column folder_id bigint REFERENCES "folders" ("id") ON DELETE SET NULL;
+
+CREATE INDEX ON "links" ("user_id", "folder_id");
@@ -0,0 +1,16 @@
+DROP INDEX IF EXISTS links_user_id_code_id_idx;
+
+DROP INDEX IF EXISTS links_user_id_click_count_id_idx;
+
+DROP INDEX IF EXISTS links_user_id_created_at_id_idx;
+
+DROP INDEX IF EXISTS links_user_id_link_host_idx;
+
+DROP INDEX IF EXISTS links_expr_idx;
+
+DROP FUNCTION IF EXISTS link_host(varchar);
+
+alter table if exists links
+    drop column click_count;
+
+DROP TABLE IF EXISTS clicks;
@@ -0,0 +1,35 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE TABLE "clicks"
+(
+    "id"         bigserial PRIMARY KEY,
+    "link_id"    bigint      NOT NULL REFERENCES "links" ("id") ON DELETE CASCADE,
+    "referrer"   varchar     NOT NULL DEFAULT '',
+    "user_agent" varchar     NOT NULL DEFAULT '',
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "clicks" ("link_id", "created_at");
+
+alter table if exists links
+    add column click_count bigint not null default 0;
+
+-- link_host extracts the lower-cased host of a destination so it can be filtered and indexed
+CREATE FUNCTION link_host(url varchar) RETURNS varchar
+    LANGUAGE sql
+    IMMUTABLE
+    RETURNS NULL ON NULL INPUT
+AS
+$$
+SELECT lower(substring(url from '^[A-Za-z][A-Za-z0-9+.-]*://(?:[^/?#@]*@)?([^/?#:]+)'))
+$$;
+
+CREATE INDEX ON "links" USING gin ((code || ' ' || link || ' ' || title || ' ' || notes) gin_trgm_ops);
+
+CREATE INDEX ON "links" ("user_id", link_host(link));
+
+CREATE INDEX ON "links" ("user_id", "created_at", "id");
+
+CREATE INDEX ON "links" ("user_id", "click_count", "id");
+
+CREATE INDEX ON "links" ("user_id", "code", "id");
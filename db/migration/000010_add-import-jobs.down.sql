@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS import_conflicts;
+
+DROP TABLE IF EXISTS import_jobs;
@@ -0,0 +1,35 @@
+CREATE TABLE "import_jobs"
+(
+    "id"         bigserial PRIMARY KEY,
+    "user_id"    bigint      NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "source"     varchar     NOT NULL,
+    "format"     varchar     NOT NULL,
+    "payload"    bytea       NOT NULL,
+    "status"     varchar     NOT NULL DEFAULT 'pending',
+    "total"      bigint      NOT NULL DEFAULT 0,
+    "processed"  bigint      NOT NULL DEFAULT 0,
+    "imported"   bigint      NOT NULL DEFAULT 0,
+    "conflicts"  bigint      NOT NULL DEFAULT 0,
+    "error"      varchar     NOT NULL DEFAULT '',
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    "updated_at" timestamptz NOT NULL DEFAULT (now()),
+    -- A worker holds a job until its lease runs out, so a crashed worker's job is picked up again
+    "locked_until" timestamptz
+);
+
+CREATE INDEX ON "import_jobs" ("user_id");
+
+CREATE INDEX ON "import_jobs" ("status") WHERE "status" IN ('pending', 'running');
+
+CREATE TABLE "import_conflicts"
+(
+    "id"            bigserial PRIMARY KEY,
+    "job_id"        bigint      NOT NULL REFERENCES "import_jobs" ("id") ON DELETE CASCADE,
+    "line"          bigint      NOT NULL,
+    "original_code" varchar     NOT NULL,
+    "link_id"       bigint REFERENCES "links" ("id") ON DELETE SET NULL,
+    "reason"        varchar     NOT NULL,
+    "created_at"    timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "import_conflicts" ("job_id", "line");
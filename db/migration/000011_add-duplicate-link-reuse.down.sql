@@ -0,0 +1,6 @@
+alter table if exists users
+    drop column reuse_duplicate_links;
+
+DROP INDEX IF EXISTS links_user_id_normalize_link_idx;
+
+DROP FUNCTION IF EXISTS normalize_link(varchar);
@@ -0,0 +1,19 @@
+-- normalize_link reduces a destination to the form used to spot duplicates: the scheme and host are lower-cased,
+-- default ports, fragments and trailing slashes are dropped. Anything that is not an absolute url is kept as is.
+CREATE FUNCTION normalize_link(url varchar) RETURNS varchar
+    LANGUAGE sql
+    IMMUTABLE
+    RETURNS NULL ON NULL INPUT
+AS
+$$
+SELECT coalesce(
+               regexp_replace(regexp_replace(lower(m[1]), '^(http://[^/]*):80$', '\1'), '^(https://[^/]*):443$', '\1') ||
+               regexp_replace(m[2], '/+$', '') || m[3],
+               url)
+FROM regexp_match(url, '^([A-Za-z][A-Za-z0-9+.-]*://[^/?#]*)([^?#]*)([^#]*)') AS m
+$$;
+
+CREATE INDEX ON "links" ("user_id", normalize_link(link));
+
+alter table if exists users
+    add column reuse_duplicate_links bool not null default false;
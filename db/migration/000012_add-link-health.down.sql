@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS links_user_id_id_idx;
+
+DROP INDEX IF EXISTS links_health_checked_at_idx;
+
+alter table if exists links
+    drop column health_checked_at,
+    drop column health_broken,
+    drop column health_error,
+    drop column health_latency_ms,
+    drop column health_status;
@@ -0,0 +1,10 @@
+alter table if exists links
+    add column health_status     integer     not null default 0,
+    add column health_latency_ms integer     not null default 0,
+    add column health_error      varchar     not null default '',
+    add column health_broken     bool        not null default false,
+    add column health_checked_at timestamptz;
+
+CREATE INDEX ON "links" ("health_checked_at" NULLS FIRST) WHERE "active";
+
+CREATE INDEX ON "links" ("user_id", "id") WHERE "health_broken";
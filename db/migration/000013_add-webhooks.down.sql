@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS webhook_deliveries;
+
+DROP TABLE IF EXISTS webhooks;
+
+DROP INDEX IF EXISTS links_expires_at_idx;
+
+alter table if exists links
+    drop column expiry_notified,
+    drop column max_clicks,
+    drop column expires_at;
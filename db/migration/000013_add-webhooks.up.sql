@@ -0,0 +1,38 @@
+alter table if exists links
+    add column expires_at      timestamptz,
+    add column max_clicks      bigint,
+    add column expiry_notified bool not null default false;
+
+CREATE INDEX ON "links" ("expires_at") WHERE NOT "expiry_notified";
+
+CREATE TABLE "webhooks"
+(
+    "id"         bigserial PRIMARY KEY,
+    "user_id"    bigint      NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "url"        varchar     NOT NULL,
+    "secret"     varchar     NOT NULL,
+    "events"     varchar[]   NOT NULL,
+    "active"     bool        NOT NULL DEFAULT true,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "webhooks" ("user_id");
+
+CREATE TABLE "webhook_deliveries"
+(
+    "id"              bigserial PRIMARY KEY,
+    "webhook_id"      bigint      NOT NULL REFERENCES "webhooks" ("id") ON DELETE CASCADE,
+    "event"           varchar     NOT NULL,
+    "payload"         jsonb       NOT NULL,
+    "status"          varchar     NOT NULL DEFAULT 'pending',
+    "attempts"        integer     NOT NULL DEFAULT 0,
+    "next_attempt_at" timestamptz NOT NULL DEFAULT (now()),
+    "response_status" integer     NOT NULL DEFAULT 0,
+    "error"           varchar     NOT NULL DEFAULT '',
+    "delivered_at"    timestamptz,
+    "created_at"      timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "webhook_deliveries" ("next_attempt_at") WHERE "status" = 'pending';
+
+CREATE INDEX ON "webhook_deliveries" ("webhook_id", "id");
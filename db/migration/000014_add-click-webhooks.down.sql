@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS webhook_click_outbox;
+
+alter table if exists webhooks
+    drop column batch_max_wait_seconds,
+    drop column batch_size;
@@ -0,0 +1,15 @@
+alter table if exists webhooks
+    add column batch_size             integer not null default 100,
+    add column batch_max_wait_seconds integer not null default 60;
+
+-- Clicks wait here until they are batched into a webhook delivery. Rows are written in the same statement as the click
+-- and only removed by the statement that creates their delivery, so no click is lost between the two.
+CREATE TABLE "webhook_click_outbox"
+(
+    "id"         bigserial PRIMARY KEY,
+    "webhook_id" bigint      NOT NULL REFERENCES "webhooks" ("id") ON DELETE CASCADE,
+    "payload"    jsonb       NOT NULL,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "webhook_click_outbox" ("webhook_id", "id");
@@ -0,0 +1,2 @@
+alter table if exists links
+    add column interstitial bool not null default false;
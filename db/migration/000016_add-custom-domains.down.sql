@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS links_domain_code_key;
+
+DROP INDEX IF EXISTS links_default_code_key;
+
+alter table if exists links
+    drop column domain_id;
+
+alter table if exists links
+    add constraint links_code_key unique (code);
+
+DROP TABLE IF EXISTS domains;
@@ -0,0 +1,27 @@
+CREATE TABLE "domains"
+(
+    "id"                 bigserial PRIMARY KEY,
+    "user_id"            bigint      NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "hostname"           varchar     NOT NULL,
+    "verification_token" varchar     NOT NULL,
+    "verified_at"        timestamptz,
+    "created_at"         timestamptz NOT NULL DEFAULT (now())
+);
+
+-- Anyone may claim a hostname, but only the first claim to prove ownership gets it
+CREATE UNIQUE INDEX domains_verified_hostname_key ON "domains" ("hostname") WHERE "verified_at" IS NOT NULL;
+
+-- Also serves the lookups by user
+CREATE UNIQUE INDEX domains_user_hostname_key ON "domains" ("user_id", "hostname");
+
+-- A domain cannot be removed while links still use it
+alter table if exists links
+    add column domain_id bigint REFERENCES domains (id);
+
+-- Codes are unique per domain; links without one live on the default short domain
+alter table if exists links
+    drop constraint links_code_key;
+
+CREATE UNIQUE INDEX links_default_code_key ON "links" ("code") WHERE "domain_id" IS NULL;
+
+CREATE UNIQUE INDEX links_domain_code_key ON "links" ("domain_id", "code") WHERE "domain_id" IS NOT NULL;
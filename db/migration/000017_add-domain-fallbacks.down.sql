@@ -0,0 +1,4 @@
+alter table if exists domains
+    drop column not_found_html,
+    drop column not_found_url,
+    drop column root_redirect_url;
@@ -0,0 +1,5 @@
+-- Where visitors go when they hit the domain's root or a code that does not exist; empty means no fallback
+alter table if exists domains
+    add column root_redirect_url varchar not null default '',
+    add column not_found_url     varchar not null default '',
+    add column not_found_html    text    not null default '';
@@ -0,0 +1,6 @@
+alter table if exists clicks
+    drop column bio_page_id;
+
+DROP TABLE IF EXISTS bio_page_links;
+
+DROP TABLE IF EXISTS bio_pages;
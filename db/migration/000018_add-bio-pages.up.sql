@@ -0,0 +1,30 @@
+CREATE TABLE "bio_pages"
+(
+    "id"           bigserial PRIMARY KEY,
+    "user_id"      bigint      NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "slug"         varchar     NOT NULL UNIQUE,
+    "title"        varchar     NOT NULL DEFAULT '',
+    "description"  varchar     NOT NULL DEFAULT '',
+    "avatar_url"   varchar     NOT NULL DEFAULT '',
+    "theme"        varchar     NOT NULL DEFAULT 'light',
+    "accent_color" varchar     NOT NULL DEFAULT '#1a56db',
+    "created_at"   timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "bio_pages" ("user_id");
+
+CREATE TABLE "bio_page_links"
+(
+    "bio_page_id" bigint  NOT NULL REFERENCES "bio_pages" ("id") ON DELETE CASCADE,
+    "link_id"     bigint  NOT NULL REFERENCES "links" ("id") ON DELETE CASCADE,
+    "position"    integer NOT NULL,
+    "label"       varchar NOT NULL DEFAULT '',
+    "icon_url"    varchar NOT NULL DEFAULT '',
+    PRIMARY KEY ("bio_page_id", "link_id")
+);
+
+-- Clicks made through a bio page count for the link and the page
+alter table if exists clicks
+    add column bio_page_id bigint REFERENCES "bio_pages" ("id") ON DELETE SET NULL;
+
+CREATE INDEX ON "clicks" ("bio_page_id", "created_at");
@@ -0,0 +1,4 @@
+alter table if exists links
+    drop column utm_campaign;
+
+DROP TABLE IF EXISTS campaign_templates;
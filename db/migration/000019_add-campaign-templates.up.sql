@@ -0,0 +1,20 @@
+CREATE TABLE "campaign_templates"
+(
+    "id"           bigserial PRIMARY KEY,
+    "user_id"      bigint      NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "name"         varchar     NOT NULL,
+    "utm_source"   varchar     NOT NULL DEFAULT '',
+    "utm_medium"   varchar     NOT NULL DEFAULT '',
+    "utm_campaign" varchar     NOT NULL DEFAULT '',
+    "utm_term"     varchar     NOT NULL DEFAULT '',
+    "utm_content"  varchar     NOT NULL DEFAULT '',
+    "params"       jsonb       NOT NULL DEFAULT '{}',
+    "created_at"   timestamptz NOT NULL DEFAULT (now()),
+    UNIQUE ("user_id", "name")
+);
+
+-- The campaign a destination's utm_campaign names, kept so links can be filtered by it
+alter table if exists links
+    add column utm_campaign varchar not null default '';
+
+CREATE INDEX ON "links" ("user_id", "utm_campaign");
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS campaign_links;
+
+DROP TABLE IF EXISTS campaigns;
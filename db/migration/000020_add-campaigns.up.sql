@@ -0,0 +1,21 @@
+CREATE TABLE "campaigns"
+(
+    "id"         bigserial PRIMARY KEY,
+    "user_id"    bigint      NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "name"       varchar     NOT NULL,
+    "starts_at"  timestamptz,
+    "ends_at"    timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now()),
+    UNIQUE ("user_id", "name"),
+    CHECK ("starts_at" IS NULL OR "ends_at" IS NULL OR "starts_at" < "ends_at")
+);
+
+CREATE TABLE "campaign_links"
+(
+    "campaign_id" bigint      NOT NULL REFERENCES "campaigns" ("id") ON DELETE CASCADE,
+    "link_id"     bigint      NOT NULL REFERENCES "links" ("id") ON DELETE CASCADE,
+    "created_at"  timestamptz NOT NULL DEFAULT (now()),
+    PRIMARY KEY ("campaign_id", "link_id")
+);
+
+CREATE INDEX ON "campaign_links" ("link_id");
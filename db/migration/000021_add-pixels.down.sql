@@ -0,0 +1,6 @@
+alter table if exists links
+    drop column has_pixels;
+
+DROP TABLE IF EXISTS link_pixels;
+
+DROP TABLE IF EXISTS pixels;
@@ -0,0 +1,25 @@
+CREATE TABLE "pixels"
+(
+    "id"         bigserial PRIMARY KEY,
+    "user_id"    bigint      NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "name"       varchar     NOT NULL,
+    "provider"   varchar     NOT NULL,
+    "account_id" varchar     NOT NULL DEFAULT '',
+    "src"        varchar     NOT NULL DEFAULT '',
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "pixels" ("user_id");
+
+CREATE TABLE "link_pixels"
+(
+    "link_id"  bigint NOT NULL REFERENCES "links" ("id") ON DELETE CASCADE,
+    "pixel_id" bigint NOT NULL REFERENCES "pixels" ("id") ON DELETE CASCADE,
+    PRIMARY KEY ("link_id", "pixel_id")
+);
+
+CREATE INDEX ON "link_pixels" ("pixel_id");
+
+-- Kept on the link so redirects without pixels never look them up
+alter table if exists links
+    add column has_pixels bool not null default false;
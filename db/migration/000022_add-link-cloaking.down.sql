@@ -0,0 +1,2 @@
+alter table if exists links
+    drop column cloaked;
@@ -0,0 +1,2 @@
+alter table if exists links
+    add column cloaked bool not null default false;
@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS user_tokens;
+
+alter table if exists users
+    drop column email_verified_at;
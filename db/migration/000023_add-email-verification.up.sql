@@ -0,0 +1,20 @@
+alter table if exists users
+    add column email_verified_at timestamptz;
+
+-- Accounts from before verification existed are trusted as they are, so the required policy does not lock them out
+update users
+set email_verified_at = created_at;
+
+-- Only a hash of each token is stored; the token itself only ever exists in the email
+CREATE TABLE "user_tokens"
+(
+    "id"         bigserial PRIMARY KEY,
+    "user_id"    bigint      NOT NULL REFERENCES "users" ("id") ON DELETE CASCADE,
+    "purpose"    varchar     NOT NULL,
+    "token_hash" varchar     NOT NULL UNIQUE,
+    "expires_at" timestamptz NOT NULL,
+    "used_at"    timestamptz,
+    "created_at" timestamptz NOT NULL DEFAULT (now())
+);
+
+CREATE INDEX ON "user_tokens" ("user_id", "purpose");
@@ -41,199 +41,1745 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// AddBioPageLinks mocks base method.
+func (m *MockStore) AddBioPageLinks(arg0 context.Context, arg1 db.AddBioPageLinksParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddBioPageLinks", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddBioPageLinks indicates an expected call of AddBioPageLinks.
+func (mr *MockStoreMockRecorder) AddBioPageLinks(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBioPageLinks", reflect.TypeOf((*MockStore)(nil).AddBioPageLinks), arg0, arg1)
+}
+
+// AddCampaignLinks mocks base method.
+func (m *MockStore) AddCampaignLinks(arg0 context.Context, arg1 db.AddCampaignLinksParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCampaignLinks", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddCampaignLinks indicates an expected call of AddCampaignLinks.
+func (mr *MockStoreMockRecorder) AddCampaignLinks(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCampaignLinks", reflect.TypeOf((*MockStore)(nil).AddCampaignLinks), arg0, arg1)
+}
+
+// AddLinkPixels mocks base method.
+func (m *MockStore) AddLinkPixels(arg0 context.Context, arg1 db.AddLinkPixelsParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddLinkPixels", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddLinkPixels indicates an expected call of AddLinkPixels.
+func (mr *MockStoreMockRecorder) AddLinkPixels(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddLinkPixels", reflect.TypeOf((*MockStore)(nil).AddLinkPixels), arg0, arg1)
+}
+
+// AttachTag mocks base method.
+func (m *MockStore) AttachTag(arg0 context.Context, arg1 db.AttachTagParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AttachTag", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AttachTag indicates an expected call of AttachTag.
+func (mr *MockStoreMockRecorder) AttachTag(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AttachTag", reflect.TypeOf((*MockStore)(nil).AttachTag), arg0, arg1)
+}
+
 // BlockSession mocks base method.
 func (m *MockStore) BlockSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "BlockSession", arg0, arg1)
-	ret0, _ := ret[0].(db.Session)
+	ret := m.ctrl.Call(m, "BlockSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BlockSession indicates an expected call of BlockSession.
+func (mr *MockStoreMockRecorder) BlockSession(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockSession", reflect.TypeOf((*MockStore)(nil).BlockSession), arg0, arg1)
+}
+
+// ChangePasswordTx mocks base method.
+func (m *MockStore) ChangePasswordTx(arg0 context.Context, arg1 db.ChangePasswordTxParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePasswordTx", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangePasswordTx indicates an expected call of ChangePasswordTx.
+func (mr *MockStoreMockRecorder) ChangePasswordTx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePasswordTx", reflect.TypeOf((*MockStore)(nil).ChangePasswordTx), arg0, arg1)
+}
+
+// ClaimImportJob mocks base method.
+func (m *MockStore) ClaimImportJob(arg0 context.Context, arg1 db.ClaimImportJobParams) (db.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimImportJob", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimImportJob indicates an expected call of ClaimImportJob.
+func (mr *MockStoreMockRecorder) ClaimImportJob(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimImportJob", reflect.TypeOf((*MockStore)(nil).ClaimImportJob), arg0, arg1)
+}
+
+// ClaimWebhookDeliveries mocks base method.
+func (m *MockStore) ClaimWebhookDeliveries(arg0 context.Context, arg1 db.ClaimWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimWebhookDeliveries indicates an expected call of ClaimWebhookDeliveries.
+func (mr *MockStoreMockRecorder) ClaimWebhookDeliveries(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).ClaimWebhookDeliveries), arg0, arg1)
+}
+
+// CountBrokenLinksByUser mocks base method.
+func (m *MockStore) CountBrokenLinksByUser(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountBrokenLinksByUser", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountBrokenLinksByUser indicates an expected call of CountBrokenLinksByUser.
+func (mr *MockStoreMockRecorder) CountBrokenLinksByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountBrokenLinksByUser", reflect.TypeOf((*MockStore)(nil).CountBrokenLinksByUser), arg0, arg1)
+}
+
+// CountLinksByUser mocks base method.
+func (m *MockStore) CountLinksByUser(arg0 context.Context, arg1 db.CountLinksByUserParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountLinksByUser", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountLinksByUser indicates an expected call of CountLinksByUser.
+func (mr *MockStoreMockRecorder) CountLinksByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountLinksByUser", reflect.TypeOf((*MockStore)(nil).CountLinksByUser), arg0, arg1)
+}
+
+// CountRecentUserTokens mocks base method.
+func (m *MockStore) CountRecentUserTokens(arg0 context.Context, arg1 db.CountRecentUserTokensParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountRecentUserTokens", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountRecentUserTokens indicates an expected call of CountRecentUserTokens.
+func (mr *MockStoreMockRecorder) CountRecentUserTokens(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountRecentUserTokens", reflect.TypeOf((*MockStore)(nil).CountRecentUserTokens), arg0, arg1)
+}
+
+// CountSessions mocks base method.
+func (m *MockStore) CountSessions(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSessions", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSessions indicates an expected call of CountSessions.
+func (mr *MockStoreMockRecorder) CountSessions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSessions", reflect.TypeOf((*MockStore)(nil).CountSessions), arg0, arg1)
+}
+
+// CountWebhookDeliveries mocks base method.
+func (m *MockStore) CountWebhookDeliveries(arg0 context.Context, arg1 int64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountWebhookDeliveries indicates an expected call of CountWebhookDeliveries.
+func (mr *MockStoreMockRecorder) CountWebhookDeliveries(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).CountWebhookDeliveries), arg0, arg1)
+}
+
+// CreateBioPage mocks base method.
+func (m *MockStore) CreateBioPage(arg0 context.Context, arg1 db.CreateBioPageParams) (db.BioPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBioPage", arg0, arg1)
+	ret0, _ := ret[0].(db.BioPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBioPage indicates an expected call of CreateBioPage.
+func (mr *MockStoreMockRecorder) CreateBioPage(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBioPage", reflect.TypeOf((*MockStore)(nil).CreateBioPage), arg0, arg1)
+}
+
+// CreateCampaign mocks base method.
+func (m *MockStore) CreateCampaign(arg0 context.Context, arg1 db.CreateCampaignParams) (db.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCampaign", arg0, arg1)
+	ret0, _ := ret[0].(db.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCampaign indicates an expected call of CreateCampaign.
+func (mr *MockStoreMockRecorder) CreateCampaign(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCampaign", reflect.TypeOf((*MockStore)(nil).CreateCampaign), arg0, arg1)
+}
+
+// CreateCampaignTemplate mocks base method.
+func (m *MockStore) CreateCampaignTemplate(arg0 context.Context, arg1 db.CreateCampaignTemplateParams) (db.CampaignTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCampaignTemplate", arg0, arg1)
+	ret0, _ := ret[0].(db.CampaignTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCampaignTemplate indicates an expected call of CreateCampaignTemplate.
+func (mr *MockStoreMockRecorder) CreateCampaignTemplate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCampaignTemplate", reflect.TypeOf((*MockStore)(nil).CreateCampaignTemplate), arg0, arg1)
+}
+
+// CreateClickBatchDelivery mocks base method.
+func (m *MockStore) CreateClickBatchDelivery(arg0 context.Context, arg1 db.CreateClickBatchDeliveryParams) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateClickBatchDelivery", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateClickBatchDelivery indicates an expected call of CreateClickBatchDelivery.
+func (mr *MockStoreMockRecorder) CreateClickBatchDelivery(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateClickBatchDelivery", reflect.TypeOf((*MockStore)(nil).CreateClickBatchDelivery), arg0, arg1)
+}
+
+// CreateDomain mocks base method.
+func (m *MockStore) CreateDomain(arg0 context.Context, arg1 db.CreateDomainParams) (db.Domain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDomain", arg0, arg1)
+	ret0, _ := ret[0].(db.Domain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDomain indicates an expected call of CreateDomain.
+func (mr *MockStoreMockRecorder) CreateDomain(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDomain", reflect.TypeOf((*MockStore)(nil).CreateDomain), arg0, arg1)
+}
+
+// CreateFolder mocks base method.
+func (m *MockStore) CreateFolder(arg0 context.Context, arg1 db.CreateFolderParams) (db.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFolder", arg0, arg1)
+	ret0, _ := ret[0].(db.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFolder indicates an expected call of CreateFolder.
+func (mr *MockStoreMockRecorder) CreateFolder(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFolder", reflect.TypeOf((*MockStore)(nil).CreateFolder), arg0, arg1)
+}
+
+// CreateImportConflict mocks base method.
+func (m *MockStore) CreateImportConflict(arg0 context.Context, arg1 db.CreateImportConflictParams) (db.ImportConflict, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImportConflict", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportConflict)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateImportConflict indicates an expected call of CreateImportConflict.
+func (mr *MockStoreMockRecorder) CreateImportConflict(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImportConflict", reflect.TypeOf((*MockStore)(nil).CreateImportConflict), arg0, arg1)
+}
+
+// CreateImportJob mocks base method.
+func (m *MockStore) CreateImportJob(arg0 context.Context, arg1 db.CreateImportJobParams) (db.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateImportJob", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateImportJob indicates an expected call of CreateImportJob.
+func (mr *MockStoreMockRecorder) CreateImportJob(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateImportJob", reflect.TypeOf((*MockStore)(nil).CreateImportJob), arg0, arg1)
+}
+
+// CreateLink mocks base method.
+func (m *MockStore) CreateLink(arg0 context.Context, arg1 db.CreateLinkParams) (db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLink", arg0, arg1)
+	ret0, _ := ret[0].(db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLink indicates an expected call of CreateLink.
+func (mr *MockStoreMockRecorder) CreateLink(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLink", reflect.TypeOf((*MockStore)(nil).CreateLink), arg0, arg1)
+}
+
+// CreateLinkTx mocks base method.
+func (m *MockStore) CreateLinkTx(arg0 context.Context, arg1 db.CreateLinkTxParams) (db.CreateLinkTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLinkTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateLinkTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLinkTx indicates an expected call of CreateLinkTx.
+func (mr *MockStoreMockRecorder) CreateLinkTx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLinkTx", reflect.TypeOf((*MockStore)(nil).CreateLinkTx), arg0, arg1)
+}
+
+// CreateLinksTx mocks base method.
+func (m *MockStore) CreateLinksTx(arg0 context.Context, arg1 []db.CreateLinkTxParams) ([]db.CreateLinkTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateLinksTx", arg0, arg1)
+	ret0, _ := ret[0].([]db.CreateLinkTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateLinksTx indicates an expected call of CreateLinksTx.
+func (mr *MockStoreMockRecorder) CreateLinksTx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLinksTx", reflect.TypeOf((*MockStore)(nil).CreateLinksTx), arg0, arg1)
+}
+
+// CreatePixel mocks base method.
+func (m *MockStore) CreatePixel(arg0 context.Context, arg1 db.CreatePixelParams) (db.Pixel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePixel", arg0, arg1)
+	ret0, _ := ret[0].(db.Pixel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePixel indicates an expected call of CreatePixel.
+func (mr *MockStoreMockRecorder) CreatePixel(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePixel", reflect.TypeOf((*MockStore)(nil).CreatePixel), arg0, arg1)
+}
+
+// CreateSession mocks base method.
+func (m *MockStore) CreateSession(arg0 context.Context, arg1 db.CreateSessionParams) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockStoreMockRecorder) CreateSession(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), arg0, arg1)
+}
+
+// CreateTag mocks base method.
+func (m *MockStore) CreateTag(arg0 context.Context, arg1 db.CreateTagParams) (db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTag", arg0, arg1)
+	ret0, _ := ret[0].(db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTag indicates an expected call of CreateTag.
+func (mr *MockStoreMockRecorder) CreateTag(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTag", reflect.TypeOf((*MockStore)(nil).CreateTag), arg0, arg1)
+}
+
+// CreateUser mocks base method.
+func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+}
+
+// CreateUserToken mocks base method.
+func (m *MockStore) CreateUserToken(arg0 context.Context, arg1 db.CreateUserTokenParams) (db.UserToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUserToken", arg0, arg1)
+	ret0, _ := ret[0].(db.UserToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateUserToken indicates an expected call of CreateUserToken.
+func (mr *MockStoreMockRecorder) CreateUserToken(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUserToken", reflect.TypeOf((*MockStore)(nil).CreateUserToken), arg0, arg1)
+}
+
+// CreateWebhook mocks base method.
+func (m *MockStore) CreateWebhook(arg0 context.Context, arg1 db.CreateWebhookParams) (db.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhook", arg0, arg1)
+	ret0, _ := ret[0].(db.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhook indicates an expected call of CreateWebhook.
+func (mr *MockStoreMockRecorder) CreateWebhook(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhook", reflect.TypeOf((*MockStore)(nil).CreateWebhook), arg0, arg1)
+}
+
+// CreateWebhookDeliveries mocks base method.
+func (m *MockStore) CreateWebhookDeliveries(arg0 context.Context, arg1 db.CreateWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateWebhookDeliveries indicates an expected call of CreateWebhookDeliveries.
+func (mr *MockStoreMockRecorder) CreateWebhookDeliveries(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).CreateWebhookDeliveries), arg0, arg1)
+}
+
+// DeleteBioPage mocks base method.
+func (m *MockStore) DeleteBioPage(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBioPage", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBioPage indicates an expected call of DeleteBioPage.
+func (mr *MockStoreMockRecorder) DeleteBioPage(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBioPage", reflect.TypeOf((*MockStore)(nil).DeleteBioPage), arg0, arg1)
+}
+
+// DeleteBioPageLinks mocks base method.
+func (m *MockStore) DeleteBioPageLinks(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteBioPageLinks", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteBioPageLinks indicates an expected call of DeleteBioPageLinks.
+func (mr *MockStoreMockRecorder) DeleteBioPageLinks(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteBioPageLinks", reflect.TypeOf((*MockStore)(nil).DeleteBioPageLinks), arg0, arg1)
+}
+
+// DeleteCampaign mocks base method.
+func (m *MockStore) DeleteCampaign(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCampaign", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCampaign indicates an expected call of DeleteCampaign.
+func (mr *MockStoreMockRecorder) DeleteCampaign(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCampaign", reflect.TypeOf((*MockStore)(nil).DeleteCampaign), arg0, arg1)
+}
+
+// DeleteCampaignTemplate mocks base method.
+func (m *MockStore) DeleteCampaignTemplate(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCampaignTemplate", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteCampaignTemplate indicates an expected call of DeleteCampaignTemplate.
+func (mr *MockStoreMockRecorder) DeleteCampaignTemplate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCampaignTemplate", reflect.TypeOf((*MockStore)(nil).DeleteCampaignTemplate), arg0, arg1)
+}
+
+// DeleteDomain mocks base method.
+func (m *MockStore) DeleteDomain(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDomain", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteDomain indicates an expected call of DeleteDomain.
+func (mr *MockStoreMockRecorder) DeleteDomain(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDomain", reflect.TypeOf((*MockStore)(nil).DeleteDomain), arg0, arg1)
+}
+
+// DeleteFolder mocks base method.
+func (m *MockStore) DeleteFolder(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteFolder", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteFolder indicates an expected call of DeleteFolder.
+func (mr *MockStoreMockRecorder) DeleteFolder(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteFolder", reflect.TypeOf((*MockStore)(nil).DeleteFolder), arg0, arg1)
+}
+
+// DeleteLinkPixels mocks base method.
+func (m *MockStore) DeleteLinkPixels(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteLinkPixels", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteLinkPixels indicates an expected call of DeleteLinkPixels.
+func (mr *MockStoreMockRecorder) DeleteLinkPixels(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteLinkPixels", reflect.TypeOf((*MockStore)(nil).DeleteLinkPixels), arg0, arg1)
+}
+
+// DeletePixel mocks base method.
+func (m *MockStore) DeletePixel(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePixel", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePixel indicates an expected call of DeletePixel.
+func (mr *MockStoreMockRecorder) DeletePixel(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePixel", reflect.TypeOf((*MockStore)(nil).DeletePixel), arg0, arg1)
+}
+
+// DeleteTag mocks base method.
+func (m *MockStore) DeleteTag(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTag", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTag indicates an expected call of DeleteTag.
+func (mr *MockStoreMockRecorder) DeleteTag(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTag", reflect.TypeOf((*MockStore)(nil).DeleteTag), arg0, arg1)
+}
+
+// DeleteWebhook mocks base method.
+func (m *MockStore) DeleteWebhook(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebhook", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebhook indicates an expected call of DeleteWebhook.
+func (mr *MockStoreMockRecorder) DeleteWebhook(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebhook", reflect.TypeOf((*MockStore)(nil).DeleteWebhook), arg0, arg1)
+}
+
+// DetachTag mocks base method.
+func (m *MockStore) DetachTag(arg0 context.Context, arg1 db.DetachTagParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DetachTag", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DetachTag indicates an expected call of DetachTag.
+func (mr *MockStoreMockRecorder) DetachTag(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DetachTag", reflect.TypeOf((*MockStore)(nil).DetachTag), arg0, arg1)
+}
+
+// ExportLinksByUser mocks base method.
+func (m *MockStore) ExportLinksByUser(arg0 context.Context, arg1 db.ExportLinksByUserParams) ([]db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportLinksByUser", arg0, arg1)
+	ret0, _ := ret[0].([]db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportLinksByUser indicates an expected call of ExportLinksByUser.
+func (mr *MockStoreMockRecorder) ExportLinksByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportLinksByUser", reflect.TypeOf((*MockStore)(nil).ExportLinksByUser), arg0, arg1)
+}
+
+// FinishImportJob mocks base method.
+func (m *MockStore) FinishImportJob(arg0 context.Context, arg1 db.FinishImportJobParams) (db.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishImportJob", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FinishImportJob indicates an expected call of FinishImportJob.
+func (mr *MockStoreMockRecorder) FinishImportJob(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishImportJob", reflect.TypeOf((*MockStore)(nil).FinishImportJob), arg0, arg1)
+}
+
+// GetActiveLinkByDestination mocks base method.
+func (m *MockStore) GetActiveLinkByDestination(arg0 context.Context, arg1 db.GetActiveLinkByDestinationParams) (db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveLinkByDestination", arg0, arg1)
+	ret0, _ := ret[0].(db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveLinkByDestination indicates an expected call of GetActiveLinkByDestination.
+func (mr *MockStoreMockRecorder) GetActiveLinkByDestination(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveLinkByDestination", reflect.TypeOf((*MockStore)(nil).GetActiveLinkByDestination), arg0, arg1)
+}
+
+// GetActiveSessions mocks base method.
+func (m *MockStore) GetActiveSessions(arg0 context.Context, arg1 db.GetActiveSessionsParams) ([]db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveSessions", arg0, arg1)
+	ret0, _ := ret[0].([]db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveSessions indicates an expected call of GetActiveSessions.
+func (mr *MockStoreMockRecorder) GetActiveSessions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSessions", reflect.TypeOf((*MockStore)(nil).GetActiveSessions), arg0, arg1)
+}
+
+// GetBioPage mocks base method.
+func (m *MockStore) GetBioPage(arg0 context.Context, arg1 int64) (db.BioPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBioPage", arg0, arg1)
+	ret0, _ := ret[0].(db.BioPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBioPage indicates an expected call of GetBioPage.
+func (mr *MockStoreMockRecorder) GetBioPage(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBioPage", reflect.TypeOf((*MockStore)(nil).GetBioPage), arg0, arg1)
+}
+
+// GetBioPageBySlug mocks base method.
+func (m *MockStore) GetBioPageBySlug(arg0 context.Context, arg1 string) (db.BioPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBioPageBySlug", arg0, arg1)
+	ret0, _ := ret[0].(db.BioPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBioPageBySlug indicates an expected call of GetBioPageBySlug.
+func (mr *MockStoreMockRecorder) GetBioPageBySlug(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBioPageBySlug", reflect.TypeOf((*MockStore)(nil).GetBioPageBySlug), arg0, arg1)
+}
+
+// GetBioPageClickStats mocks base method.
+func (m *MockStore) GetBioPageClickStats(arg0 context.Context, arg1 db.GetBioPageClickStatsParams) ([]db.GetBioPageClickStatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBioPageClickStats", arg0, arg1)
+	ret0, _ := ret[0].([]db.GetBioPageClickStatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBioPageClickStats indicates an expected call of GetBioPageClickStats.
+func (mr *MockStoreMockRecorder) GetBioPageClickStats(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBioPageClickStats", reflect.TypeOf((*MockStore)(nil).GetBioPageClickStats), arg0, arg1)
+}
+
+// GetBioPageLink mocks base method.
+func (m *MockStore) GetBioPageLink(arg0 context.Context, arg1 db.GetBioPageLinkParams) (db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBioPageLink", arg0, arg1)
+	ret0, _ := ret[0].(db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBioPageLink indicates an expected call of GetBioPageLink.
+func (mr *MockStoreMockRecorder) GetBioPageLink(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBioPageLink", reflect.TypeOf((*MockStore)(nil).GetBioPageLink), arg0, arg1)
+}
+
+// GetBioPageLinks mocks base method.
+func (m *MockStore) GetBioPageLinks(arg0 context.Context, arg1 int64) ([]db.GetBioPageLinksRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBioPageLinks", arg0, arg1)
+	ret0, _ := ret[0].([]db.GetBioPageLinksRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBioPageLinks indicates an expected call of GetBioPageLinks.
+func (mr *MockStoreMockRecorder) GetBioPageLinks(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBioPageLinks", reflect.TypeOf((*MockStore)(nil).GetBioPageLinks), arg0, arg1)
+}
+
+// GetBioPagesByUser mocks base method.
+func (m *MockStore) GetBioPagesByUser(arg0 context.Context, arg1 int64) ([]db.BioPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBioPagesByUser", arg0, arg1)
+	ret0, _ := ret[0].([]db.BioPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBioPagesByUser indicates an expected call of GetBioPagesByUser.
+func (mr *MockStoreMockRecorder) GetBioPagesByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBioPagesByUser", reflect.TypeOf((*MockStore)(nil).GetBioPagesByUser), arg0, arg1)
+}
+
+// GetBrokenLinksByUser mocks base method.
+func (m *MockStore) GetBrokenLinksByUser(arg0 context.Context, arg1 db.GetBrokenLinksByUserParams) ([]db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBrokenLinksByUser", arg0, arg1)
+	ret0, _ := ret[0].([]db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBrokenLinksByUser indicates an expected call of GetBrokenLinksByUser.
+func (mr *MockStoreMockRecorder) GetBrokenLinksByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBrokenLinksByUser", reflect.TypeOf((*MockStore)(nil).GetBrokenLinksByUser), arg0, arg1)
+}
+
+// GetCampaign mocks base method.
+func (m *MockStore) GetCampaign(arg0 context.Context, arg1 int64) (db.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaign", arg0, arg1)
+	ret0, _ := ret[0].(db.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaign indicates an expected call of GetCampaign.
+func (mr *MockStoreMockRecorder) GetCampaign(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaign", reflect.TypeOf((*MockStore)(nil).GetCampaign), arg0, arg1)
+}
+
+// GetCampaignChannelStats mocks base method.
+func (m *MockStore) GetCampaignChannelStats(arg0 context.Context, arg1 db.GetCampaignChannelStatsParams) ([]db.GetCampaignChannelStatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaignChannelStats", arg0, arg1)
+	ret0, _ := ret[0].([]db.GetCampaignChannelStatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaignChannelStats indicates an expected call of GetCampaignChannelStats.
+func (mr *MockStoreMockRecorder) GetCampaignChannelStats(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaignChannelStats", reflect.TypeOf((*MockStore)(nil).GetCampaignChannelStats), arg0, arg1)
+}
+
+// GetCampaignLinkStats mocks base method.
+func (m *MockStore) GetCampaignLinkStats(arg0 context.Context, arg1 db.GetCampaignLinkStatsParams) ([]db.GetCampaignLinkStatsRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaignLinkStats", arg0, arg1)
+	ret0, _ := ret[0].([]db.GetCampaignLinkStatsRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaignLinkStats indicates an expected call of GetCampaignLinkStats.
+func (mr *MockStoreMockRecorder) GetCampaignLinkStats(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaignLinkStats", reflect.TypeOf((*MockStore)(nil).GetCampaignLinkStats), arg0, arg1)
+}
+
+// GetCampaignTemplate mocks base method.
+func (m *MockStore) GetCampaignTemplate(arg0 context.Context, arg1 int64) (db.CampaignTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaignTemplate", arg0, arg1)
+	ret0, _ := ret[0].(db.CampaignTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaignTemplate indicates an expected call of GetCampaignTemplate.
+func (mr *MockStoreMockRecorder) GetCampaignTemplate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaignTemplate", reflect.TypeOf((*MockStore)(nil).GetCampaignTemplate), arg0, arg1)
+}
+
+// GetCampaignTemplatesByUser mocks base method.
+func (m *MockStore) GetCampaignTemplatesByUser(arg0 context.Context, arg1 int64) ([]db.CampaignTemplate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaignTemplatesByUser", arg0, arg1)
+	ret0, _ := ret[0].([]db.CampaignTemplate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaignTemplatesByUser indicates an expected call of GetCampaignTemplatesByUser.
+func (mr *MockStoreMockRecorder) GetCampaignTemplatesByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaignTemplatesByUser", reflect.TypeOf((*MockStore)(nil).GetCampaignTemplatesByUser), arg0, arg1)
+}
+
+// GetCampaignsByUser mocks base method.
+func (m *MockStore) GetCampaignsByUser(arg0 context.Context, arg1 int64) ([]db.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaignsByUser", arg0, arg1)
+	ret0, _ := ret[0].([]db.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaignsByUser indicates an expected call of GetCampaignsByUser.
+func (mr *MockStoreMockRecorder) GetCampaignsByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaignsByUser", reflect.TypeOf((*MockStore)(nil).GetCampaignsByUser), arg0, arg1)
+}
+
+// GetClickStatsByLinks mocks base method.
+func (m *MockStore) GetClickStatsByLinks(arg0 context.Context, arg1 db.GetClickStatsByLinksParams) ([]db.GetClickStatsByLinksRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetClickStatsByLinks", arg0, arg1)
+	ret0, _ := ret[0].([]db.GetClickStatsByLinksRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetClickStatsByLinks indicates an expected call of GetClickStatsByLinks.
+func (mr *MockStoreMockRecorder) GetClickStatsByLinks(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetClickStatsByLinks", reflect.TypeOf((*MockStore)(nil).GetClickStatsByLinks), arg0, arg1)
+}
+
+// GetDomain mocks base method.
+func (m *MockStore) GetDomain(arg0 context.Context, arg1 int64) (db.Domain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDomain", arg0, arg1)
+	ret0, _ := ret[0].(db.Domain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDomain indicates an expected call of GetDomain.
+func (mr *MockStoreMockRecorder) GetDomain(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDomain", reflect.TypeOf((*MockStore)(nil).GetDomain), arg0, arg1)
+}
+
+// GetDomainsByUser mocks base method.
+func (m *MockStore) GetDomainsByUser(arg0 context.Context, arg1 int64) ([]db.Domain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDomainsByUser", arg0, arg1)
+	ret0, _ := ret[0].([]db.Domain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDomainsByUser indicates an expected call of GetDomainsByUser.
+func (mr *MockStoreMockRecorder) GetDomainsByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDomainsByUser", reflect.TypeOf((*MockStore)(nil).GetDomainsByUser), arg0, arg1)
+}
+
+// GetExpiredLinksToNotify mocks base method.
+func (m *MockStore) GetExpiredLinksToNotify(arg0 context.Context, arg1 int32) ([]db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetExpiredLinksToNotify", arg0, arg1)
+	ret0, _ := ret[0].([]db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetExpiredLinksToNotify indicates an expected call of GetExpiredLinksToNotify.
+func (mr *MockStoreMockRecorder) GetExpiredLinksToNotify(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetExpiredLinksToNotify", reflect.TypeOf((*MockStore)(nil).GetExpiredLinksToNotify), arg0, arg1)
+}
+
+// GetFolder mocks base method.
+func (m *MockStore) GetFolder(arg0 context.Context, arg1 int64) (db.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFolder", arg0, arg1)
+	ret0, _ := ret[0].(db.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFolder indicates an expected call of GetFolder.
+func (mr *MockStoreMockRecorder) GetFolder(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFolder", reflect.TypeOf((*MockStore)(nil).GetFolder), arg0, arg1)
+}
+
+// GetFoldersByUser mocks base method.
+func (m *MockStore) GetFoldersByUser(arg0 context.Context, arg1 int64) ([]db.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFoldersByUser", arg0, arg1)
+	ret0, _ := ret[0].([]db.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFoldersByUser indicates an expected call of GetFoldersByUser.
+func (mr *MockStoreMockRecorder) GetFoldersByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFoldersByUser", reflect.TypeOf((*MockStore)(nil).GetFoldersByUser), arg0, arg1)
+}
+
+// GetImportConflicts mocks base method.
+func (m *MockStore) GetImportConflicts(arg0 context.Context, arg1 db.GetImportConflictsParams) ([]db.ImportConflict, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImportConflicts", arg0, arg1)
+	ret0, _ := ret[0].([]db.ImportConflict)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImportConflicts indicates an expected call of GetImportConflicts.
+func (mr *MockStoreMockRecorder) GetImportConflicts(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportConflicts", reflect.TypeOf((*MockStore)(nil).GetImportConflicts), arg0, arg1)
+}
+
+// GetImportJob mocks base method.
+func (m *MockStore) GetImportJob(arg0 context.Context, arg1 int64) (db.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImportJob", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImportJob indicates an expected call of GetImportJob.
+func (mr *MockStoreMockRecorder) GetImportJob(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportJob", reflect.TypeOf((*MockStore)(nil).GetImportJob), arg0, arg1)
+}
+
+// GetLinkByCode mocks base method.
+func (m *MockStore) GetLinkByCode(arg0 context.Context, arg1 string) (db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLinkByCode", arg0, arg1)
+	ret0, _ := ret[0].(db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLinkByCode indicates an expected call of GetLinkByCode.
+func (mr *MockStoreMockRecorder) GetLinkByCode(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinkByCode", reflect.TypeOf((*MockStore)(nil).GetLinkByCode), arg0, arg1)
+}
+
+// GetLinkByDomainCode mocks base method.
+func (m *MockStore) GetLinkByDomainCode(arg0 context.Context, arg1 db.GetLinkByDomainCodeParams) (db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLinkByDomainCode", arg0, arg1)
+	ret0, _ := ret[0].(db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLinkByDomainCode indicates an expected call of GetLinkByDomainCode.
+func (mr *MockStoreMockRecorder) GetLinkByDomainCode(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinkByDomainCode", reflect.TypeOf((*MockStore)(nil).GetLinkByDomainCode), arg0, arg1)
+}
+
+// GetLinkById mocks base method.
+func (m *MockStore) GetLinkById(arg0 context.Context, arg1 int64) (db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLinkById", arg0, arg1)
+	ret0, _ := ret[0].(db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLinkById indicates an expected call of GetLinkById.
+func (mr *MockStoreMockRecorder) GetLinkById(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinkById", reflect.TypeOf((*MockStore)(nil).GetLinkById), arg0, arg1)
+}
+
+// GetLinks mocks base method.
+func (m *MockStore) GetLinks(arg0 context.Context, arg1 db.GetLinksParams) ([]db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLinks", arg0, arg1)
+	ret0, _ := ret[0].([]db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLinks indicates an expected call of GetLinks.
+func (mr *MockStoreMockRecorder) GetLinks(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinks", reflect.TypeOf((*MockStore)(nil).GetLinks), arg0, arg1)
+}
+
+// GetLinksByUser mocks base method.
+func (m *MockStore) GetLinksByUser(arg0 context.Context, arg1 db.GetLinksByUserParams) ([]db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLinksByUser", arg0, arg1)
+	ret0, _ := ret[0].([]db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLinksByUser indicates an expected call of GetLinksByUser.
+func (mr *MockStoreMockRecorder) GetLinksByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinksByUser", reflect.TypeOf((*MockStore)(nil).GetLinksByUser), arg0, arg1)
+}
+
+// GetLinksByUserClicksAsc mocks base method.
+func (m *MockStore) GetLinksByUserClicksAsc(arg0 context.Context, arg1 db.GetLinksByUserClicksAscParams) ([]db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLinksByUserClicksAsc", arg0, arg1)
+	ret0, _ := ret[0].([]db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLinksByUserClicksAsc indicates an expected call of GetLinksByUserClicksAsc.
+func (mr *MockStoreMockRecorder) GetLinksByUserClicksAsc(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinksByUserClicksAsc", reflect.TypeOf((*MockStore)(nil).GetLinksByUserClicksAsc), arg0, arg1)
+}
+
+// GetLinksByUserClicksDesc mocks base method.
+func (m *MockStore) GetLinksByUserClicksDesc(arg0 context.Context, arg1 db.GetLinksByUserClicksDescParams) ([]db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLinksByUserClicksDesc", arg0, arg1)
+	ret0, _ := ret[0].([]db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLinksByUserClicksDesc indicates an expected call of GetLinksByUserClicksDesc.
+func (mr *MockStoreMockRecorder) GetLinksByUserClicksDesc(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinksByUserClicksDesc", reflect.TypeOf((*MockStore)(nil).GetLinksByUserClicksDesc), arg0, arg1)
+}
+
+// GetLinksByUserCodeAsc mocks base method.
+func (m *MockStore) GetLinksByUserCodeAsc(arg0 context.Context, arg1 db.GetLinksByUserCodeAscParams) ([]db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLinksByUserCodeAsc", arg0, arg1)
+	ret0, _ := ret[0].([]db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLinksByUserCodeAsc indicates an expected call of GetLinksByUserCodeAsc.
+func (mr *MockStoreMockRecorder) GetLinksByUserCodeAsc(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinksByUserCodeAsc", reflect.TypeOf((*MockStore)(nil).GetLinksByUserCodeAsc), arg0, arg1)
+}
+
+// GetLinksByUserCodeDesc mocks base method.
+func (m *MockStore) GetLinksByUserCodeDesc(arg0 context.Context, arg1 db.GetLinksByUserCodeDescParams) ([]db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLinksByUserCodeDesc", arg0, arg1)
+	ret0, _ := ret[0].([]db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLinksByUserCodeDesc indicates an expected call of GetLinksByUserCodeDesc.
+func (mr *MockStoreMockRecorder) GetLinksByUserCodeDesc(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinksByUserCodeDesc", reflect.TypeOf((*MockStore)(nil).GetLinksByUserCodeDesc), arg0, arg1)
+}
+
+// GetLinksByUserCreatedAsc mocks base method.
+func (m *MockStore) GetLinksByUserCreatedAsc(arg0 context.Context, arg1 db.GetLinksByUserCreatedAscParams) ([]db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLinksByUserCreatedAsc", arg0, arg1)
+	ret0, _ := ret[0].([]db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLinksByUserCreatedAsc indicates an expected call of GetLinksByUserCreatedAsc.
+func (mr *MockStoreMockRecorder) GetLinksByUserCreatedAsc(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinksByUserCreatedAsc", reflect.TypeOf((*MockStore)(nil).GetLinksByUserCreatedAsc), arg0, arg1)
+}
+
+// GetLinksByUserCreatedDesc mocks base method.
+func (m *MockStore) GetLinksByUserCreatedDesc(arg0 context.Context, arg1 db.GetLinksByUserCreatedDescParams) ([]db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLinksByUserCreatedDesc", arg0, arg1)
+	ret0, _ := ret[0].([]db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLinksByUserCreatedDesc indicates an expected call of GetLinksByUserCreatedDesc.
+func (mr *MockStoreMockRecorder) GetLinksByUserCreatedDesc(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinksByUserCreatedDesc", reflect.TypeOf((*MockStore)(nil).GetLinksByUserCreatedDesc), arg0, arg1)
+}
+
+// GetLinksDueHealthCheck mocks base method.
+func (m *MockStore) GetLinksDueHealthCheck(arg0 context.Context, arg1 db.GetLinksDueHealthCheckParams) ([]db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLinksDueHealthCheck", arg0, arg1)
+	ret0, _ := ret[0].([]db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLinksDueHealthCheck indicates an expected call of GetLinksDueHealthCheck.
+func (mr *MockStoreMockRecorder) GetLinksDueHealthCheck(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinksDueHealthCheck", reflect.TypeOf((*MockStore)(nil).GetLinksDueHealthCheck), arg0, arg1)
+}
+
+// GetLinksPendingMetadata mocks base method.
+func (m *MockStore) GetLinksPendingMetadata(arg0 context.Context, arg1 int32) ([]db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLinksPendingMetadata", arg0, arg1)
+	ret0, _ := ret[0].([]db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLinksPendingMetadata indicates an expected call of GetLinksPendingMetadata.
+func (mr *MockStoreMockRecorder) GetLinksPendingMetadata(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinksPendingMetadata", reflect.TypeOf((*MockStore)(nil).GetLinksPendingMetadata), arg0, arg1)
+}
+
+// GetPixel mocks base method.
+func (m *MockStore) GetPixel(arg0 context.Context, arg1 int64) (db.Pixel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPixel", arg0, arg1)
+	ret0, _ := ret[0].(db.Pixel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPixel indicates an expected call of GetPixel.
+func (mr *MockStoreMockRecorder) GetPixel(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPixel", reflect.TypeOf((*MockStore)(nil).GetPixel), arg0, arg1)
+}
+
+// GetPixelsByLink mocks base method.
+func (m *MockStore) GetPixelsByLink(arg0 context.Context, arg1 int64) ([]db.Pixel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPixelsByLink", arg0, arg1)
+	ret0, _ := ret[0].([]db.Pixel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPixelsByLink indicates an expected call of GetPixelsByLink.
+func (mr *MockStoreMockRecorder) GetPixelsByLink(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPixelsByLink", reflect.TypeOf((*MockStore)(nil).GetPixelsByLink), arg0, arg1)
+}
+
+// GetPixelsByUser mocks base method.
+func (m *MockStore) GetPixelsByUser(arg0 context.Context, arg1 int64) ([]db.Pixel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPixelsByUser", arg0, arg1)
+	ret0, _ := ret[0].([]db.Pixel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPixelsByUser indicates an expected call of GetPixelsByUser.
+func (mr *MockStoreMockRecorder) GetPixelsByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPixelsByUser", reflect.TypeOf((*MockStore)(nil).GetPixelsByUser), arg0, arg1)
+}
+
+// GetSession mocks base method.
+func (m *MockStore) GetSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
+	ret0, _ := ret[0].(db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockStoreMockRecorder) GetSession(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), arg0, arg1)
+}
+
+// GetSessions mocks base method.
+func (m *MockStore) GetSessions(arg0 context.Context, arg1 db.GetSessionsParams) ([]db.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessions", arg0, arg1)
+	ret0, _ := ret[0].([]db.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessions indicates an expected call of GetSessions.
+func (mr *MockStoreMockRecorder) GetSessions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessions", reflect.TypeOf((*MockStore)(nil).GetSessions), arg0, arg1)
+}
+
+// GetTag mocks base method.
+func (m *MockStore) GetTag(arg0 context.Context, arg1 int64) (db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTag", arg0, arg1)
+	ret0, _ := ret[0].(db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTag indicates an expected call of GetTag.
+func (mr *MockStoreMockRecorder) GetTag(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTag", reflect.TypeOf((*MockStore)(nil).GetTag), arg0, arg1)
+}
+
+// GetTagsByLink mocks base method.
+func (m *MockStore) GetTagsByLink(arg0 context.Context, arg1 int64) ([]db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagsByLink", arg0, arg1)
+	ret0, _ := ret[0].([]db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagsByLink indicates an expected call of GetTagsByLink.
+func (mr *MockStoreMockRecorder) GetTagsByLink(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagsByLink", reflect.TypeOf((*MockStore)(nil).GetTagsByLink), arg0, arg1)
+}
+
+// GetTagsByUser mocks base method.
+func (m *MockStore) GetTagsByUser(arg0 context.Context, arg1 int64) ([]db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTagsByUser", arg0, arg1)
+	ret0, _ := ret[0].([]db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTagsByUser indicates an expected call of GetTagsByUser.
+func (mr *MockStoreMockRecorder) GetTagsByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTagsByUser", reflect.TypeOf((*MockStore)(nil).GetTagsByUser), arg0, arg1)
+}
+
+// GetUnblockedSessionIDs mocks base method.
+func (m *MockStore) GetUnblockedSessionIDs(arg0 context.Context, arg1 int64) ([]uuid.UUID, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnblockedSessionIDs", arg0, arg1)
+	ret0, _ := ret[0].([]uuid.UUID)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnblockedSessionIDs indicates an expected call of GetUnblockedSessionIDs.
+func (mr *MockStoreMockRecorder) GetUnblockedSessionIDs(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnblockedSessionIDs", reflect.TypeOf((*MockStore)(nil).GetUnblockedSessionIDs), arg0, arg1)
+}
+
+// GetUser mocks base method.
+func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+}
+
+// GetUserById mocks base method.
+func (m *MockStore) GetUserById(arg0 context.Context, arg1 int64) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserById", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserById indicates an expected call of GetUserById.
+func (mr *MockStoreMockRecorder) GetUserById(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserById", reflect.TypeOf((*MockStore)(nil).GetUserById), arg0, arg1)
+}
+
+// GetVerifiedDomainByHostname mocks base method.
+func (m *MockStore) GetVerifiedDomainByHostname(arg0 context.Context, arg1 string) (db.Domain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVerifiedDomainByHostname", arg0, arg1)
+	ret0, _ := ret[0].(db.Domain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVerifiedDomainByHostname indicates an expected call of GetVerifiedDomainByHostname.
+func (mr *MockStoreMockRecorder) GetVerifiedDomainByHostname(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVerifiedDomainByHostname", reflect.TypeOf((*MockStore)(nil).GetVerifiedDomainByHostname), arg0, arg1)
+}
+
+// GetWebhook mocks base method.
+func (m *MockStore) GetWebhook(arg0 context.Context, arg1 int64) (db.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhook", arg0, arg1)
+	ret0, _ := ret[0].(db.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhook indicates an expected call of GetWebhook.
+func (mr *MockStoreMockRecorder) GetWebhook(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhook", reflect.TypeOf((*MockStore)(nil).GetWebhook), arg0, arg1)
+}
+
+// GetWebhookDeliveries mocks base method.
+func (m *MockStore) GetWebhookDeliveries(arg0 context.Context, arg1 db.GetWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDeliveries", arg0, arg1)
+	ret0, _ := ret[0].([]db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDeliveries indicates an expected call of GetWebhookDeliveries.
+func (mr *MockStoreMockRecorder) GetWebhookDeliveries(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDeliveries", reflect.TypeOf((*MockStore)(nil).GetWebhookDeliveries), arg0, arg1)
+}
+
+// GetWebhookDelivery mocks base method.
+func (m *MockStore) GetWebhookDelivery(arg0 context.Context, arg1 int64) (db.WebhookDelivery, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhookDelivery indicates an expected call of GetWebhookDelivery.
+func (mr *MockStoreMockRecorder) GetWebhookDelivery(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhookDelivery", reflect.TypeOf((*MockStore)(nil).GetWebhookDelivery), arg0, arg1)
+}
+
+// GetWebhooksByUser mocks base method.
+func (m *MockStore) GetWebhooksByUser(arg0 context.Context, arg1 int64) ([]db.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooksByUser", arg0, arg1)
+	ret0, _ := ret[0].([]db.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooksByUser indicates an expected call of GetWebhooksByUser.
+func (mr *MockStoreMockRecorder) GetWebhooksByUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooksByUser", reflect.TypeOf((*MockStore)(nil).GetWebhooksByUser), arg0, arg1)
+}
+
+// GetWebhooksDueClickBatch mocks base method.
+func (m *MockStore) GetWebhooksDueClickBatch(arg0 context.Context, arg1 int32) ([]db.Webhook, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWebhooksDueClickBatch", arg0, arg1)
+	ret0, _ := ret[0].([]db.Webhook)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWebhooksDueClickBatch indicates an expected call of GetWebhooksDueClickBatch.
+func (mr *MockStoreMockRecorder) GetWebhooksDueClickBatch(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWebhooksDueClickBatch", reflect.TypeOf((*MockStore)(nil).GetWebhooksDueClickBatch), arg0, arg1)
+}
+
+// ImportLink mocks base method.
+func (m *MockStore) ImportLink(arg0 context.Context, arg1 db.ImportLinkParams) (db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportLink", arg0, arg1)
+	ret0, _ := ret[0].(db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportLink indicates an expected call of ImportLink.
+func (mr *MockStoreMockRecorder) ImportLink(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportLink", reflect.TypeOf((*MockStore)(nil).ImportLink), arg0, arg1)
+}
+
+// ImportLinksTx mocks base method.
+func (m *MockStore) ImportLinksTx(arg0 context.Context, arg1 db.ImportLinksTxParams) (db.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportLinksTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportJob)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// BlockSession indicates an expected call of BlockSession.
-func (mr *MockStoreMockRecorder) BlockSession(arg0, arg1 any) *gomock.Call {
+// ImportLinksTx indicates an expected call of ImportLinksTx.
+func (mr *MockStoreMockRecorder) ImportLinksTx(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BlockSession", reflect.TypeOf((*MockStore)(nil).BlockSession), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportLinksTx", reflect.TypeOf((*MockStore)(nil).ImportLinksTx), arg0, arg1)
 }
 
-// CreateLink mocks base method.
-func (m *MockStore) CreateLink(arg0 context.Context, arg1 db.CreateLinkParams) (db.Link, error) {
+// MarkDomainVerified mocks base method.
+func (m *MockStore) MarkDomainVerified(arg0 context.Context, arg1 int64) (db.Domain, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateLink", arg0, arg1)
-	ret0, _ := ret[0].(db.Link)
+	ret := m.ctrl.Call(m, "MarkDomainVerified", arg0, arg1)
+	ret0, _ := ret[0].(db.Domain)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateLink indicates an expected call of CreateLink.
-func (mr *MockStoreMockRecorder) CreateLink(arg0, arg1 any) *gomock.Call {
+// MarkDomainVerified indicates an expected call of MarkDomainVerified.
+func (mr *MockStoreMockRecorder) MarkDomainVerified(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateLink", reflect.TypeOf((*MockStore)(nil).CreateLink), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDomainVerified", reflect.TypeOf((*MockStore)(nil).MarkDomainVerified), arg0, arg1)
 }
 
-// CreateSession mocks base method.
-func (m *MockStore) CreateSession(arg0 context.Context, arg1 db.CreateSessionParams) (db.Session, error) {
+// MarkLinkExpiryNotified mocks base method.
+func (m *MockStore) MarkLinkExpiryNotified(arg0 context.Context, arg1 int64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateSession", arg0, arg1)
-	ret0, _ := ret[0].(db.Session)
+	ret := m.ctrl.Call(m, "MarkLinkExpiryNotified", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkLinkExpiryNotified indicates an expected call of MarkLinkExpiryNotified.
+func (mr *MockStoreMockRecorder) MarkLinkExpiryNotified(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkLinkExpiryNotified", reflect.TypeOf((*MockStore)(nil).MarkLinkExpiryNotified), arg0, arg1)
+}
+
+// NotifyLinkExpiredTx mocks base method.
+func (m *MockStore) NotifyLinkExpiredTx(arg0 context.Context, arg1 db.NotifyLinkExpiredTxParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "NotifyLinkExpiredTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// NotifyLinkExpiredTx indicates an expected call of NotifyLinkExpiredTx.
+func (mr *MockStoreMockRecorder) NotifyLinkExpiredTx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NotifyLinkExpiredTx", reflect.TypeOf((*MockStore)(nil).NotifyLinkExpiredTx), arg0, arg1)
+}
+
+// RecordClick mocks base method.
+func (m *MockStore) RecordClick(arg0 context.Context, arg1 db.RecordClickParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordClick", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateSession indicates an expected call of CreateSession.
-func (mr *MockStoreMockRecorder) CreateSession(arg0, arg1 any) *gomock.Call {
+// RecordClick indicates an expected call of RecordClick.
+func (mr *MockStoreMockRecorder) RecordClick(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStore)(nil).CreateSession), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordClick", reflect.TypeOf((*MockStore)(nil).RecordClick), arg0, arg1)
 }
 
-// CreateUser mocks base method.
-func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
+// RecordWebhookAttempt mocks base method.
+func (m *MockStore) RecordWebhookAttempt(arg0 context.Context, arg1 db.RecordWebhookAttemptParams) (db.WebhookDelivery, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "RecordWebhookAttempt", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CreateUser indicates an expected call of CreateUser.
-func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 any) *gomock.Call {
+// RecordWebhookAttempt indicates an expected call of RecordWebhookAttempt.
+func (mr *MockStoreMockRecorder) RecordWebhookAttempt(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWebhookAttempt", reflect.TypeOf((*MockStore)(nil).RecordWebhookAttempt), arg0, arg1)
 }
 
-// GetActiveSessions mocks base method.
-func (m *MockStore) GetActiveSessions(arg0 context.Context, arg1 db.GetActiveSessionsParams) ([]db.Session, error) {
+// RedeliverWebhookDelivery mocks base method.
+func (m *MockStore) RedeliverWebhookDelivery(arg0 context.Context, arg1 int64) (db.WebhookDelivery, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetActiveSessions", arg0, arg1)
-	ret0, _ := ret[0].([]db.Session)
+	ret := m.ctrl.Call(m, "RedeliverWebhookDelivery", arg0, arg1)
+	ret0, _ := ret[0].(db.WebhookDelivery)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetActiveSessions indicates an expected call of GetActiveSessions.
-func (mr *MockStoreMockRecorder) GetActiveSessions(arg0, arg1 any) *gomock.Call {
+// RedeliverWebhookDelivery indicates an expected call of RedeliverWebhookDelivery.
+func (mr *MockStoreMockRecorder) RedeliverWebhookDelivery(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveSessions", reflect.TypeOf((*MockStore)(nil).GetActiveSessions), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedeliverWebhookDelivery", reflect.TypeOf((*MockStore)(nil).RedeliverWebhookDelivery), arg0, arg1)
 }
 
-// GetLinkByCode mocks base method.
-func (m *MockStore) GetLinkByCode(arg0 context.Context, arg1 string) (db.Link, error) {
+// RemoveCampaignLinks mocks base method.
+func (m *MockStore) RemoveCampaignLinks(arg0 context.Context, arg1 db.RemoveCampaignLinksParams) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLinkByCode", arg0, arg1)
-	ret0, _ := ret[0].(db.Link)
+	ret := m.ctrl.Call(m, "RemoveCampaignLinks", arg0, arg1)
+	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetLinkByCode indicates an expected call of GetLinkByCode.
-func (mr *MockStoreMockRecorder) GetLinkByCode(arg0, arg1 any) *gomock.Call {
+// RemoveCampaignLinks indicates an expected call of RemoveCampaignLinks.
+func (mr *MockStoreMockRecorder) RemoveCampaignLinks(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinkByCode", reflect.TypeOf((*MockStore)(nil).GetLinkByCode), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveCampaignLinks", reflect.TypeOf((*MockStore)(nil).RemoveCampaignLinks), arg0, arg1)
 }
 
-// GetLinkById mocks base method.
-func (m *MockStore) GetLinkById(arg0 context.Context, arg1 int64) (db.Link, error) {
+// ResetPasswordTx mocks base method.
+func (m *MockStore) ResetPasswordTx(arg0 context.Context, arg1 db.ResetPasswordTxParams) (db.User, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLinkById", arg0, arg1)
+	ret := m.ctrl.Call(m, "ResetPasswordTx", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetPasswordTx indicates an expected call of ResetPasswordTx.
+func (mr *MockStoreMockRecorder) ResetPasswordTx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPasswordTx", reflect.TypeOf((*MockStore)(nil).ResetPasswordTx), arg0, arg1)
+}
+
+// RevokeUserTokens mocks base method.
+func (m *MockStore) RevokeUserTokens(arg0 context.Context, arg1 db.RevokeUserTokensParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserTokens", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserTokens indicates an expected call of RevokeUserTokens.
+func (mr *MockStoreMockRecorder) RevokeUserTokens(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserTokens", reflect.TypeOf((*MockStore)(nil).RevokeUserTokens), arg0, arg1)
+}
+
+// SetBioPageLinksTx mocks base method.
+func (m *MockStore) SetBioPageLinksTx(arg0 context.Context, arg1 db.SetBioPageLinksTxParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetBioPageLinksTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetBioPageLinksTx indicates an expected call of SetBioPageLinksTx.
+func (mr *MockStoreMockRecorder) SetBioPageLinksTx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetBioPageLinksTx", reflect.TypeOf((*MockStore)(nil).SetBioPageLinksTx), arg0, arg1)
+}
+
+// SetDomainFallbacks mocks base method.
+func (m *MockStore) SetDomainFallbacks(arg0 context.Context, arg1 db.SetDomainFallbacksParams) (db.Domain, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetDomainFallbacks", arg0, arg1)
+	ret0, _ := ret[0].(db.Domain)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetDomainFallbacks indicates an expected call of SetDomainFallbacks.
+func (mr *MockStoreMockRecorder) SetDomainFallbacks(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetDomainFallbacks", reflect.TypeOf((*MockStore)(nil).SetDomainFallbacks), arg0, arg1)
+}
+
+// SetLinkCloaked mocks base method.
+func (m *MockStore) SetLinkCloaked(arg0 context.Context, arg1 db.SetLinkCloakedParams) (db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLinkCloaked", arg0, arg1)
 	ret0, _ := ret[0].(db.Link)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetLinkById indicates an expected call of GetLinkById.
-func (mr *MockStoreMockRecorder) GetLinkById(arg0, arg1 any) *gomock.Call {
+// SetLinkCloaked indicates an expected call of SetLinkCloaked.
+func (mr *MockStoreMockRecorder) SetLinkCloaked(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinkById", reflect.TypeOf((*MockStore)(nil).GetLinkById), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLinkCloaked", reflect.TypeOf((*MockStore)(nil).SetLinkCloaked), arg0, arg1)
 }
 
-// GetLinks mocks base method.
-func (m *MockStore) GetLinks(arg0 context.Context, arg1 db.GetLinksParams) ([]db.Link, error) {
+// SetLinkFolder mocks base method.
+func (m *MockStore) SetLinkFolder(arg0 context.Context, arg1 db.SetLinkFolderParams) (db.Link, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLinks", arg0, arg1)
-	ret0, _ := ret[0].([]db.Link)
+	ret := m.ctrl.Call(m, "SetLinkFolder", arg0, arg1)
+	ret0, _ := ret[0].(db.Link)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetLinks indicates an expected call of GetLinks.
-func (mr *MockStoreMockRecorder) GetLinks(arg0, arg1 any) *gomock.Call {
+// SetLinkFolder indicates an expected call of SetLinkFolder.
+func (mr *MockStoreMockRecorder) SetLinkFolder(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinks", reflect.TypeOf((*MockStore)(nil).GetLinks), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLinkFolder", reflect.TypeOf((*MockStore)(nil).SetLinkFolder), arg0, arg1)
 }
 
-// GetLinksByUser mocks base method.
-func (m *MockStore) GetLinksByUser(arg0 context.Context, arg1 db.GetLinksByUserParams) ([]db.Link, error) {
+// SetLinkHasPixels mocks base method.
+func (m *MockStore) SetLinkHasPixels(arg0 context.Context, arg1 db.SetLinkHasPixelsParams) (db.Link, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetLinksByUser", arg0, arg1)
-	ret0, _ := ret[0].([]db.Link)
+	ret := m.ctrl.Call(m, "SetLinkHasPixels", arg0, arg1)
+	ret0, _ := ret[0].(db.Link)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetLinksByUser indicates an expected call of GetLinksByUser.
-func (mr *MockStoreMockRecorder) GetLinksByUser(arg0, arg1 any) *gomock.Call {
+// SetLinkHasPixels indicates an expected call of SetLinkHasPixels.
+func (mr *MockStoreMockRecorder) SetLinkHasPixels(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLinksByUser", reflect.TypeOf((*MockStore)(nil).GetLinksByUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLinkHasPixels", reflect.TypeOf((*MockStore)(nil).SetLinkHasPixels), arg0, arg1)
 }
 
-// GetSession mocks base method.
-func (m *MockStore) GetSession(arg0 context.Context, arg1 uuid.UUID) (db.Session, error) {
+// SetLinkInterstitial mocks base method.
+func (m *MockStore) SetLinkInterstitial(arg0 context.Context, arg1 db.SetLinkInterstitialParams) (db.Link, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSession", arg0, arg1)
-	ret0, _ := ret[0].(db.Session)
+	ret := m.ctrl.Call(m, "SetLinkInterstitial", arg0, arg1)
+	ret0, _ := ret[0].(db.Link)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSession indicates an expected call of GetSession.
-func (mr *MockStoreMockRecorder) GetSession(arg0, arg1 any) *gomock.Call {
+// SetLinkInterstitial indicates an expected call of SetLinkInterstitial.
+func (mr *MockStoreMockRecorder) SetLinkInterstitial(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStore)(nil).GetSession), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLinkInterstitial", reflect.TypeOf((*MockStore)(nil).SetLinkInterstitial), arg0, arg1)
 }
 
-// GetSessions mocks base method.
-func (m *MockStore) GetSessions(arg0 context.Context, arg1 db.GetSessionsParams) ([]db.Session, error) {
+// SetLinkLimits mocks base method.
+func (m *MockStore) SetLinkLimits(arg0 context.Context, arg1 db.SetLinkLimitsParams) (db.Link, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetSessions", arg0, arg1)
-	ret0, _ := ret[0].([]db.Session)
+	ret := m.ctrl.Call(m, "SetLinkLimits", arg0, arg1)
+	ret0, _ := ret[0].(db.Link)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetSessions indicates an expected call of GetSessions.
-func (mr *MockStoreMockRecorder) GetSessions(arg0, arg1 any) *gomock.Call {
+// SetLinkLimits indicates an expected call of SetLinkLimits.
+func (mr *MockStoreMockRecorder) SetLinkLimits(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessions", reflect.TypeOf((*MockStore)(nil).GetSessions), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLinkLimits", reflect.TypeOf((*MockStore)(nil).SetLinkLimits), arg0, arg1)
 }
 
-// GetUser mocks base method.
-func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
+// SetLinkPixelsTx mocks base method.
+func (m *MockStore) SetLinkPixelsTx(arg0 context.Context, arg1 db.SetLinkPixelsTxParams) (db.Link, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUser", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "SetLinkPixelsTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Link)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUser indicates an expected call of GetUser.
-func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 any) *gomock.Call {
+// SetLinkPixelsTx indicates an expected call of SetLinkPixelsTx.
+func (mr *MockStoreMockRecorder) SetLinkPixelsTx(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLinkPixelsTx", reflect.TypeOf((*MockStore)(nil).SetLinkPixelsTx), arg0, arg1)
 }
 
-// GetUserById mocks base method.
-func (m *MockStore) GetUserById(arg0 context.Context, arg1 int64) (db.User, error) {
+// StartImportJob mocks base method.
+func (m *MockStore) StartImportJob(arg0 context.Context, arg1 db.StartImportJobParams) (db.ImportJob, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetUserById", arg0, arg1)
-	ret0, _ := ret[0].(db.User)
+	ret := m.ctrl.Call(m, "StartImportJob", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportJob)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetUserById indicates an expected call of GetUserById.
-func (mr *MockStoreMockRecorder) GetUserById(arg0, arg1 any) *gomock.Call {
+// StartImportJob indicates an expected call of StartImportJob.
+func (mr *MockStoreMockRecorder) StartImportJob(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserById", reflect.TypeOf((*MockStore)(nil).GetUserById), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartImportJob", reflect.TypeOf((*MockStore)(nil).StartImportJob), arg0, arg1)
 }
 
 // ToggleStatus mocks base method.
@@ -251,6 +1797,21 @@ func (mr *MockStoreMockRecorder) ToggleStatus(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ToggleStatus", reflect.TypeOf((*MockStore)(nil).ToggleStatus), arg0, arg1)
 }
 
+// UpdateBioPage mocks base method.
+func (m *MockStore) UpdateBioPage(arg0 context.Context, arg1 db.UpdateBioPageParams) (db.BioPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBioPage", arg0, arg1)
+	ret0, _ := ret[0].(db.BioPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateBioPage indicates an expected call of UpdateBioPage.
+func (mr *MockStoreMockRecorder) UpdateBioPage(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBioPage", reflect.TypeOf((*MockStore)(nil).UpdateBioPage), arg0, arg1)
+}
+
 // UpdateCode mocks base method.
 func (m *MockStore) UpdateCode(arg0 context.Context, arg1 db.UpdateCodeParams) (db.Link, error) {
 	m.ctrl.T.Helper()
@@ -266,6 +1827,111 @@ func (mr *MockStoreMockRecorder) UpdateCode(arg0, arg1 any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCode", reflect.TypeOf((*MockStore)(nil).UpdateCode), arg0, arg1)
 }
 
+// UpdateFolder mocks base method.
+func (m *MockStore) UpdateFolder(arg0 context.Context, arg1 db.UpdateFolderParams) (db.Folder, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateFolder", arg0, arg1)
+	ret0, _ := ret[0].(db.Folder)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateFolder indicates an expected call of UpdateFolder.
+func (mr *MockStoreMockRecorder) UpdateFolder(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateFolder", reflect.TypeOf((*MockStore)(nil).UpdateFolder), arg0, arg1)
+}
+
+// UpdateImportJobProgress mocks base method.
+func (m *MockStore) UpdateImportJobProgress(arg0 context.Context, arg1 db.UpdateImportJobProgressParams) (db.ImportJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateImportJobProgress", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateImportJobProgress indicates an expected call of UpdateImportJobProgress.
+func (mr *MockStoreMockRecorder) UpdateImportJobProgress(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateImportJobProgress", reflect.TypeOf((*MockStore)(nil).UpdateImportJobProgress), arg0, arg1)
+}
+
+// UpdateLinkDetails mocks base method.
+func (m *MockStore) UpdateLinkDetails(arg0 context.Context, arg1 db.UpdateLinkDetailsParams) (db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLinkDetails", arg0, arg1)
+	ret0, _ := ret[0].(db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLinkDetails indicates an expected call of UpdateLinkDetails.
+func (mr *MockStoreMockRecorder) UpdateLinkDetails(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLinkDetails", reflect.TypeOf((*MockStore)(nil).UpdateLinkDetails), arg0, arg1)
+}
+
+// UpdateLinkHealth mocks base method.
+func (m *MockStore) UpdateLinkHealth(arg0 context.Context, arg1 db.UpdateLinkHealthParams) (db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLinkHealth", arg0, arg1)
+	ret0, _ := ret[0].(db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLinkHealth indicates an expected call of UpdateLinkHealth.
+func (mr *MockStoreMockRecorder) UpdateLinkHealth(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLinkHealth", reflect.TypeOf((*MockStore)(nil).UpdateLinkHealth), arg0, arg1)
+}
+
+// UpdateLinkHealthTx mocks base method.
+func (m *MockStore) UpdateLinkHealthTx(arg0 context.Context, arg1 db.UpdateLinkHealthTxParams) (db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLinkHealthTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLinkHealthTx indicates an expected call of UpdateLinkHealthTx.
+func (mr *MockStoreMockRecorder) UpdateLinkHealthTx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLinkHealthTx", reflect.TypeOf((*MockStore)(nil).UpdateLinkHealthTx), arg0, arg1)
+}
+
+// UpdateLinkMetadata mocks base method.
+func (m *MockStore) UpdateLinkMetadata(arg0 context.Context, arg1 db.UpdateLinkMetadataParams) (db.Link, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateLinkMetadata", arg0, arg1)
+	ret0, _ := ret[0].(db.Link)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateLinkMetadata indicates an expected call of UpdateLinkMetadata.
+func (mr *MockStoreMockRecorder) UpdateLinkMetadata(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateLinkMetadata", reflect.TypeOf((*MockStore)(nil).UpdateLinkMetadata), arg0, arg1)
+}
+
+// UpdateTag mocks base method.
+func (m *MockStore) UpdateTag(arg0 context.Context, arg1 db.UpdateTagParams) (db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTag", arg0, arg1)
+	ret0, _ := ret[0].(db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTag indicates an expected call of UpdateTag.
+func (mr *MockStoreMockRecorder) UpdateTag(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTag", reflect.TypeOf((*MockStore)(nil).UpdateTag), arg0, arg1)
+}
+
 // UpdateUser mocks base method.
 func (m *MockStore) UpdateUser(arg0 context.Context, arg1 db.UpdateUserParams) (db.User, error) {
 	m.ctrl.T.Helper()
@@ -280,3 +1946,93 @@ func (mr *MockStoreMockRecorder) UpdateUser(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockStore)(nil).UpdateUser), arg0, arg1)
 }
+
+// UpdateUserPassword mocks base method.
+func (m *MockStore) UpdateUserPassword(arg0 context.Context, arg1 db.UpdateUserPasswordParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserPassword", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserPassword indicates an expected call of UpdateUserPassword.
+func (mr *MockStoreMockRecorder) UpdateUserPassword(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPassword", reflect.TypeOf((*MockStore)(nil).UpdateUserPassword), arg0, arg1)
+}
+
+// UpdateUserPreferences mocks base method.
+func (m *MockStore) UpdateUserPreferences(arg0 context.Context, arg1 db.UpdateUserPreferencesParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserPreferences", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserPreferences indicates an expected call of UpdateUserPreferences.
+func (mr *MockStoreMockRecorder) UpdateUserPreferences(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPreferences", reflect.TypeOf((*MockStore)(nil).UpdateUserPreferences), arg0, arg1)
+}
+
+// UpsertTag mocks base method.
+func (m *MockStore) UpsertTag(arg0 context.Context, arg1 db.UpsertTagParams) (db.Tag, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertTag", arg0, arg1)
+	ret0, _ := ret[0].(db.Tag)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertTag indicates an expected call of UpsertTag.
+func (mr *MockStoreMockRecorder) UpsertTag(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertTag", reflect.TypeOf((*MockStore)(nil).UpsertTag), arg0, arg1)
+}
+
+// UseUserToken mocks base method.
+func (m *MockStore) UseUserToken(arg0 context.Context, arg1 db.UseUserTokenParams) (db.UserToken, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseUserToken", arg0, arg1)
+	ret0, _ := ret[0].(db.UserToken)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseUserToken indicates an expected call of UseUserToken.
+func (mr *MockStoreMockRecorder) UseUserToken(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseUserToken", reflect.TypeOf((*MockStore)(nil).UseUserToken), arg0, arg1)
+}
+
+// VerifyEmailTx mocks base method.
+func (m *MockStore) VerifyEmailTx(arg0 context.Context, arg1 string) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmailTx", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyEmailTx indicates an expected call of VerifyEmailTx.
+func (mr *MockStoreMockRecorder) VerifyEmailTx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmailTx", reflect.TypeOf((*MockStore)(nil).VerifyEmailTx), arg0, arg1)
+}
+
+// VerifyUserEmail mocks base method.
+func (m *MockStore) VerifyUserEmail(arg0 context.Context, arg1 int64) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyUserEmail", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyUserEmail indicates an expected call of VerifyUserEmail.
+func (mr *MockStoreMockRecorder) VerifyUserEmail(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyUserEmail", reflect.TypeOf((*MockStore)(nil).VerifyUserEmail), arg0, arg1)
+}
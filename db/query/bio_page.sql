@@ -0,0 +1,84 @@
+-- name: CreateBioPage :one
+INSERT INTO bio_pages (user_id, slug, title, description, avatar_url, theme, accent_color)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: GetBioPage :one
+SELECT *
+FROM bio_pages
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetBioPageBySlug :one
+SELECT *
+FROM bio_pages
+WHERE slug = $1
+LIMIT 1;
+
+-- name: GetBioPagesByUser :many
+SELECT *
+FROM bio_pages
+WHERE user_id = $1
+ORDER BY id;
+
+-- name: UpdateBioPage :one
+UPDATE bio_pages
+SET slug         = $2,
+    title        = $3,
+    description  = $4,
+    avatar_url   = $5,
+    theme        = $6,
+    accent_color = $7
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteBioPage :exec
+DELETE
+FROM bio_pages
+WHERE id = $1;
+
+-- name: GetBioPageLinks :many
+SELECT bio_page_links.link_id,
+       bio_page_links.label,
+       bio_page_links.icon_url,
+       links.link,
+       links.title,
+       links.meta_title,
+       links.active,
+       links.expires_at,
+       links.max_clicks,
+       links.click_count
+FROM bio_page_links
+         JOIN links ON links.id = bio_page_links.link_id
+WHERE bio_page_links.bio_page_id = $1
+ORDER BY bio_page_links.position;
+
+-- name: GetBioPageLink :one
+SELECT links.*
+FROM links
+         JOIN bio_page_links ON bio_page_links.link_id = links.id
+WHERE bio_page_links.bio_page_id = $1
+  AND bio_page_links.link_id = $2
+LIMIT 1;
+
+-- name: DeleteBioPageLinks :exec
+DELETE
+FROM bio_page_links
+WHERE bio_page_id = $1;
+
+-- name: AddBioPageLinks :execrows
+INSERT INTO bio_page_links (bio_page_id, link_id, position, label, icon_url)
+SELECT sqlc.arg(bio_page_id)::bigint, items.link_id, items.position, items.label, items.icon_url
+FROM unnest(sqlc.arg(link_ids)::bigint[], sqlc.arg(labels)::varchar[], sqlc.arg(icon_urls)::varchar[])
+         WITH ORDINALITY AS items(link_id, label, icon_url, position)
+         JOIN links ON links.id = items.link_id
+WHERE links.user_id = sqlc.arg(user_id);
+
+-- name: GetBioPageClickStats :many
+SELECT link_id,
+       count(*) AS clicks
+FROM clicks
+WHERE bio_page_id = sqlc.arg(bio_page_id)
+  AND (sqlc.narg(clicked_after)::timestamptz IS NULL OR created_at >= sqlc.narg(clicked_after))
+  AND (sqlc.narg(clicked_before)::timestamptz IS NULL OR created_at < sqlc.narg(clicked_before))
+GROUP BY link_id;
@@ -0,0 +1,64 @@
+-- name: CreateCampaign :one
+INSERT INTO campaigns (user_id, name, starts_at, ends_at)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetCampaign :one
+SELECT *
+FROM campaigns
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetCampaignsByUser :many
+SELECT *
+FROM campaigns
+WHERE user_id = $1
+ORDER BY created_at DESC, id DESC;
+
+-- name: DeleteCampaign :exec
+DELETE
+FROM campaigns
+WHERE id = $1;
+
+-- name: AddCampaignLinks :execrows
+INSERT INTO campaign_links (campaign_id, link_id)
+SELECT sqlc.arg(campaign_id)::bigint, links.id
+FROM links
+WHERE links.id = ANY (sqlc.arg(link_ids)::bigint[])
+  AND links.user_id = sqlc.arg(user_id)
+ON CONFLICT DO NOTHING;
+
+-- name: RemoveCampaignLinks :execrows
+DELETE
+FROM campaign_links
+WHERE campaign_id = sqlc.arg(campaign_id)
+  AND link_id = ANY (sqlc.arg(link_ids)::bigint[]);
+
+-- name: GetCampaignLinkStats :many
+SELECT links.id                AS link_id,
+       links.code,
+       links.link,
+       count(clicks.id)        AS clicks,
+       count(DISTINCT nullif(clicks.referrer, '')) AS referrers
+FROM campaign_links
+         JOIN links ON links.id = campaign_links.link_id
+         LEFT JOIN clicks ON clicks.link_id = links.id
+    AND (sqlc.narg(clicked_after)::timestamptz IS NULL OR clicks.created_at >= sqlc.narg(clicked_after))
+    AND (sqlc.narg(clicked_before)::timestamptz IS NULL OR clicks.created_at < sqlc.narg(clicked_before))
+WHERE campaign_links.campaign_id = sqlc.arg(campaign_id)
+GROUP BY links.id
+ORDER BY clicks DESC, links.id;
+
+-- name: GetCampaignChannelStats :many
+SELECT (CASE
+            WHEN clicks.bio_page_id IS NOT NULL THEN 'bio_page'
+            ELSE coalesce(link_host(nullif(clicks.referrer, '')), 'direct')
+    END)::varchar AS channel,
+       count(*)   AS clicks
+FROM clicks
+         JOIN campaign_links ON campaign_links.link_id = clicks.link_id
+WHERE campaign_links.campaign_id = sqlc.arg(campaign_id)
+  AND (sqlc.narg(clicked_after)::timestamptz IS NULL OR clicks.created_at >= sqlc.narg(clicked_after))
+  AND (sqlc.narg(clicked_before)::timestamptz IS NULL OR clicks.created_at < sqlc.narg(clicked_before))
+GROUP BY channel
+ORDER BY clicks DESC, channel;
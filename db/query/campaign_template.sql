@@ -0,0 +1,21 @@
+-- name: CreateCampaignTemplate :one
+INSERT INTO campaign_templates (user_id, name, utm_source, utm_medium, utm_campaign, utm_term, utm_content, params)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+RETURNING *;
+
+-- name: GetCampaignTemplate :one
+SELECT *
+FROM campaign_templates
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetCampaignTemplatesByUser :many
+SELECT *
+FROM campaign_templates
+WHERE user_id = $1
+ORDER BY name;
+
+-- name: DeleteCampaignTemplate :exec
+DELETE
+FROM campaign_templates
+WHERE id = $1;
@@ -0,0 +1,34 @@
+-- name: RecordClick :one
+with click as (
+    insert into clicks (link_id, referrer, user_agent, bio_page_id)
+        values ($1, $2, $3, $4)
+        returning id, referrer, user_agent, bio_page_id, created_at),
+     link as (
+         update links
+             set click_count = click_count + 1
+             where id = $1
+             returning id, user_id, code, click_count),
+     outbox as (
+         insert into webhook_click_outbox (webhook_id, payload)
+             select webhooks.id,
+                    jsonb_build_object('id', click.id, 'link_id', link.id, 'code', link.code,
+                                       'referrer', click.referrer, 'user_agent', click.user_agent,
+                                       'bio_page_id', click.bio_page_id, 'clicked_at', click.created_at)
+             from webhooks, click, link
+             where webhooks.user_id = link.user_id
+               and webhooks.active
+               and 'link.clicked' = any (webhooks.events))
+select click_count
+from link;
+
+-- name: GetClickStatsByLinks :many
+select link_id,
+       count(*)                          as clicks,
+       count(distinct nullif(referrer, '')) as referrers,
+       min(created_at)::timestamptz      as first_click_at,
+       max(created_at)::timestamptz      as last_click_at
+from clicks
+where link_id = any (sqlc.arg(link_ids)::bigint[])
+  and (sqlc.narg(clicked_after)::timestamptz is null or created_at >= sqlc.narg(clicked_after))
+  and (sqlc.narg(clicked_before)::timestamptz is null or created_at < sqlc.narg(clicked_before))
+group by link_id;
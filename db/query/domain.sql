@@ -0,0 +1,42 @@
+-- name: CreateDomain :one
+insert into domains (user_id, hostname, verification_token)
+values ($1, $2, $3)
+returning *;
+
+-- name: GetDomain :one
+select *
+from domains
+where id = $1
+limit 1;
+
+-- name: GetDomainsByUser :many
+select *
+from domains
+where user_id = $1
+order by id;
+
+-- name: GetVerifiedDomainByHostname :one
+select *
+from domains
+where hostname = $1
+  and verified_at is not null
+limit 1;
+
+-- name: MarkDomainVerified :one
+update domains
+set verified_at = now()
+where id = $1
+returning *;
+
+-- name: DeleteDomain :exec
+delete
+from domains
+where id = $1;
+
+-- name: SetDomainFallbacks :one
+update domains
+set root_redirect_url = $2,
+    not_found_url     = $3,
+    not_found_html    = $4
+where id = $1
+returning *;
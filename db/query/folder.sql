@@ -0,0 +1,27 @@
+-- name: CreateFolder :one
+INSERT INTO folders (user_id, name)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: GetFolder :one
+SELECT *
+FROM folders
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetFoldersByUser :many
+SELECT *
+FROM folders
+WHERE user_id = $1
+ORDER BY name;
+
+-- name: UpdateFolder :one
+UPDATE folders
+SET name = $1
+WHERE id = $2
+RETURNING *;
+
+-- name: DeleteFolder :exec
+DELETE
+FROM folders
+WHERE id = $1;
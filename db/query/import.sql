@@ -0,0 +1,64 @@
+-- name: CreateImportJob :one
+INSERT INTO import_jobs (user_id, source, format, payload)
+VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetImportJob :one
+SELECT *
+FROM import_jobs
+WHERE id = $1
+LIMIT 1;
+
+-- name: ClaimImportJob :one
+UPDATE import_jobs
+SET locked_until = now() + sqlc.arg(lease_seconds)::bigint * interval '1 second',
+    updated_at   = now()
+WHERE id = (SELECT id
+            FROM import_jobs
+            WHERE status IN ('pending', 'running')
+              AND (locked_until IS NULL OR locked_until < now())
+              AND (sqlc.narg(id)::bigint IS NULL OR id = sqlc.narg(id))
+            ORDER BY id
+            LIMIT 1 FOR UPDATE SKIP LOCKED)
+RETURNING *;
+
+-- name: StartImportJob :one
+UPDATE import_jobs
+SET status     = 'running',
+    total      = $1,
+    updated_at = now()
+WHERE id = $2
+RETURNING *;
+
+-- name: UpdateImportJobProgress :one
+UPDATE import_jobs
+SET processed    = processed + sqlc.arg(processed),
+    imported     = imported + sqlc.arg(imported),
+    conflicts    = conflicts + sqlc.arg(conflicts),
+    locked_until = now() + sqlc.arg(lease_seconds)::bigint * interval '1 second',
+    updated_at   = now()
+WHERE id = sqlc.arg(id)
+  AND processed = sqlc.arg(start)
+RETURNING *;
+
+-- name: FinishImportJob :one
+UPDATE import_jobs
+SET status       = $1,
+    error        = $2,
+    locked_until = NULL,
+    updated_at   = now()
+WHERE id = $3
+RETURNING *;
+
+-- name: CreateImportConflict :one
+INSERT INTO import_conflicts (job_id, line, original_code, link_id, reason)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetImportConflicts :many
+SELECT *
+FROM import_conflicts
+WHERE job_id = sqlc.arg(job_id)
+  AND line > sqlc.arg(after_line)
+ORDER BY line
+LIMIT sqlc.arg('limit');
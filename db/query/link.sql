@@ -1,6 +1,6 @@
 -- name: CreateLink :one
-INSERT INTO links (code, link, user_id)
-VALUES ($1, $2, $3)
+INSERT INTO links (code, link, user_id, title, notes, og_title, og_description, og_image, domain_id, utm_campaign, cloaked)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
 RETURNING *;
 
 -- name: GetLinks :many
@@ -9,12 +9,148 @@ FROM links
 ORDER BY id
 LIMIT $1 OFFSET $2;
 
--- name: GetLinksByUser :many
+-- name: GetLinksByUserCreatedAsc :many
 select *
 from links
-where user_id = $1
-order by id desc
-limit $2 offset $3;
+where user_id = sqlc.arg(user_id)
+  and (sqlc.narg(folder_id)::bigint is null or folder_id = sqlc.narg(folder_id))
+  and (sqlc.narg(tag_id)::bigint is null or exists(select 1
+                                                  from link_tags
+                                                  where link_tags.link_id = links.id
+                                                    and link_tags.tag_id = sqlc.narg(tag_id)))
+  and (sqlc.narg(active)::bool is null or active = sqlc.narg(active))
+  and (sqlc.narg(created_after)::timestamptz is null or created_at >= sqlc.narg(created_after))
+  and (sqlc.narg(created_before)::timestamptz is null or created_at < sqlc.narg(created_before))
+  and (sqlc.narg(domain)::varchar is null or link_host(link) = lower(sqlc.narg(domain)))
+  and (sqlc.narg(search)::varchar is null or
+       (code || ' ' || link || ' ' || title || ' ' || notes) ilike '%' || sqlc.narg(search) || '%')
+  and (sqlc.narg(campaign)::varchar is null or utm_campaign = sqlc.narg(campaign))
+  and (sqlc.narg(cursor_id)::bigint is null or
+       (created_at, id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)))
+order by created_at, id
+limit sqlc.arg('limit');
+
+-- name: GetLinksByUserCreatedDesc :many
+select *
+from links
+where user_id = sqlc.arg(user_id)
+  and (sqlc.narg(folder_id)::bigint is null or folder_id = sqlc.narg(folder_id))
+  and (sqlc.narg(tag_id)::bigint is null or exists(select 1
+                                                  from link_tags
+                                                  where link_tags.link_id = links.id
+                                                    and link_tags.tag_id = sqlc.narg(tag_id)))
+  and (sqlc.narg(active)::bool is null or active = sqlc.narg(active))
+  and (sqlc.narg(created_after)::timestamptz is null or created_at >= sqlc.narg(created_after))
+  and (sqlc.narg(created_before)::timestamptz is null or created_at < sqlc.narg(created_before))
+  and (sqlc.narg(domain)::varchar is null or link_host(link) = lower(sqlc.narg(domain)))
+  and (sqlc.narg(search)::varchar is null or
+       (code || ' ' || link || ' ' || title || ' ' || notes) ilike '%' || sqlc.narg(search) || '%')
+  and (sqlc.narg(campaign)::varchar is null or utm_campaign = sqlc.narg(campaign))
+  and (sqlc.narg(cursor_id)::bigint is null or
+       (created_at, id) < (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id)))
+order by created_at desc, id desc
+limit sqlc.arg('limit');
+
+-- name: GetLinksByUserClicksAsc :many
+select *
+from links
+where user_id = sqlc.arg(user_id)
+  and (sqlc.narg(folder_id)::bigint is null or folder_id = sqlc.narg(folder_id))
+  and (sqlc.narg(tag_id)::bigint is null or exists(select 1
+                                                  from link_tags
+                                                  where link_tags.link_id = links.id
+                                                    and link_tags.tag_id = sqlc.narg(tag_id)))
+  and (sqlc.narg(active)::bool is null or active = sqlc.narg(active))
+  and (sqlc.narg(created_after)::timestamptz is null or created_at >= sqlc.narg(created_after))
+  and (sqlc.narg(created_before)::timestamptz is null or created_at < sqlc.narg(created_before))
+  and (sqlc.narg(domain)::varchar is null or link_host(link) = lower(sqlc.narg(domain)))
+  and (sqlc.narg(search)::varchar is null or
+       (code || ' ' || link || ' ' || title || ' ' || notes) ilike '%' || sqlc.narg(search) || '%')
+  and (sqlc.narg(campaign)::varchar is null or utm_campaign = sqlc.narg(campaign))
+  and (sqlc.narg(cursor_id)::bigint is null or
+       (click_count, id) > (sqlc.narg(cursor_clicks)::bigint, sqlc.narg(cursor_id)))
+order by click_count, id
+limit sqlc.arg('limit');
+
+-- name: GetLinksByUserClicksDesc :many
+select *
+from links
+where user_id = sqlc.arg(user_id)
+  and (sqlc.narg(folder_id)::bigint is null or folder_id = sqlc.narg(folder_id))
+  and (sqlc.narg(tag_id)::bigint is null or exists(select 1
+                                                  from link_tags
+                                                  where link_tags.link_id = links.id
+                                                    and link_tags.tag_id = sqlc.narg(tag_id)))
+  and (sqlc.narg(active)::bool is null or active = sqlc.narg(active))
+  and (sqlc.narg(created_after)::timestamptz is null or created_at >= sqlc.narg(created_after))
+  and (sqlc.narg(created_before)::timestamptz is null or created_at < sqlc.narg(created_before))
+  and (sqlc.narg(domain)::varchar is null or link_host(link) = lower(sqlc.narg(domain)))
+  and (sqlc.narg(search)::varchar is null or
+       (code || ' ' || link || ' ' || title || ' ' || notes) ilike '%' || sqlc.narg(search) || '%')
+  and (sqlc.narg(campaign)::varchar is null or utm_campaign = sqlc.narg(campaign))
+  and (sqlc.narg(cursor_id)::bigint is null or
+       (click_count, id) < (sqlc.narg(cursor_clicks)::bigint, sqlc.narg(cursor_id)))
+order by click_count desc, id desc
+limit sqlc.arg('limit');
+
+-- name: GetLinksByUserCodeAsc :many
+select *
+from links
+where user_id = sqlc.arg(user_id)
+  and (sqlc.narg(folder_id)::bigint is null or folder_id = sqlc.narg(folder_id))
+  and (sqlc.narg(tag_id)::bigint is null or exists(select 1
+                                                  from link_tags
+                                                  where link_tags.link_id = links.id
+                                                    and link_tags.tag_id = sqlc.narg(tag_id)))
+  and (sqlc.narg(active)::bool is null or active = sqlc.narg(active))
+  and (sqlc.narg(created_after)::timestamptz is null or created_at >= sqlc.narg(created_after))
+  and (sqlc.narg(created_before)::timestamptz is null or created_at < sqlc.narg(created_before))
+  and (sqlc.narg(domain)::varchar is null or link_host(link) = lower(sqlc.narg(domain)))
+  and (sqlc.narg(search)::varchar is null or
+       (code || ' ' || link || ' ' || title || ' ' || notes) ilike '%' || sqlc.narg(search) || '%')
+  and (sqlc.narg(campaign)::varchar is null or utm_campaign = sqlc.narg(campaign))
+  and (sqlc.narg(cursor_id)::bigint is null or
+       (code, id) > (sqlc.narg(cursor_code)::varchar, sqlc.narg(cursor_id)))
+order by code, id
+limit sqlc.arg('limit');
+
+-- name: GetLinksByUserCodeDesc :many
+select *
+from links
+where user_id = sqlc.arg(user_id)
+  and (sqlc.narg(folder_id)::bigint is null or folder_id = sqlc.narg(folder_id))
+  and (sqlc.narg(tag_id)::bigint is null or exists(select 1
+                                                  from link_tags
+                                                  where link_tags.link_id = links.id
+                                                    and link_tags.tag_id = sqlc.narg(tag_id)))
+  and (sqlc.narg(active)::bool is null or active = sqlc.narg(active))
+  and (sqlc.narg(created_after)::timestamptz is null or created_at >= sqlc.narg(created_after))
+  and (sqlc.narg(created_before)::timestamptz is null or created_at < sqlc.narg(created_before))
+  and (sqlc.narg(domain)::varchar is null or link_host(link) = lower(sqlc.narg(domain)))
+  and (sqlc.narg(search)::varchar is null or
+       (code || ' ' || link || ' ' || title || ' ' || notes) ilike '%' || sqlc.narg(search) || '%')
+  and (sqlc.narg(campaign)::varchar is null or utm_campaign = sqlc.narg(campaign))
+  and (sqlc.narg(cursor_id)::bigint is null or
+       (code, id) < (sqlc.narg(cursor_code)::varchar, sqlc.narg(cursor_id)))
+order by code desc, id desc
+limit sqlc.arg('limit');
+
+-- name: CountLinksByUser :one
+select count(*)
+from links
+where user_id = sqlc.arg(user_id)
+  and (sqlc.narg(folder_id)::bigint is null or folder_id = sqlc.narg(folder_id))
+  and (sqlc.narg(tag_id)::bigint is null or exists(select 1
+                                                  from link_tags
+                                                  where link_tags.link_id = links.id
+                                                    and link_tags.tag_id = sqlc.narg(tag_id)))
+  and (sqlc.narg(active)::bool is null or active = sqlc.narg(active))
+  and (sqlc.narg(created_after)::timestamptz is null or created_at >= sqlc.narg(created_after))
+  and (sqlc.narg(created_before)::timestamptz is null or created_at < sqlc.narg(created_before))
+  and (sqlc.narg(domain)::varchar is null or link_host(link) = lower(sqlc.narg(domain)))
+  and (sqlc.narg(search)::varchar is null or
+       (code || ' ' || link || ' ' || title || ' ' || notes) ilike '%' || sqlc.narg(search) || '%')
+  and (sqlc.narg(campaign)::varchar is null or utm_campaign = sqlc.narg(campaign));
 
 -- name: GetLinkById :one
 select *
@@ -26,6 +162,14 @@ limit 1;
 select *
 from links
 where code = $1
+  and domain_id is null
+limit 1;
+
+-- name: GetLinkByDomainCode :one
+select *
+from links
+where domain_id = $1
+  and code = $2
 limit 1;
 
 -- name: UpdateCode :one
@@ -38,4 +182,133 @@ returning *;
 update links
 set active = $1
 where id = $2
-returning *;
\ No newline at end of file
+returning *;
+
+-- name: SetLinkInterstitial :one
+update links
+set interstitial = $1
+where id = $2
+returning *;
+
+-- name: SetLinkCloaked :one
+update links
+set cloaked = $1
+where id = $2
+returning *;
+
+-- name: SetLinkHasPixels :one
+update links
+set has_pixels = $1
+where id = $2
+returning *;
+
+-- name: UpdateLinkDetails :one
+update links
+set title          = coalesce(sqlc.narg(title), title),
+    notes          = coalesce(sqlc.narg(notes), notes),
+    og_title       = coalesce(sqlc.narg(og_title), og_title),
+    og_description = coalesce(sqlc.narg(og_description), og_description),
+    og_image       = coalesce(sqlc.narg(og_image), og_image)
+where id = sqlc.arg(id)
+returning *;
+
+-- name: GetLinksPendingMetadata :many
+select *
+from links
+where meta_fetched_at is null
+order by id
+limit $1;
+
+-- name: UpdateLinkMetadata :one
+update links
+set meta_title       = $1,
+    meta_description = $2,
+    meta_favicon     = $3,
+    meta_fetched_at  = now()
+where id = $4
+returning *;
+
+-- name: SetLinkFolder :one
+update links
+set folder_id = $1
+where id = $2
+returning *;
+
+-- name: ImportLink :one
+insert into links (code, link, user_id, title, created_at, click_count, utm_campaign)
+values (sqlc.arg(code), sqlc.arg(link), sqlc.arg(user_id), sqlc.arg(title),
+        coalesce(sqlc.narg(created_at), now()), sqlc.arg(click_count), sqlc.arg(utm_campaign))
+on conflict (code) where domain_id is null do nothing
+returning *;
+
+-- name: ExportLinksByUser :many
+select *
+from links
+where user_id = sqlc.arg(user_id)
+  and id > sqlc.arg(after_id)
+  and (sqlc.narg(campaign)::varchar is null or utm_campaign = sqlc.narg(campaign))
+order by id
+limit sqlc.arg('limit');
+
+-- name: GetActiveLinkByDestination :one
+select *
+from links
+where user_id = sqlc.arg(user_id)
+  and normalize_link(link) = normalize_link(sqlc.arg(link))
+  and active
+order by id
+limit 1;
+
+-- name: GetLinksDueHealthCheck :many
+select *
+from links
+where active
+  and (health_checked_at is null or health_checked_at < sqlc.arg(checked_before))
+order by health_checked_at nulls first, id
+limit sqlc.arg('limit');
+
+-- name: UpdateLinkHealth :one
+update links
+set health_status     = sqlc.arg(health_status),
+    health_latency_ms = sqlc.arg(health_latency_ms),
+    health_error      = sqlc.arg(health_error),
+    health_broken     = sqlc.arg(health_broken),
+    health_checked_at = now()
+where id = sqlc.arg(id)
+returning *;
+
+-- name: GetBrokenLinksByUser :many
+select *
+from links
+where user_id = sqlc.arg(user_id)
+  and health_broken
+  and (sqlc.narg(cursor_id)::bigint is null or id < sqlc.narg(cursor_id))
+order by id desc
+limit sqlc.arg('limit');
+
+-- name: CountBrokenLinksByUser :one
+select count(*)
+from links
+where user_id = $1
+  and health_broken;
+
+-- name: SetLinkLimits :one
+update links
+set expires_at      = sqlc.narg(expires_at),
+    max_clicks      = sqlc.narg(max_clicks),
+    expiry_notified = false
+where id = sqlc.arg(id)
+returning *;
+
+-- name: GetExpiredLinksToNotify :many
+select *
+from links
+where expires_at <= now()
+  and not expiry_notified
+order by expires_at
+limit $1;
+
+-- name: MarkLinkExpiryNotified :exec
+update links
+set expiry_notified = true
+where id = $1;
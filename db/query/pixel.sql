@@ -0,0 +1,40 @@
+-- name: CreatePixel :one
+INSERT INTO pixels (user_id, name, provider, account_id, src)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *;
+
+-- name: GetPixel :one
+SELECT *
+FROM pixels
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetPixelsByUser :many
+SELECT *
+FROM pixels
+WHERE user_id = $1
+ORDER BY name, id;
+
+-- name: GetPixelsByLink :many
+SELECT pixels.*
+FROM pixels
+         JOIN link_pixels ON link_pixels.pixel_id = pixels.id
+WHERE link_pixels.link_id = $1
+ORDER BY pixels.id;
+
+-- name: DeletePixel :exec
+DELETE
+FROM pixels
+WHERE id = $1;
+
+-- name: DeleteLinkPixels :exec
+DELETE
+FROM link_pixels
+WHERE link_id = $1;
+
+-- name: AddLinkPixels :execrows
+INSERT INTO link_pixels (link_id, pixel_id)
+SELECT sqlc.arg(link_id)::bigint, pixels.id
+FROM pixels
+WHERE pixels.id = ANY (sqlc.arg(pixel_ids)::bigint[])
+  AND pixels.user_id = sqlc.arg(user_id);
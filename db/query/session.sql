@@ -6,9 +6,21 @@ RETURNING *;
 -- name: GetSessions :many
 SELECT *
 FROM sessions
-WHERE user_id = $1
-ORDER BY created_at DESC
-LIMIT $2 OFFSET $3;
+WHERE user_id = sqlc.arg(user_id)
+  AND (sqlc.narg(cursor_id)::uuid IS NULL OR CASE
+        WHEN sqlc.arg(backward)::bool
+            THEN (created_at, id) > (sqlc.narg(cursor_created_at)::timestamptz, sqlc.narg(cursor_id))
+        ELSE (created_at, id) < (sqlc.narg(cursor_created_at), sqlc.narg(cursor_id)) END)
+ORDER BY CASE WHEN sqlc.arg(backward) THEN created_at END,
+         CASE WHEN sqlc.arg(backward) THEN id END,
+         created_at DESC,
+         id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CountSessions :one
+SELECT count(*)
+FROM sessions
+WHERE user_id = $1;
 
 -- name: GetActiveSessions :many
 SELECT *
@@ -27,4 +39,9 @@ limit 1;
 UPDATE sessions
 SET is_blocked = true
 WHERE id = $1
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: GetUnblockedSessionIDs :many
+SELECT id
+FROM sessions
+WHERE user_id = $1 AND is_blocked = false AND expires_at > NOW();
\ No newline at end of file
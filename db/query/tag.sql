@@ -0,0 +1,54 @@
+-- name: CreateTag :one
+INSERT INTO tags (user_id, name)
+VALUES ($1, $2)
+RETURNING *;
+
+-- name: GetTag :one
+SELECT *
+FROM tags
+WHERE id = $1
+LIMIT 1;
+
+-- name: GetTagsByUser :many
+SELECT *
+FROM tags
+WHERE user_id = $1
+ORDER BY name;
+
+-- name: GetTagsByLink :many
+SELECT tags.*
+FROM tags
+         JOIN link_tags ON link_tags.tag_id = tags.id
+WHERE link_tags.link_id = $1
+ORDER BY tags.name;
+
+-- name: UpdateTag :one
+UPDATE tags
+SET name = $1
+WHERE id = $2
+RETURNING *;
+
+-- name: DeleteTag :exec
+DELETE
+FROM tags
+WHERE id = $1;
+
+-- name: AttachTag :execrows
+INSERT INTO link_tags (link_id, tag_id)
+SELECT links.id, sqlc.arg(tag_id)::bigint
+FROM links
+WHERE links.id = ANY (sqlc.arg(link_ids)::bigint[])
+  AND links.user_id = sqlc.arg(user_id)
+ON CONFLICT DO NOTHING;
+
+-- name: DetachTag :execrows
+DELETE
+FROM link_tags
+WHERE tag_id = sqlc.arg(tag_id)
+  AND link_id = ANY (sqlc.arg(link_ids)::bigint[]);
+
+-- name: UpsertTag :one
+INSERT INTO tags (user_id, name)
+VALUES ($1, $2)
+ON CONFLICT (user_id, name) DO UPDATE SET name = excluded.name
+RETURNING *;
@@ -22,4 +22,23 @@ set first_name = $1,
     email      = $3,
     username   = $4
 where id = $5
-returning *;
\ No newline at end of file
+returning *;
+
+-- name: UpdateUserPreferences :one
+update users
+set reuse_duplicate_links = $1
+where id = $2
+returning *;
+
+-- name: VerifyUserEmail :one
+update users
+set email_verified_at = coalesce(email_verified_at, now())
+where id = $1
+returning *;
+
+-- name: UpdateUserPassword :one
+update users
+set hashed_password     = $1,
+    password_changed_at = $2
+where id = $3
+returning *;
@@ -0,0 +1,112 @@
+-- name: CreateWebhook :one
+insert into webhooks (user_id, url, secret, events, batch_size, batch_max_wait_seconds)
+values ($1, $2, $3, $4, $5, $6)
+returning *;
+
+-- name: GetWebhook :one
+select *
+from webhooks
+where id = $1
+limit 1;
+
+-- name: GetWebhooksByUser :many
+select *
+from webhooks
+where user_id = $1
+order by id;
+
+-- name: DeleteWebhook :exec
+delete
+from webhooks
+where id = $1;
+
+-- name: CreateWebhookDeliveries :many
+insert into webhook_deliveries (webhook_id, event, payload)
+select id, sqlc.arg(event)::varchar, sqlc.arg(payload)::jsonb
+from webhooks
+where user_id = sqlc.arg(user_id)
+  and active
+  and sqlc.arg(event) = any (events)
+returning *;
+
+-- name: ClaimWebhookDeliveries :many
+update webhook_deliveries
+set next_attempt_at = sqlc.arg(lease_until)
+where id in (select id
+             from webhook_deliveries
+             where status = 'pending'
+               and next_attempt_at <= now()
+             order by next_attempt_at
+             limit sqlc.arg('limit') for update skip locked)
+returning *;
+
+-- name: RecordWebhookAttempt :one
+update webhook_deliveries
+set status          = sqlc.arg(status),
+    attempts        = attempts + 1,
+    response_status = sqlc.arg(response_status),
+    error           = sqlc.arg(error),
+    next_attempt_at = sqlc.arg(next_attempt_at),
+    delivered_at    = case when sqlc.arg(status) = 'succeeded' then now() end
+where id = sqlc.arg(id)
+returning *;
+
+-- name: GetWebhookDelivery :one
+select *
+from webhook_deliveries
+where id = $1
+limit 1;
+
+-- name: GetWebhookDeliveries :many
+select *
+from webhook_deliveries
+where webhook_id = sqlc.arg(webhook_id)
+  and (sqlc.narg(cursor_id)::bigint is null or id < sqlc.narg(cursor_id))
+order by id desc
+limit sqlc.arg('limit');
+
+-- name: CountWebhookDeliveries :one
+select count(*)
+from webhook_deliveries
+where webhook_id = $1;
+
+-- name: RedeliverWebhookDelivery :one
+update webhook_deliveries
+set status          = 'pending',
+    attempts        = 0,
+    next_attempt_at = now(),
+    error           = ''
+where id = $1
+returning *;
+
+-- name: GetWebhooksDueClickBatch :many
+select *
+from webhooks
+where id in (select outbox.webhook_id
+             from webhook_click_outbox outbox
+                      join webhooks hook on hook.id = outbox.webhook_id
+             group by outbox.webhook_id, hook.batch_size, hook.batch_max_wait_seconds
+             having count(*) >= hook.batch_size
+                 or min(outbox.created_at) <= now() - make_interval(secs => hook.batch_max_wait_seconds))
+order by id
+limit $1;
+
+-- name: CreateClickBatchDelivery :one
+with batch as (
+    delete
+        from webhook_click_outbox
+            where id in (select id
+                         from webhook_click_outbox
+                         where webhook_id = sqlc.arg(webhook_id)
+                         order by id
+                         limit sqlc.arg('limit') for update skip locked)
+            returning id, payload)
+insert
+into webhook_deliveries (webhook_id, event, payload)
+select sqlc.arg(webhook_id),
+       'link.clicked',
+       jsonb_build_object('event', 'link.clicked', 'occurred_at', now(),
+                          'data', jsonb_build_object('clicks', jsonb_agg(payload order by id)))
+from batch
+having count(*) > 0
+returning *;
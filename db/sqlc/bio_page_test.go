@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func createRandomDbBioPage(t *testing.T, userID int64) BioPage {
+	arg := CreateBioPageParams{
+		UserID:      userID,
+		Slug:        strings.ToLower(util.RandomString(12)),
+		Title:       util.RandomString(10),
+		Theme:       "light",
+		AccentColor: "#1a56db",
+	}
+
+	page, err := testQueries.CreateBioPage(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Slug, page.Slug)
+	require.Equal(t, arg.Title, page.Title)
+
+	return page
+}
+
+func TestStore_SetBioPageLinksTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	first := createRandomDbLink(t)
+	page := createRandomDbBioPage(t, first.UserID)
+
+	second, err := testQueries.CreateLink(context.Background(), CreateLinkParams{
+		Code:   util.RandomCode(),
+		Link:   util.RandomLink(),
+		UserID: first.UserID,
+	})
+	require.NoError(t, err)
+
+	err = store.SetBioPageLinksTx(context.Background(), SetBioPageLinksTxParams{
+		BioPageID: page.ID,
+		UserID:    page.UserID,
+		Links: []BioPageLinkItem{
+			{LinkID: second.ID, Label: "Second first"},
+			{LinkID: first.ID, IconUrl: "https://cdn.example.com/icon.png"},
+		},
+	})
+	require.NoError(t, err)
+
+	links, err := testQueries.GetBioPageLinks(context.Background(), page.ID)
+	require.NoError(t, err)
+	require.Len(t, links, 2)
+	require.Equal(t, second.ID, links[0].LinkID)
+	require.Equal(t, "Second first", links[0].Label)
+	require.Equal(t, first.ID, links[1].LinkID)
+	require.Equal(t, first.Link, links[1].Link)
+
+	// Someone else's link rolls the whole replacement back
+	other := createRandomDbLink(t)
+	err = store.SetBioPageLinksTx(context.Background(), SetBioPageLinksTxParams{
+		BioPageID: page.ID,
+		UserID:    page.UserID,
+		Links:     []BioPageLinkItem{{LinkID: other.ID}},
+	})
+	require.ErrorIs(t, err, ErrBioPageLinkNotOwned)
+
+	links, err = testQueries.GetBioPageLinks(context.Background(), page.ID)
+	require.NoError(t, err)
+	require.Len(t, links, 2)
+
+	link, err := testQueries.GetBioPageLink(context.Background(), GetBioPageLinkParams{BioPageID: page.ID, LinkID: first.ID})
+	require.NoError(t, err)
+	require.Equal(t, first.ID, link.ID)
+
+	_, err = testQueries.GetBioPageLink(context.Background(), GetBioPageLinkParams{BioPageID: page.ID, LinkID: other.ID})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestQueries_GetBioPageClickStats(t *testing.T) {
+	link := createRandomDbLink(t)
+	page := createRandomDbBioPage(t, link.UserID)
+
+	for i := 0; i < 2; i++ {
+		_, err := testQueries.RecordClick(context.Background(), RecordClickParams{
+			LinkID:    link.ID,
+			BioPageID: pgtype.Int8{Int64: page.ID, Valid: true},
+		})
+		require.NoError(t, err)
+	}
+
+	clicks, err := testQueries.RecordClick(context.Background(), RecordClickParams{LinkID: link.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), clicks)
+
+	stats, err := testQueries.GetBioPageClickStats(context.Background(), GetBioPageClickStatsParams{BioPageID: page.ID})
+	require.NoError(t, err)
+	require.Equal(t, []GetBioPageClickStatsRow{{LinkID: link.ID, Clicks: 2}}, stats)
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestQueries_CampaignTemplates(t *testing.T) {
+	user := createRandomDbUser(t)
+
+	arg := CreateCampaignTemplateParams{
+		UserID:      user.ID,
+		Name:        util.RandomString(8),
+		UtmSource:   "newsletter",
+		UtmCampaign: util.RandomString(8),
+		Params:      []byte(`{"partner": "acme"}`),
+	}
+
+	template, err := testQueries.CreateCampaignTemplate(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Name, template.Name)
+	require.Equal(t, arg.UtmCampaign, template.UtmCampaign)
+	require.JSONEq(t, string(arg.Params), string(template.Params))
+
+	_, err = testQueries.CreateCampaignTemplate(context.Background(), arg)
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+
+	templates, err := testQueries.GetCampaignTemplatesByUser(context.Background(), user.ID)
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	require.Equal(t, template.ID, templates[0].ID)
+
+	require.NoError(t, testQueries.DeleteCampaignTemplate(context.Background(), template.ID))
+
+	_, err = testQueries.GetCampaignTemplate(context.Background(), template.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestQueries_GetLinksByCampaign(t *testing.T) {
+	user := createRandomDbUser(t)
+	campaign := util.RandomString(8)
+
+	tagged, err := testQueries.CreateLink(context.Background(), CreateLinkParams{
+		Code:        util.RandomCode(),
+		Link:        util.RandomLink() + "?utm_campaign=" + campaign,
+		UserID:      user.ID,
+		UtmCampaign: campaign,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.CreateLink(context.Background(), CreateLinkParams{
+		Code:   util.RandomCode(),
+		Link:   util.RandomLink(),
+		UserID: user.ID,
+	})
+	require.NoError(t, err)
+
+	links, err := testQueries.GetLinksByUser(context.Background(), GetLinksByUserParams{
+		UserID:   user.ID,
+		Campaign: pgtype.Text{String: campaign, Valid: true},
+		Sort:     "-created",
+		Limit:    10,
+	})
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	require.Equal(t, tagged.ID, links[0].ID)
+	require.Equal(t, campaign, links[0].UtmCampaign)
+
+	count, err := testQueries.CountLinksByUser(context.Background(), CountLinksByUserParams{
+		UserID:   user.ID,
+		Campaign: pgtype.Text{String: campaign, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+}
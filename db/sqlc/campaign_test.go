@@ -0,0 +1,77 @@
+package db
+
+import (
+	"context"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestQueries_CampaignStats(t *testing.T) {
+	link := createRandomDbLink(t)
+	idle, err := testQueries.CreateLink(context.Background(), CreateLinkParams{
+		Code:   util.RandomCode(),
+		Link:   util.RandomLink(),
+		UserID: link.UserID,
+	})
+	require.NoError(t, err)
+
+	other := createRandomDbLink(t)
+
+	campaign, err := testQueries.CreateCampaign(context.Background(), CreateCampaignParams{
+		UserID: link.UserID,
+		Name:   util.RandomString(8),
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.CreateCampaign(context.Background(), CreateCampaignParams{
+		UserID:   link.UserID,
+		Name:     util.RandomString(8),
+		StartsAt: pgtype.Timestamptz{Time: time.Now(), Valid: true},
+		EndsAt:   pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true},
+	})
+	require.Error(t, err)
+
+	// Links of another user are left out
+	added, err := testQueries.AddCampaignLinks(context.Background(), AddCampaignLinksParams{
+		CampaignID: campaign.ID,
+		LinkIds:    []int64{link.ID, idle.ID, other.ID},
+		UserID:     link.UserID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), added)
+
+	for _, referrer := range []string{"https://t.co/abc", "https://T.co/def", ""} {
+		_, err := testQueries.RecordClick(context.Background(), RecordClickParams{LinkID: link.ID, Referrer: referrer})
+		require.NoError(t, err)
+	}
+
+	links, err := testQueries.GetCampaignLinkStats(context.Background(), GetCampaignLinkStatsParams{CampaignID: campaign.ID})
+	require.NoError(t, err)
+	require.Len(t, links, 2)
+	require.Equal(t, link.ID, links[0].LinkID)
+	require.Equal(t, int64(3), links[0].Clicks)
+	require.Equal(t, int64(2), links[0].Referrers)
+	require.Equal(t, idle.ID, links[1].LinkID)
+	require.Zero(t, links[1].Clicks)
+
+	channels, err := testQueries.GetCampaignChannelStats(context.Background(), GetCampaignChannelStatsParams{CampaignID: campaign.ID})
+	require.NoError(t, err)
+	require.Equal(t, []GetCampaignChannelStatsRow{{Channel: "t.co", Clicks: 2}, {Channel: "direct", Clicks: 1}}, channels)
+
+	future, err := testQueries.GetCampaignChannelStats(context.Background(), GetCampaignChannelStatsParams{
+		CampaignID:   campaign.ID,
+		ClickedAfter: pgtype.Timestamptz{Time: time.Now().Add(time.Hour), Valid: true},
+	})
+	require.NoError(t, err)
+	require.Empty(t, future)
+
+	removed, err := testQueries.RemoveCampaignLinks(context.Background(), RemoveCampaignLinksParams{
+		CampaignID: campaign.ID,
+		LinkIds:    []int64{idle.ID},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), removed)
+}
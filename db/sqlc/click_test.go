@@ -0,0 +1,59 @@
+package db
+
+import (
+	"context"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestQueries_RecordClick(t *testing.T) {
+	link := createRandomDbLink(t)
+	require.Zero(t, link.ClickCount)
+
+	arg := RecordClickParams{
+		LinkID:    link.ID,
+		Referrer:  "https://example.com",
+		UserAgent: "Mozilla/5.0",
+	}
+
+	for i := 0; i < 3; i++ {
+		clicks, err := testQueries.RecordClick(context.Background(), arg)
+		require.NoError(t, err)
+		require.Equal(t, int64(i+1), clicks)
+	}
+
+	fetchedLink, err := testQueries.GetLinkById(context.Background(), link.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), fetchedLink.ClickCount)
+}
+
+func TestQueries_GetClickStatsByLinks(t *testing.T) {
+	link := createRandomDbLink(t)
+	quiet := createRandomDbLink(t)
+
+	referrers := []string{"https://a.example", "https://b.example", "https://a.example", ""}
+	for _, referrer := range referrers {
+		_, err := testQueries.RecordClick(context.Background(), RecordClickParams{LinkID: link.ID, Referrer: referrer})
+		require.NoError(t, err)
+	}
+
+	stats, err := testQueries.GetClickStatsByLinks(context.Background(), GetClickStatsByLinksParams{
+		LinkIds: []int64{link.ID, quiet.ID},
+	})
+	require.NoError(t, err)
+	require.Len(t, stats, 1)
+
+	require.Equal(t, link.ID, stats[0].LinkID)
+	require.Equal(t, int64(4), stats[0].Clicks)
+	require.Equal(t, int64(2), stats[0].Referrers)
+	require.False(t, stats[0].LastClickAt.Before(stats[0].FirstClickAt))
+
+	// No clicks fall inside a window that ended before they were recorded
+	stats, err = testQueries.GetClickStatsByLinks(context.Background(), GetClickStatsByLinksParams{
+		LinkIds:       []int64{link.ID},
+		ClickedBefore: pgtype.Timestamptz{Time: link.CreatedAt, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Empty(t, stats)
+}
@@ -0,0 +1,117 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+)
+
+func createRandomDbDomain(t *testing.T, userID int64) Domain {
+	arg := CreateDomainParams{
+		UserID:            userID,
+		Hostname:          strings.ToLower(fmt.Sprintf("go.%s.com", util.RandomString(10))),
+		VerificationToken: util.RandomString(32),
+	}
+
+	domain, err := testQueries.CreateDomain(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Hostname, domain.Hostname)
+	require.Equal(t, arg.VerificationToken, domain.VerificationToken)
+	require.False(t, domain.VerifiedAt.Valid)
+
+	return domain
+}
+
+func TestQueries_CustomDomains(t *testing.T) {
+	user := createRandomDbUser(t)
+	domain := createRandomDbDomain(t, user.ID)
+
+	_, err := testQueries.GetVerifiedDomainByHostname(context.Background(), domain.Hostname)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	verified, err := testQueries.MarkDomainVerified(context.Background(), domain.ID)
+	require.NoError(t, err)
+	require.True(t, verified.VerifiedAt.Valid)
+
+	found, err := testQueries.GetVerifiedDomainByHostname(context.Background(), domain.Hostname)
+	require.NoError(t, err)
+	require.Equal(t, domain.ID, found.ID)
+
+	// The same code can live once on the default domain and once per custom domain
+	code := util.RandomCode()
+	onDefault, err := testQueries.CreateLink(context.Background(), CreateLinkParams{
+		Code:   code,
+		Link:   util.RandomLink(),
+		UserID: user.ID,
+	})
+	require.NoError(t, err)
+
+	domainID := pgtype.Int8{Int64: domain.ID, Valid: true}
+	onDomain, err := testQueries.CreateLink(context.Background(), CreateLinkParams{
+		Code:     code,
+		Link:     util.RandomLink(),
+		UserID:   user.ID,
+		DomainID: domainID,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.CreateLink(context.Background(), CreateLinkParams{
+		Code:     code,
+		Link:     util.RandomLink(),
+		UserID:   user.ID,
+		DomainID: domainID,
+	})
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+
+	link, err := testQueries.GetLinkByCode(context.Background(), code)
+	require.NoError(t, err)
+	require.Equal(t, onDefault.ID, link.ID)
+
+	link, err = testQueries.GetLinkByDomainCode(context.Background(), GetLinkByDomainCodeParams{DomainID: domainID, Code: code})
+	require.NoError(t, err)
+	require.Equal(t, onDomain.ID, link.ID)
+
+	err = testQueries.DeleteDomain(context.Background(), domain.ID)
+	require.Equal(t, ForeignKeyViolation, ErrorCode(err))
+}
+
+func TestQueries_SetDomainFallbacks(t *testing.T) {
+	user := createRandomDbUser(t)
+	domain := createRandomDbDomain(t, user.ID)
+	require.Empty(t, domain.RootRedirectUrl)
+
+	arg := SetDomainFallbacksParams{
+		ID:              domain.ID,
+		RootRedirectUrl: util.RandomLink(),
+		NotFoundHtml:    "<h1>Gone</h1>",
+	}
+
+	updated, err := testQueries.SetDomainFallbacks(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.RootRedirectUrl, updated.RootRedirectUrl)
+	require.Empty(t, updated.NotFoundUrl)
+	require.Equal(t, arg.NotFoundHtml, updated.NotFoundHtml)
+}
+
+func TestQueries_ClaimedHostname(t *testing.T) {
+	owner := createRandomDbDomain(t, createRandomDbUser(t).ID)
+
+	// An unverified claim does not stop another account from claiming the same hostname
+	squatter, err := testQueries.CreateDomain(context.Background(), CreateDomainParams{
+		UserID:            createRandomDbUser(t).ID,
+		Hostname:          owner.Hostname,
+		VerificationToken: util.RandomString(32),
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.MarkDomainVerified(context.Background(), owner.ID)
+	require.NoError(t, err)
+
+	// but once one claim is verified, the others can no longer be
+	_, err = testQueries.MarkDomainVerified(context.Background(), squatter.ID)
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+}
@@ -0,0 +1,68 @@
+package db
+
+import (
+	"context"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func createRandomDbFolder(t *testing.T, userID int64) Folder {
+	arg := CreateFolderParams{
+		UserID: userID,
+		Name:   util.RandomString(8),
+	}
+
+	folder, err := testQueries.CreateFolder(context.Background(), arg)
+	require.NoError(t, err)
+
+	require.Equal(t, arg.UserID, folder.UserID)
+	require.Equal(t, arg.Name, folder.Name)
+	require.NotZero(t, folder.CreatedAt)
+
+	return folder
+}
+
+func TestQueries_SetLinkFolder(t *testing.T) {
+	link := createRandomDbLink(t)
+	folder := createRandomDbFolder(t, link.UserID)
+
+	updatedLink, err := testQueries.SetLinkFolder(context.Background(), SetLinkFolderParams{
+		ID:       link.ID,
+		FolderID: pgtype.Int8{Int64: folder.ID, Valid: true},
+	})
+	require.NoError(t, err)
+	require.Equal(t, folder.ID, updatedLink.FolderID.Int64)
+
+	links, err := testQueries.GetLinksByUser(context.Background(), GetLinksByUserParams{
+		UserID:   link.UserID,
+		FolderID: pgtype.Int8{Int64: folder.ID, Valid: true},
+		Limit:    10,
+	})
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+
+	// Deleting the folder leaves the link in place without one
+	err = testQueries.DeleteFolder(context.Background(), folder.ID)
+	require.NoError(t, err)
+
+	fetchedLink, err := testQueries.GetLinkById(context.Background(), link.ID)
+	require.NoError(t, err)
+	require.False(t, fetchedLink.FolderID.Valid)
+}
+
+func TestQueries_UpdateFolder(t *testing.T) {
+	folder := createRandomDbFolder(t, createRandomDbUser(t).ID)
+
+	updatedFolder, err := testQueries.UpdateFolder(context.Background(), UpdateFolderParams{
+		ID:   folder.ID,
+		Name: util.RandomString(8),
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, folder.Name, updatedFolder.Name)
+
+	folders, err := testQueries.GetFoldersByUser(context.Background(), folder.UserID)
+	require.NoError(t, err)
+	require.Equal(t, []Folder{updatedFolder}, folders)
+}
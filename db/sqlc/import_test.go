@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func createRandomImportJob(t *testing.T, userID int64) ImportJob {
+	arg := CreateImportJobParams{
+		UserID:  userID,
+		Source:  "bitly",
+		Format:  "csv",
+		Payload: []byte("bitlink,long_url\n"),
+	}
+
+	job, err := testQueries.CreateImportJob(context.Background(), arg)
+	require.NoError(t, err)
+	require.NotZero(t, job.ID)
+	require.Equal(t, arg.UserID, job.UserID)
+	require.Equal(t, arg.Payload, job.Payload)
+	require.Equal(t, "pending", job.Status)
+	require.Zero(t, job.Processed)
+
+	return job
+}
+
+func TestStore_ImportLinksTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomDbUser(t)
+	taken := createRandomDbLink(t)
+	job := createRandomImportJob(t, user.ID)
+
+	job, err := testQueries.StartImportJob(context.Background(), StartImportJobParams{ID: job.ID, Total: 3})
+	require.NoError(t, err)
+	require.Equal(t, "running", job.Status)
+
+	fresh := util.RandomCode()
+
+	job, err = store.ImportLinksTx(context.Background(), ImportLinksTxParams{
+		JobID:  job.ID,
+		UserID: user.ID,
+		Records: []ImportLinkRecord{
+			{Line: 1, Code: fresh, Link: util.RandomLink(), Clicks: 7},
+			{Line: 2, Code: taken.Code, Link: util.RandomLink()},
+			{Line: 3, Code: "abc", Link: "ftp://example.com", Conflict: "bad url", Skip: true},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), job.Processed)
+	require.Equal(t, int64(2), job.Imported)
+	require.Equal(t, int64(2), job.Conflicts)
+
+	link, err := testQueries.GetLinkByCode(context.Background(), fresh)
+	require.NoError(t, err)
+	require.Equal(t, int64(7), link.ClickCount)
+
+	conflicts, err := testQueries.GetImportConflicts(context.Background(), GetImportConflictsParams{JobID: job.ID, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, conflicts, 2)
+
+	require.Equal(t, int64(2), conflicts[0].Line)
+	require.Equal(t, ConflictCodeTaken, conflicts[0].Reason)
+	require.True(t, conflicts[0].LinkID.Valid)
+
+	require.Equal(t, int64(3), conflicts[1].Line)
+	require.False(t, conflicts[1].LinkID.Valid)
+
+	job, err = testQueries.FinishImportJob(context.Background(), FinishImportJobParams{ID: job.ID, Status: "completed"})
+	require.NoError(t, err)
+	require.Equal(t, "completed", job.Status)
+	require.False(t, job.LockedUntil.Valid)
+}
+
+func TestQueries_ClaimImportJob(t *testing.T) {
+	user := createRandomDbUser(t)
+	job := createRandomImportJob(t, user.ID)
+	id := pgtype.Int8{Int64: job.ID, Valid: true}
+
+	claimed, err := testQueries.ClaimImportJob(context.Background(), ClaimImportJobParams{LeaseSeconds: 60, ID: id})
+	require.NoError(t, err)
+	require.Equal(t, job.ID, claimed.ID)
+	require.True(t, claimed.LockedUntil.Valid)
+
+	// A job cannot be claimed again while its lease runs
+	_, err = testQueries.ClaimImportJob(context.Background(), ClaimImportJobParams{LeaseSeconds: 60, ID: id})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	// but is taken over once it runs out
+	_, err = testQueries.UpdateImportJobProgress(context.Background(), UpdateImportJobProgressParams{ID: job.ID, LeaseSeconds: -1})
+	require.NoError(t, err)
+
+	_, err = testQueries.ClaimImportJob(context.Background(), ClaimImportJobParams{LeaseSeconds: 60, ID: id})
+	require.NoError(t, err)
+}
+
+func TestStore_ImportLinksTxStaleBatch(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomDbUser(t)
+	job := createRandomImportJob(t, user.ID)
+
+	batch := func(code string) ImportLinksTxParams {
+		return ImportLinksTxParams{
+			JobID:   job.ID,
+			UserID:  user.ID,
+			Records: []ImportLinkRecord{{Line: 1, Code: code, Link: util.RandomLink()}},
+		}
+	}
+
+	// Another worker claimed the job after this one's lease ran out and imported the batch first
+	job, err := store.ImportLinksTx(context.Background(), batch(util.RandomCode()))
+	require.NoError(t, err)
+	require.Equal(t, int64(1), job.Processed)
+
+	stale := util.RandomCode()
+	_, err = store.ImportLinksTx(context.Background(), batch(stale))
+	require.ErrorIs(t, err, ErrStaleImportBatch)
+
+	// so the late batch leaves no links behind and the counters alone
+	_, err = testQueries.GetLinkByCode(context.Background(), stale)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	job, err = testQueries.GetImportJob(context.Background(), job.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), job.Processed)
+	require.Equal(t, int64(1), job.Imported)
+}
@@ -11,20 +11,227 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const countBrokenLinksByUser = `-- name: CountBrokenLinksByUser :one
+select count(*)
+from links
+where user_id = $1
+  and health_broken
+`
+
+func (q *Queries) CountBrokenLinksByUser(ctx context.Context, userID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, countBrokenLinksByUser, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countLinksByUser = `-- name: CountLinksByUser :one
+select count(*)
+from links
+where user_id = $1
+  and ($2::bigint is null or folder_id = $2)
+  and ($3::bigint is null or exists(select 1
+                                                  from link_tags
+                                                  where link_tags.link_id = links.id
+                                                    and link_tags.tag_id = $3))
+  and ($4::bool is null or active = $4)
+  and ($5::timestamptz is null or created_at >= $5)
+  and ($6::timestamptz is null or created_at < $6)
+  and ($7::varchar is null or link_host(link) = lower($7))
+  and ($8::varchar is null or
+       (code || ' ' || link || ' ' || title || ' ' || notes) ilike '%' || $8 || '%')
+  and ($9::varchar is null or utm_campaign = $9)
+`
+
+type CountLinksByUserParams struct {
+	UserID        int64              `json:"user_id"`
+	FolderID      pgtype.Int8        `json:"folder_id"`
+	TagID         pgtype.Int8        `json:"tag_id"`
+	Active        pgtype.Bool        `json:"active"`
+	CreatedAfter  pgtype.Timestamptz `json:"created_after"`
+	CreatedBefore pgtype.Timestamptz `json:"created_before"`
+	Domain        pgtype.Text        `json:"domain"`
+	Search        pgtype.Text        `json:"search"`
+	Campaign      pgtype.Text        `json:"campaign"`
+}
+
+func (q *Queries) CountLinksByUser(ctx context.Context, arg CountLinksByUserParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countLinksByUser,
+		arg.UserID,
+		arg.FolderID,
+		arg.TagID,
+		arg.Active,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.Domain,
+		arg.Search,
+		arg.Campaign,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createLink = `-- name: CreateLink :one
-INSERT INTO links (code, link, user_id)
-VALUES ($1, $2, $3)
-RETURNING id, user_id, code, link, created_at, active
+INSERT INTO links (code, link, user_id, title, notes, og_title, og_description, og_image, domain_id, utm_campaign, cloaked)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+RETURNING id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
 `
 
 type CreateLinkParams struct {
-	Code   string `json:"code"`
-	Link   string `json:"link"`
-	UserID int64  `json:"user_id"`
+	Code          string      `json:"code"`
+	Link          string      `json:"link"`
+	UserID        int64       `json:"user_id"`
+	Title         string      `json:"title"`
+	Notes         string      `json:"notes"`
+	OgTitle       string      `json:"og_title"`
+	OgDescription string      `json:"og_description"`
+	OgImage       string      `json:"og_image"`
+	DomainID      pgtype.Int8 `json:"domain_id"`
+	UtmCampaign   string      `json:"utm_campaign"`
+	Cloaked       bool        `json:"cloaked"`
 }
 
 func (q *Queries) CreateLink(ctx context.Context, arg CreateLinkParams) (Link, error) {
-	row := q.db.QueryRow(ctx, createLink, arg.Code, arg.Link, arg.UserID)
+	row := q.db.QueryRow(ctx, createLink,
+		arg.Code,
+		arg.Link,
+		arg.UserID,
+		arg.Title,
+		arg.Notes,
+		arg.OgTitle,
+		arg.OgDescription,
+		arg.OgImage,
+		arg.DomainID,
+		arg.UtmCampaign,
+		arg.Cloaked,
+	)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Code,
+		&i.Link,
+		&i.CreatedAt,
+		&i.Active,
+		&i.Title,
+		&i.Notes,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaFavicon,
+		&i.MetaFetchedAt,
+		&i.OgTitle,
+		&i.OgDescription,
+		&i.OgImage,
+		&i.FolderID,
+		&i.ClickCount,
+		&i.HealthStatus,
+		&i.HealthLatencyMs,
+		&i.HealthError,
+		&i.HealthBroken,
+		&i.HealthCheckedAt,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.ExpiryNotified,
+		&i.Interstitial,
+		&i.DomainID,
+		&i.UtmCampaign,
+		&i.HasPixels,
+		&i.Cloaked,
+	)
+	return i, err
+}
+
+const exportLinksByUser = `-- name: ExportLinksByUser :many
+select id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+from links
+where user_id = $1
+  and id > $2
+  and ($3::varchar is null or utm_campaign = $3)
+order by id
+limit $4
+`
+
+type ExportLinksByUserParams struct {
+	UserID   int64       `json:"user_id"`
+	AfterID  int64       `json:"after_id"`
+	Campaign pgtype.Text `json:"campaign"`
+	Limit    int32       `json:"limit"`
+}
+
+func (q *Queries) ExportLinksByUser(ctx context.Context, arg ExportLinksByUserParams) ([]Link, error) {
+	rows, err := q.db.Query(ctx, exportLinksByUser,
+		arg.UserID,
+		arg.AfterID,
+		arg.Campaign,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Link{}
+	for rows.Next() {
+		var i Link
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Code,
+			&i.Link,
+			&i.CreatedAt,
+			&i.Active,
+			&i.Title,
+			&i.Notes,
+			&i.MetaTitle,
+			&i.MetaDescription,
+			&i.MetaFavicon,
+			&i.MetaFetchedAt,
+			&i.OgTitle,
+			&i.OgDescription,
+			&i.OgImage,
+			&i.FolderID,
+			&i.ClickCount,
+			&i.HealthStatus,
+			&i.HealthLatencyMs,
+			&i.HealthError,
+			&i.HealthBroken,
+			&i.HealthCheckedAt,
+			&i.ExpiresAt,
+			&i.MaxClicks,
+			&i.ExpiryNotified,
+			&i.Interstitial,
+			&i.DomainID,
+			&i.UtmCampaign,
+			&i.HasPixels,
+			&i.Cloaked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getActiveLinkByDestination = `-- name: GetActiveLinkByDestination :one
+select id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+from links
+where user_id = $1
+  and normalize_link(link) = normalize_link($2)
+  and active
+order by id
+limit 1
+`
+
+type GetActiveLinkByDestinationParams struct {
+	UserID int64  `json:"user_id"`
+	Link   string `json:"link"`
+}
+
+func (q *Queries) GetActiveLinkByDestination(ctx context.Context, arg GetActiveLinkByDestinationParams) (Link, error) {
+	row := q.db.QueryRow(ctx, getActiveLinkByDestination, arg.UserID, arg.Link)
 	var i Link
 	err := row.Scan(
 		&i.ID,
@@ -33,14 +240,166 @@ func (q *Queries) CreateLink(ctx context.Context, arg CreateLinkParams) (Link, e
 		&i.Link,
 		&i.CreatedAt,
 		&i.Active,
+		&i.Title,
+		&i.Notes,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaFavicon,
+		&i.MetaFetchedAt,
+		&i.OgTitle,
+		&i.OgDescription,
+		&i.OgImage,
+		&i.FolderID,
+		&i.ClickCount,
+		&i.HealthStatus,
+		&i.HealthLatencyMs,
+		&i.HealthError,
+		&i.HealthBroken,
+		&i.HealthCheckedAt,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.ExpiryNotified,
+		&i.Interstitial,
+		&i.DomainID,
+		&i.UtmCampaign,
+		&i.HasPixels,
+		&i.Cloaked,
 	)
 	return i, err
 }
 
+const getBrokenLinksByUser = `-- name: GetBrokenLinksByUser :many
+select id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+from links
+where user_id = $1
+  and health_broken
+  and ($2::bigint is null or id < $2)
+order by id desc
+limit $3
+`
+
+type GetBrokenLinksByUserParams struct {
+	UserID   int64       `json:"user_id"`
+	CursorID pgtype.Int8 `json:"cursor_id"`
+	Limit    int32       `json:"limit"`
+}
+
+func (q *Queries) GetBrokenLinksByUser(ctx context.Context, arg GetBrokenLinksByUserParams) ([]Link, error) {
+	rows, err := q.db.Query(ctx, getBrokenLinksByUser, arg.UserID, arg.CursorID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Link{}
+	for rows.Next() {
+		var i Link
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Code,
+			&i.Link,
+			&i.CreatedAt,
+			&i.Active,
+			&i.Title,
+			&i.Notes,
+			&i.MetaTitle,
+			&i.MetaDescription,
+			&i.MetaFavicon,
+			&i.MetaFetchedAt,
+			&i.OgTitle,
+			&i.OgDescription,
+			&i.OgImage,
+			&i.FolderID,
+			&i.ClickCount,
+			&i.HealthStatus,
+			&i.HealthLatencyMs,
+			&i.HealthError,
+			&i.HealthBroken,
+			&i.HealthCheckedAt,
+			&i.ExpiresAt,
+			&i.MaxClicks,
+			&i.ExpiryNotified,
+			&i.Interstitial,
+			&i.DomainID,
+			&i.UtmCampaign,
+			&i.HasPixels,
+			&i.Cloaked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getExpiredLinksToNotify = `-- name: GetExpiredLinksToNotify :many
+select id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+from links
+where expires_at <= now()
+  and not expiry_notified
+order by expires_at
+limit $1
+`
+
+func (q *Queries) GetExpiredLinksToNotify(ctx context.Context, limit int32) ([]Link, error) {
+	rows, err := q.db.Query(ctx, getExpiredLinksToNotify, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Link{}
+	for rows.Next() {
+		var i Link
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Code,
+			&i.Link,
+			&i.CreatedAt,
+			&i.Active,
+			&i.Title,
+			&i.Notes,
+			&i.MetaTitle,
+			&i.MetaDescription,
+			&i.MetaFavicon,
+			&i.MetaFetchedAt,
+			&i.OgTitle,
+			&i.OgDescription,
+			&i.OgImage,
+			&i.FolderID,
+			&i.ClickCount,
+			&i.HealthStatus,
+			&i.HealthLatencyMs,
+			&i.HealthError,
+			&i.HealthBroken,
+			&i.HealthCheckedAt,
+			&i.ExpiresAt,
+			&i.MaxClicks,
+			&i.ExpiryNotified,
+			&i.Interstitial,
+			&i.DomainID,
+			&i.UtmCampaign,
+			&i.HasPixels,
+			&i.Cloaked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getLinkByCode = `-- name: GetLinkByCode :one
-select id, user_id, code, link, created_at, active
+select id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
 from links
 where code = $1
+  and domain_id is null
 limit 1
 `
 
@@ -54,12 +413,87 @@ func (q *Queries) GetLinkByCode(ctx context.Context, code string) (Link, error)
 		&i.Link,
 		&i.CreatedAt,
 		&i.Active,
+		&i.Title,
+		&i.Notes,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaFavicon,
+		&i.MetaFetchedAt,
+		&i.OgTitle,
+		&i.OgDescription,
+		&i.OgImage,
+		&i.FolderID,
+		&i.ClickCount,
+		&i.HealthStatus,
+		&i.HealthLatencyMs,
+		&i.HealthError,
+		&i.HealthBroken,
+		&i.HealthCheckedAt,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.ExpiryNotified,
+		&i.Interstitial,
+		&i.DomainID,
+		&i.UtmCampaign,
+		&i.HasPixels,
+		&i.Cloaked,
+	)
+	return i, err
+}
+
+const getLinkByDomainCode = `-- name: GetLinkByDomainCode :one
+select id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+from links
+where domain_id = $1
+  and code = $2
+limit 1
+`
+
+type GetLinkByDomainCodeParams struct {
+	DomainID pgtype.Int8 `json:"domain_id"`
+	Code     string      `json:"code"`
+}
+
+func (q *Queries) GetLinkByDomainCode(ctx context.Context, arg GetLinkByDomainCodeParams) (Link, error) {
+	row := q.db.QueryRow(ctx, getLinkByDomainCode, arg.DomainID, arg.Code)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Code,
+		&i.Link,
+		&i.CreatedAt,
+		&i.Active,
+		&i.Title,
+		&i.Notes,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaFavicon,
+		&i.MetaFetchedAt,
+		&i.OgTitle,
+		&i.OgDescription,
+		&i.OgImage,
+		&i.FolderID,
+		&i.ClickCount,
+		&i.HealthStatus,
+		&i.HealthLatencyMs,
+		&i.HealthError,
+		&i.HealthBroken,
+		&i.HealthCheckedAt,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.ExpiryNotified,
+		&i.Interstitial,
+		&i.DomainID,
+		&i.UtmCampaign,
+		&i.HasPixels,
+		&i.Cloaked,
 	)
 	return i, err
 }
 
 const getLinkById = `-- name: GetLinkById :one
-select id, user_id, code, link, created_at, active
+select id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
 from links
 where id = $1
 limit 1
@@ -75,12 +509,36 @@ func (q *Queries) GetLinkById(ctx context.Context, id int64) (Link, error) {
 		&i.Link,
 		&i.CreatedAt,
 		&i.Active,
+		&i.Title,
+		&i.Notes,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaFavicon,
+		&i.MetaFetchedAt,
+		&i.OgTitle,
+		&i.OgDescription,
+		&i.OgImage,
+		&i.FolderID,
+		&i.ClickCount,
+		&i.HealthStatus,
+		&i.HealthLatencyMs,
+		&i.HealthError,
+		&i.HealthBroken,
+		&i.HealthCheckedAt,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.ExpiryNotified,
+		&i.Interstitial,
+		&i.DomainID,
+		&i.UtmCampaign,
+		&i.HasPixels,
+		&i.Cloaked,
 	)
 	return i, err
 }
 
 const getLinks = `-- name: GetLinks :many
-SELECT id, user_id, code, link, created_at, active
+SELECT id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
 FROM links
 ORDER BY id
 LIMIT $1 OFFSET $2
@@ -107,6 +565,30 @@ func (q *Queries) GetLinks(ctx context.Context, arg GetLinksParams) ([]Link, err
 			&i.Link,
 			&i.CreatedAt,
 			&i.Active,
+			&i.Title,
+			&i.Notes,
+			&i.MetaTitle,
+			&i.MetaDescription,
+			&i.MetaFavicon,
+			&i.MetaFetchedAt,
+			&i.OgTitle,
+			&i.OgDescription,
+			&i.OgImage,
+			&i.FolderID,
+			&i.ClickCount,
+			&i.HealthStatus,
+			&i.HealthLatencyMs,
+			&i.HealthError,
+			&i.HealthBroken,
+			&i.HealthCheckedAt,
+			&i.ExpiresAt,
+			&i.MaxClicks,
+			&i.ExpiryNotified,
+			&i.Interstitial,
+			&i.DomainID,
+			&i.UtmCampaign,
+			&i.HasPixels,
+			&i.Cloaked,
 		); err != nil {
 			return nil, err
 		}
@@ -118,22 +600,58 @@ func (q *Queries) GetLinks(ctx context.Context, arg GetLinksParams) ([]Link, err
 	return items, nil
 }
 
-const getLinksByUser = `-- name: GetLinksByUser :many
-select id, user_id, code, link, created_at, active
+const getLinksByUserClicksAsc = `-- name: GetLinksByUserClicksAsc :many
+select id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
 from links
 where user_id = $1
-order by id desc
-limit $2 offset $3
+  and ($2::bigint is null or folder_id = $2)
+  and ($3::bigint is null or exists(select 1
+                                                  from link_tags
+                                                  where link_tags.link_id = links.id
+                                                    and link_tags.tag_id = $3))
+  and ($4::bool is null or active = $4)
+  and ($5::timestamptz is null or created_at >= $5)
+  and ($6::timestamptz is null or created_at < $6)
+  and ($7::varchar is null or link_host(link) = lower($7))
+  and ($8::varchar is null or
+       (code || ' ' || link || ' ' || title || ' ' || notes) ilike '%' || $8 || '%')
+  and ($9::varchar is null or utm_campaign = $9)
+  and ($10::bigint is null or
+       (click_count, id) > ($11::bigint, $10))
+order by click_count, id
+limit $12
 `
 
-type GetLinksByUserParams struct {
-	UserID int64 `json:"user_id"`
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+type GetLinksByUserClicksAscParams struct {
+	UserID        int64              `json:"user_id"`
+	FolderID      pgtype.Int8        `json:"folder_id"`
+	TagID         pgtype.Int8        `json:"tag_id"`
+	Active        pgtype.Bool        `json:"active"`
+	CreatedAfter  pgtype.Timestamptz `json:"created_after"`
+	CreatedBefore pgtype.Timestamptz `json:"created_before"`
+	Domain        pgtype.Text        `json:"domain"`
+	Search        pgtype.Text        `json:"search"`
+	Campaign      pgtype.Text        `json:"campaign"`
+	CursorID      pgtype.Int8        `json:"cursor_id"`
+	CursorClicks  pgtype.Int8        `json:"cursor_clicks"`
+	Limit         int32              `json:"limit"`
 }
 
-func (q *Queries) GetLinksByUser(ctx context.Context, arg GetLinksByUserParams) ([]Link, error) {
-	rows, err := q.db.Query(ctx, getLinksByUser, arg.UserID, arg.Limit, arg.Offset)
+func (q *Queries) GetLinksByUserClicksAsc(ctx context.Context, arg GetLinksByUserClicksAscParams) ([]Link, error) {
+	rows, err := q.db.Query(ctx, getLinksByUserClicksAsc,
+		arg.UserID,
+		arg.FolderID,
+		arg.TagID,
+		arg.Active,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.Domain,
+		arg.Search,
+		arg.Campaign,
+		arg.CursorID,
+		arg.CursorClicks,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -148,6 +666,30 @@ func (q *Queries) GetLinksByUser(ctx context.Context, arg GetLinksByUserParams)
 			&i.Link,
 			&i.CreatedAt,
 			&i.Active,
+			&i.Title,
+			&i.Notes,
+			&i.MetaTitle,
+			&i.MetaDescription,
+			&i.MetaFavicon,
+			&i.MetaFetchedAt,
+			&i.OgTitle,
+			&i.OgDescription,
+			&i.OgImage,
+			&i.FolderID,
+			&i.ClickCount,
+			&i.HealthStatus,
+			&i.HealthLatencyMs,
+			&i.HealthError,
+			&i.HealthBroken,
+			&i.HealthCheckedAt,
+			&i.ExpiresAt,
+			&i.MaxClicks,
+			&i.ExpiryNotified,
+			&i.Interstitial,
+			&i.DomainID,
+			&i.UtmCampaign,
+			&i.HasPixels,
+			&i.Cloaked,
 		); err != nil {
 			return nil, err
 		}
@@ -159,46 +701,1215 @@ func (q *Queries) GetLinksByUser(ctx context.Context, arg GetLinksByUserParams)
 	return items, nil
 }
 
-const toggleStatus = `-- name: ToggleStatus :one
-update links
-set active = $1
-where id = $2
-returning id, user_id, code, link, created_at, active
+const getLinksByUserClicksDesc = `-- name: GetLinksByUserClicksDesc :many
+select id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+from links
+where user_id = $1
+  and ($2::bigint is null or folder_id = $2)
+  and ($3::bigint is null or exists(select 1
+                                                  from link_tags
+                                                  where link_tags.link_id = links.id
+                                                    and link_tags.tag_id = $3))
+  and ($4::bool is null or active = $4)
+  and ($5::timestamptz is null or created_at >= $5)
+  and ($6::timestamptz is null or created_at < $6)
+  and ($7::varchar is null or link_host(link) = lower($7))
+  and ($8::varchar is null or
+       (code || ' ' || link || ' ' || title || ' ' || notes) ilike '%' || $8 || '%')
+  and ($9::varchar is null or utm_campaign = $9)
+  and ($10::bigint is null or
+       (click_count, id) < ($11::bigint, $10))
+order by click_count desc, id desc
+limit $12
 `
 
-type ToggleStatusParams struct {
-	Active pgtype.Bool `json:"active"`
-	ID     int64       `json:"id"`
+type GetLinksByUserClicksDescParams struct {
+	UserID        int64              `json:"user_id"`
+	FolderID      pgtype.Int8        `json:"folder_id"`
+	TagID         pgtype.Int8        `json:"tag_id"`
+	Active        pgtype.Bool        `json:"active"`
+	CreatedAfter  pgtype.Timestamptz `json:"created_after"`
+	CreatedBefore pgtype.Timestamptz `json:"created_before"`
+	Domain        pgtype.Text        `json:"domain"`
+	Search        pgtype.Text        `json:"search"`
+	Campaign      pgtype.Text        `json:"campaign"`
+	CursorID      pgtype.Int8        `json:"cursor_id"`
+	CursorClicks  pgtype.Int8        `json:"cursor_clicks"`
+	Limit         int32              `json:"limit"`
 }
 
-func (q *Queries) ToggleStatus(ctx context.Context, arg ToggleStatusParams) (Link, error) {
-	row := q.db.QueryRow(ctx, toggleStatus, arg.Active, arg.ID)
-	var i Link
-	err := row.Scan(
-		&i.ID,
-		&i.UserID,
-		&i.Code,
-		&i.Link,
-		&i.CreatedAt,
-		&i.Active,
+func (q *Queries) GetLinksByUserClicksDesc(ctx context.Context, arg GetLinksByUserClicksDescParams) ([]Link, error) {
+	rows, err := q.db.Query(ctx, getLinksByUserClicksDesc,
+		arg.UserID,
+		arg.FolderID,
+		arg.TagID,
+		arg.Active,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.Domain,
+		arg.Search,
+		arg.Campaign,
+		arg.CursorID,
+		arg.CursorClicks,
+		arg.Limit,
 	)
-	return i, err
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Link{}
+	for rows.Next() {
+		var i Link
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Code,
+			&i.Link,
+			&i.CreatedAt,
+			&i.Active,
+			&i.Title,
+			&i.Notes,
+			&i.MetaTitle,
+			&i.MetaDescription,
+			&i.MetaFavicon,
+			&i.MetaFetchedAt,
+			&i.OgTitle,
+			&i.OgDescription,
+			&i.OgImage,
+			&i.FolderID,
+			&i.ClickCount,
+			&i.HealthStatus,
+			&i.HealthLatencyMs,
+			&i.HealthError,
+			&i.HealthBroken,
+			&i.HealthCheckedAt,
+			&i.ExpiresAt,
+			&i.MaxClicks,
+			&i.ExpiryNotified,
+			&i.Interstitial,
+			&i.DomainID,
+			&i.UtmCampaign,
+			&i.HasPixels,
+			&i.Cloaked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-const updateCode = `-- name: UpdateCode :one
-update links
-set code = $1
-where id = $2
-returning id, user_id, code, link, created_at, active
+const getLinksByUserCodeAsc = `-- name: GetLinksByUserCodeAsc :many
+select id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+from links
+where user_id = $1
+  and ($2::bigint is null or folder_id = $2)
+  and ($3::bigint is null or exists(select 1
+                                                  from link_tags
+                                                  where link_tags.link_id = links.id
+                                                    and link_tags.tag_id = $3))
+  and ($4::bool is null or active = $4)
+  and ($5::timestamptz is null or created_at >= $5)
+  and ($6::timestamptz is null or created_at < $6)
+  and ($7::varchar is null or link_host(link) = lower($7))
+  and ($8::varchar is null or
+       (code || ' ' || link || ' ' || title || ' ' || notes) ilike '%' || $8 || '%')
+  and ($9::varchar is null or utm_campaign = $9)
+  and ($10::bigint is null or
+       (code, id) > ($11::varchar, $10))
+order by code, id
+limit $12
 `
 
-type UpdateCodeParams struct {
-	Code string `json:"code"`
-	ID   int64  `json:"id"`
+type GetLinksByUserCodeAscParams struct {
+	UserID        int64              `json:"user_id"`
+	FolderID      pgtype.Int8        `json:"folder_id"`
+	TagID         pgtype.Int8        `json:"tag_id"`
+	Active        pgtype.Bool        `json:"active"`
+	CreatedAfter  pgtype.Timestamptz `json:"created_after"`
+	CreatedBefore pgtype.Timestamptz `json:"created_before"`
+	Domain        pgtype.Text        `json:"domain"`
+	Search        pgtype.Text        `json:"search"`
+	Campaign      pgtype.Text        `json:"campaign"`
+	CursorID      pgtype.Int8        `json:"cursor_id"`
+	CursorCode    pgtype.Text        `json:"cursor_code"`
+	Limit         int32              `json:"limit"`
 }
 
-func (q *Queries) UpdateCode(ctx context.Context, arg UpdateCodeParams) (Link, error) {
-	row := q.db.QueryRow(ctx, updateCode, arg.Code, arg.ID)
+func (q *Queries) GetLinksByUserCodeAsc(ctx context.Context, arg GetLinksByUserCodeAscParams) ([]Link, error) {
+	rows, err := q.db.Query(ctx, getLinksByUserCodeAsc,
+		arg.UserID,
+		arg.FolderID,
+		arg.TagID,
+		arg.Active,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.Domain,
+		arg.Search,
+		arg.Campaign,
+		arg.CursorID,
+		arg.CursorCode,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Link{}
+	for rows.Next() {
+		var i Link
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Code,
+			&i.Link,
+			&i.CreatedAt,
+			&i.Active,
+			&i.Title,
+			&i.Notes,
+			&i.MetaTitle,
+			&i.MetaDescription,
+			&i.MetaFavicon,
+			&i.MetaFetchedAt,
+			&i.OgTitle,
+			&i.OgDescription,
+			&i.OgImage,
+			&i.FolderID,
+			&i.ClickCount,
+			&i.HealthStatus,
+			&i.HealthLatencyMs,
+			&i.HealthError,
+			&i.HealthBroken,
+			&i.HealthCheckedAt,
+			&i.ExpiresAt,
+			&i.MaxClicks,
+			&i.ExpiryNotified,
+			&i.Interstitial,
+			&i.DomainID,
+			&i.UtmCampaign,
+			&i.HasPixels,
+			&i.Cloaked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLinksByUserCodeDesc = `-- name: GetLinksByUserCodeDesc :many
+select id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+from links
+where user_id = $1
+  and ($2::bigint is null or folder_id = $2)
+  and ($3::bigint is null or exists(select 1
+                                                  from link_tags
+                                                  where link_tags.link_id = links.id
+                                                    and link_tags.tag_id = $3))
+  and ($4::bool is null or active = $4)
+  and ($5::timestamptz is null or created_at >= $5)
+  and ($6::timestamptz is null or created_at < $6)
+  and ($7::varchar is null or link_host(link) = lower($7))
+  and ($8::varchar is null or
+       (code || ' ' || link || ' ' || title || ' ' || notes) ilike '%' || $8 || '%')
+  and ($9::varchar is null or utm_campaign = $9)
+  and ($10::bigint is null or
+       (code, id) < ($11::varchar, $10))
+order by code desc, id desc
+limit $12
+`
+
+type GetLinksByUserCodeDescParams struct {
+	UserID        int64              `json:"user_id"`
+	FolderID      pgtype.Int8        `json:"folder_id"`
+	TagID         pgtype.Int8        `json:"tag_id"`
+	Active        pgtype.Bool        `json:"active"`
+	CreatedAfter  pgtype.Timestamptz `json:"created_after"`
+	CreatedBefore pgtype.Timestamptz `json:"created_before"`
+	Domain        pgtype.Text        `json:"domain"`
+	Search        pgtype.Text        `json:"search"`
+	Campaign      pgtype.Text        `json:"campaign"`
+	CursorID      pgtype.Int8        `json:"cursor_id"`
+	CursorCode    pgtype.Text        `json:"cursor_code"`
+	Limit         int32              `json:"limit"`
+}
+
+func (q *Queries) GetLinksByUserCodeDesc(ctx context.Context, arg GetLinksByUserCodeDescParams) ([]Link, error) {
+	rows, err := q.db.Query(ctx, getLinksByUserCodeDesc,
+		arg.UserID,
+		arg.FolderID,
+		arg.TagID,
+		arg.Active,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.Domain,
+		arg.Search,
+		arg.Campaign,
+		arg.CursorID,
+		arg.CursorCode,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Link{}
+	for rows.Next() {
+		var i Link
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Code,
+			&i.Link,
+			&i.CreatedAt,
+			&i.Active,
+			&i.Title,
+			&i.Notes,
+			&i.MetaTitle,
+			&i.MetaDescription,
+			&i.MetaFavicon,
+			&i.MetaFetchedAt,
+			&i.OgTitle,
+			&i.OgDescription,
+			&i.OgImage,
+			&i.FolderID,
+			&i.ClickCount,
+			&i.HealthStatus,
+			&i.HealthLatencyMs,
+			&i.HealthError,
+			&i.HealthBroken,
+			&i.HealthCheckedAt,
+			&i.ExpiresAt,
+			&i.MaxClicks,
+			&i.ExpiryNotified,
+			&i.Interstitial,
+			&i.DomainID,
+			&i.UtmCampaign,
+			&i.HasPixels,
+			&i.Cloaked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLinksByUserCreatedAsc = `-- name: GetLinksByUserCreatedAsc :many
+select id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+from links
+where user_id = $1
+  and ($2::bigint is null or folder_id = $2)
+  and ($3::bigint is null or exists(select 1
+                                                  from link_tags
+                                                  where link_tags.link_id = links.id
+                                                    and link_tags.tag_id = $3))
+  and ($4::bool is null or active = $4)
+  and ($5::timestamptz is null or created_at >= $5)
+  and ($6::timestamptz is null or created_at < $6)
+  and ($7::varchar is null or link_host(link) = lower($7))
+  and ($8::varchar is null or
+       (code || ' ' || link || ' ' || title || ' ' || notes) ilike '%' || $8 || '%')
+  and ($9::varchar is null or utm_campaign = $9)
+  and ($10::bigint is null or
+       (created_at, id) > ($11::timestamptz, $10))
+order by created_at, id
+limit $12
+`
+
+type GetLinksByUserCreatedAscParams struct {
+	UserID          int64              `json:"user_id"`
+	FolderID        pgtype.Int8        `json:"folder_id"`
+	TagID           pgtype.Int8        `json:"tag_id"`
+	Active          pgtype.Bool        `json:"active"`
+	CreatedAfter    pgtype.Timestamptz `json:"created_after"`
+	CreatedBefore   pgtype.Timestamptz `json:"created_before"`
+	Domain          pgtype.Text        `json:"domain"`
+	Search          pgtype.Text        `json:"search"`
+	Campaign        pgtype.Text        `json:"campaign"`
+	CursorID        pgtype.Int8        `json:"cursor_id"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	Limit           int32              `json:"limit"`
+}
+
+func (q *Queries) GetLinksByUserCreatedAsc(ctx context.Context, arg GetLinksByUserCreatedAscParams) ([]Link, error) {
+	rows, err := q.db.Query(ctx, getLinksByUserCreatedAsc,
+		arg.UserID,
+		arg.FolderID,
+		arg.TagID,
+		arg.Active,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.Domain,
+		arg.Search,
+		arg.Campaign,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Link{}
+	for rows.Next() {
+		var i Link
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Code,
+			&i.Link,
+			&i.CreatedAt,
+			&i.Active,
+			&i.Title,
+			&i.Notes,
+			&i.MetaTitle,
+			&i.MetaDescription,
+			&i.MetaFavicon,
+			&i.MetaFetchedAt,
+			&i.OgTitle,
+			&i.OgDescription,
+			&i.OgImage,
+			&i.FolderID,
+			&i.ClickCount,
+			&i.HealthStatus,
+			&i.HealthLatencyMs,
+			&i.HealthError,
+			&i.HealthBroken,
+			&i.HealthCheckedAt,
+			&i.ExpiresAt,
+			&i.MaxClicks,
+			&i.ExpiryNotified,
+			&i.Interstitial,
+			&i.DomainID,
+			&i.UtmCampaign,
+			&i.HasPixels,
+			&i.Cloaked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLinksByUserCreatedDesc = `-- name: GetLinksByUserCreatedDesc :many
+select id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+from links
+where user_id = $1
+  and ($2::bigint is null or folder_id = $2)
+  and ($3::bigint is null or exists(select 1
+                                                  from link_tags
+                                                  where link_tags.link_id = links.id
+                                                    and link_tags.tag_id = $3))
+  and ($4::bool is null or active = $4)
+  and ($5::timestamptz is null or created_at >= $5)
+  and ($6::timestamptz is null or created_at < $6)
+  and ($7::varchar is null or link_host(link) = lower($7))
+  and ($8::varchar is null or
+       (code || ' ' || link || ' ' || title || ' ' || notes) ilike '%' || $8 || '%')
+  and ($9::varchar is null or utm_campaign = $9)
+  and ($10::bigint is null or
+       (created_at, id) < ($11::timestamptz, $10))
+order by created_at desc, id desc
+limit $12
+`
+
+type GetLinksByUserCreatedDescParams struct {
+	UserID          int64              `json:"user_id"`
+	FolderID        pgtype.Int8        `json:"folder_id"`
+	TagID           pgtype.Int8        `json:"tag_id"`
+	Active          pgtype.Bool        `json:"active"`
+	CreatedAfter    pgtype.Timestamptz `json:"created_after"`
+	CreatedBefore   pgtype.Timestamptz `json:"created_before"`
+	Domain          pgtype.Text        `json:"domain"`
+	Search          pgtype.Text        `json:"search"`
+	Campaign        pgtype.Text        `json:"campaign"`
+	CursorID        pgtype.Int8        `json:"cursor_id"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	Limit           int32              `json:"limit"`
+}
+
+func (q *Queries) GetLinksByUserCreatedDesc(ctx context.Context, arg GetLinksByUserCreatedDescParams) ([]Link, error) {
+	rows, err := q.db.Query(ctx, getLinksByUserCreatedDesc,
+		arg.UserID,
+		arg.FolderID,
+		arg.TagID,
+		arg.Active,
+		arg.CreatedAfter,
+		arg.CreatedBefore,
+		arg.Domain,
+		arg.Search,
+		arg.Campaign,
+		arg.CursorID,
+		arg.CursorCreatedAt,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Link{}
+	for rows.Next() {
+		var i Link
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Code,
+			&i.Link,
+			&i.CreatedAt,
+			&i.Active,
+			&i.Title,
+			&i.Notes,
+			&i.MetaTitle,
+			&i.MetaDescription,
+			&i.MetaFavicon,
+			&i.MetaFetchedAt,
+			&i.OgTitle,
+			&i.OgDescription,
+			&i.OgImage,
+			&i.FolderID,
+			&i.ClickCount,
+			&i.HealthStatus,
+			&i.HealthLatencyMs,
+			&i.HealthError,
+			&i.HealthBroken,
+			&i.HealthCheckedAt,
+			&i.ExpiresAt,
+			&i.MaxClicks,
+			&i.ExpiryNotified,
+			&i.Interstitial,
+			&i.DomainID,
+			&i.UtmCampaign,
+			&i.HasPixels,
+			&i.Cloaked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLinksDueHealthCheck = `-- name: GetLinksDueHealthCheck :many
+select id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+from links
+where active
+  and (health_checked_at is null or health_checked_at < $1)
+order by health_checked_at nulls first, id
+limit $2
+`
+
+type GetLinksDueHealthCheckParams struct {
+	CheckedBefore pgtype.Timestamptz `json:"checked_before"`
+	Limit         int32              `json:"limit"`
+}
+
+func (q *Queries) GetLinksDueHealthCheck(ctx context.Context, arg GetLinksDueHealthCheckParams) ([]Link, error) {
+	rows, err := q.db.Query(ctx, getLinksDueHealthCheck, arg.CheckedBefore, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Link{}
+	for rows.Next() {
+		var i Link
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Code,
+			&i.Link,
+			&i.CreatedAt,
+			&i.Active,
+			&i.Title,
+			&i.Notes,
+			&i.MetaTitle,
+			&i.MetaDescription,
+			&i.MetaFavicon,
+			&i.MetaFetchedAt,
+			&i.OgTitle,
+			&i.OgDescription,
+			&i.OgImage,
+			&i.FolderID,
+			&i.ClickCount,
+			&i.HealthStatus,
+			&i.HealthLatencyMs,
+			&i.HealthError,
+			&i.HealthBroken,
+			&i.HealthCheckedAt,
+			&i.ExpiresAt,
+			&i.MaxClicks,
+			&i.ExpiryNotified,
+			&i.Interstitial,
+			&i.DomainID,
+			&i.UtmCampaign,
+			&i.HasPixels,
+			&i.Cloaked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getLinksPendingMetadata = `-- name: GetLinksPendingMetadata :many
+select id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+from links
+where meta_fetched_at is null
+order by id
+limit $1
+`
+
+func (q *Queries) GetLinksPendingMetadata(ctx context.Context, limit int32) ([]Link, error) {
+	rows, err := q.db.Query(ctx, getLinksPendingMetadata, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []Link{}
+	for rows.Next() {
+		var i Link
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Code,
+			&i.Link,
+			&i.CreatedAt,
+			&i.Active,
+			&i.Title,
+			&i.Notes,
+			&i.MetaTitle,
+			&i.MetaDescription,
+			&i.MetaFavicon,
+			&i.MetaFetchedAt,
+			&i.OgTitle,
+			&i.OgDescription,
+			&i.OgImage,
+			&i.FolderID,
+			&i.ClickCount,
+			&i.HealthStatus,
+			&i.HealthLatencyMs,
+			&i.HealthError,
+			&i.HealthBroken,
+			&i.HealthCheckedAt,
+			&i.ExpiresAt,
+			&i.MaxClicks,
+			&i.ExpiryNotified,
+			&i.Interstitial,
+			&i.DomainID,
+			&i.UtmCampaign,
+			&i.HasPixels,
+			&i.Cloaked,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const importLink = `-- name: ImportLink :one
+insert into links (code, link, user_id, title, created_at, click_count, utm_campaign)
+values ($1, $2, $3, $4,
+        coalesce($5, now()), $6, $7)
+on conflict (code) where domain_id is null do nothing
+returning id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+`
+
+type ImportLinkParams struct {
+	Code        string             `json:"code"`
+	Link        string             `json:"link"`
+	UserID      int64              `json:"user_id"`
+	Title       string             `json:"title"`
+	CreatedAt   pgtype.Timestamptz `json:"created_at"`
+	ClickCount  int64              `json:"click_count"`
+	UtmCampaign string             `json:"utm_campaign"`
+}
+
+func (q *Queries) ImportLink(ctx context.Context, arg ImportLinkParams) (Link, error) {
+	row := q.db.QueryRow(ctx, importLink,
+		arg.Code,
+		arg.Link,
+		arg.UserID,
+		arg.Title,
+		arg.CreatedAt,
+		arg.ClickCount,
+		arg.UtmCampaign,
+	)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Code,
+		&i.Link,
+		&i.CreatedAt,
+		&i.Active,
+		&i.Title,
+		&i.Notes,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaFavicon,
+		&i.MetaFetchedAt,
+		&i.OgTitle,
+		&i.OgDescription,
+		&i.OgImage,
+		&i.FolderID,
+		&i.ClickCount,
+		&i.HealthStatus,
+		&i.HealthLatencyMs,
+		&i.HealthError,
+		&i.HealthBroken,
+		&i.HealthCheckedAt,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.ExpiryNotified,
+		&i.Interstitial,
+		&i.DomainID,
+		&i.UtmCampaign,
+		&i.HasPixels,
+		&i.Cloaked,
+	)
+	return i, err
+}
+
+const markLinkExpiryNotified = `-- name: MarkLinkExpiryNotified :exec
+update links
+set expiry_notified = true
+where id = $1
+`
+
+func (q *Queries) MarkLinkExpiryNotified(ctx context.Context, id int64) error {
+	_, err := q.db.Exec(ctx, markLinkExpiryNotified, id)
+	return err
+}
+
+const setLinkCloaked = `-- name: SetLinkCloaked :one
+update links
+set cloaked = $1
+where id = $2
+returning id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+`
+
+type SetLinkCloakedParams struct {
+	Cloaked bool  `json:"cloaked"`
+	ID      int64 `json:"id"`
+}
+
+func (q *Queries) SetLinkCloaked(ctx context.Context, arg SetLinkCloakedParams) (Link, error) {
+	row := q.db.QueryRow(ctx, setLinkCloaked, arg.Cloaked, arg.ID)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Code,
+		&i.Link,
+		&i.CreatedAt,
+		&i.Active,
+		&i.Title,
+		&i.Notes,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaFavicon,
+		&i.MetaFetchedAt,
+		&i.OgTitle,
+		&i.OgDescription,
+		&i.OgImage,
+		&i.FolderID,
+		&i.ClickCount,
+		&i.HealthStatus,
+		&i.HealthLatencyMs,
+		&i.HealthError,
+		&i.HealthBroken,
+		&i.HealthCheckedAt,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.ExpiryNotified,
+		&i.Interstitial,
+		&i.DomainID,
+		&i.UtmCampaign,
+		&i.HasPixels,
+		&i.Cloaked,
+	)
+	return i, err
+}
+
+const setLinkFolder = `-- name: SetLinkFolder :one
+update links
+set folder_id = $1
+where id = $2
+returning id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+`
+
+type SetLinkFolderParams struct {
+	FolderID pgtype.Int8 `json:"folder_id"`
+	ID       int64       `json:"id"`
+}
+
+func (q *Queries) SetLinkFolder(ctx context.Context, arg SetLinkFolderParams) (Link, error) {
+	row := q.db.QueryRow(ctx, setLinkFolder, arg.FolderID, arg.ID)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Code,
+		&i.Link,
+		&i.CreatedAt,
+		&i.Active,
+		&i.Title,
+		&i.Notes,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaFavicon,
+		&i.MetaFetchedAt,
+		&i.OgTitle,
+		&i.OgDescription,
+		&i.OgImage,
+		&i.FolderID,
+		&i.ClickCount,
+		&i.HealthStatus,
+		&i.HealthLatencyMs,
+		&i.HealthError,
+		&i.HealthBroken,
+		&i.HealthCheckedAt,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.ExpiryNotified,
+		&i.Interstitial,
+		&i.DomainID,
+		&i.UtmCampaign,
+		&i.HasPixels,
+		&i.Cloaked,
+	)
+	return i, err
+}
+
+const setLinkHasPixels = `-- name: SetLinkHasPixels :one
+update links
+set has_pixels = $1
+where id = $2
+returning id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+`
+
+type SetLinkHasPixelsParams struct {
+	HasPixels bool  `json:"has_pixels"`
+	ID        int64 `json:"id"`
+}
+
+func (q *Queries) SetLinkHasPixels(ctx context.Context, arg SetLinkHasPixelsParams) (Link, error) {
+	row := q.db.QueryRow(ctx, setLinkHasPixels, arg.HasPixels, arg.ID)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Code,
+		&i.Link,
+		&i.CreatedAt,
+		&i.Active,
+		&i.Title,
+		&i.Notes,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaFavicon,
+		&i.MetaFetchedAt,
+		&i.OgTitle,
+		&i.OgDescription,
+		&i.OgImage,
+		&i.FolderID,
+		&i.ClickCount,
+		&i.HealthStatus,
+		&i.HealthLatencyMs,
+		&i.HealthError,
+		&i.HealthBroken,
+		&i.HealthCheckedAt,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.ExpiryNotified,
+		&i.Interstitial,
+		&i.DomainID,
+		&i.UtmCampaign,
+		&i.HasPixels,
+		&i.Cloaked,
+	)
+	return i, err
+}
+
+const setLinkInterstitial = `-- name: SetLinkInterstitial :one
+update links
+set interstitial = $1
+where id = $2
+returning id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+`
+
+type SetLinkInterstitialParams struct {
+	Interstitial bool  `json:"interstitial"`
+	ID           int64 `json:"id"`
+}
+
+func (q *Queries) SetLinkInterstitial(ctx context.Context, arg SetLinkInterstitialParams) (Link, error) {
+	row := q.db.QueryRow(ctx, setLinkInterstitial, arg.Interstitial, arg.ID)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Code,
+		&i.Link,
+		&i.CreatedAt,
+		&i.Active,
+		&i.Title,
+		&i.Notes,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaFavicon,
+		&i.MetaFetchedAt,
+		&i.OgTitle,
+		&i.OgDescription,
+		&i.OgImage,
+		&i.FolderID,
+		&i.ClickCount,
+		&i.HealthStatus,
+		&i.HealthLatencyMs,
+		&i.HealthError,
+		&i.HealthBroken,
+		&i.HealthCheckedAt,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.ExpiryNotified,
+		&i.Interstitial,
+		&i.DomainID,
+		&i.UtmCampaign,
+		&i.HasPixels,
+		&i.Cloaked,
+	)
+	return i, err
+}
+
+const setLinkLimits = `-- name: SetLinkLimits :one
+update links
+set expires_at      = $1,
+    max_clicks      = $2,
+    expiry_notified = false
+where id = $3
+returning id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+`
+
+type SetLinkLimitsParams struct {
+	ExpiresAt pgtype.Timestamptz `json:"expires_at"`
+	MaxClicks pgtype.Int8        `json:"max_clicks"`
+	ID        int64              `json:"id"`
+}
+
+func (q *Queries) SetLinkLimits(ctx context.Context, arg SetLinkLimitsParams) (Link, error) {
+	row := q.db.QueryRow(ctx, setLinkLimits, arg.ExpiresAt, arg.MaxClicks, arg.ID)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Code,
+		&i.Link,
+		&i.CreatedAt,
+		&i.Active,
+		&i.Title,
+		&i.Notes,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaFavicon,
+		&i.MetaFetchedAt,
+		&i.OgTitle,
+		&i.OgDescription,
+		&i.OgImage,
+		&i.FolderID,
+		&i.ClickCount,
+		&i.HealthStatus,
+		&i.HealthLatencyMs,
+		&i.HealthError,
+		&i.HealthBroken,
+		&i.HealthCheckedAt,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.ExpiryNotified,
+		&i.Interstitial,
+		&i.DomainID,
+		&i.UtmCampaign,
+		&i.HasPixels,
+		&i.Cloaked,
+	)
+	return i, err
+}
+
+const toggleStatus = `-- name: ToggleStatus :one
+update links
+set active = $1
+where id = $2
+returning id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+`
+
+type ToggleStatusParams struct {
+	Active pgtype.Bool `json:"active"`
+	ID     int64       `json:"id"`
+}
+
+func (q *Queries) ToggleStatus(ctx context.Context, arg ToggleStatusParams) (Link, error) {
+	row := q.db.QueryRow(ctx, toggleStatus, arg.Active, arg.ID)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Code,
+		&i.Link,
+		&i.CreatedAt,
+		&i.Active,
+		&i.Title,
+		&i.Notes,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaFavicon,
+		&i.MetaFetchedAt,
+		&i.OgTitle,
+		&i.OgDescription,
+		&i.OgImage,
+		&i.FolderID,
+		&i.ClickCount,
+		&i.HealthStatus,
+		&i.HealthLatencyMs,
+		&i.HealthError,
+		&i.HealthBroken,
+		&i.HealthCheckedAt,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.ExpiryNotified,
+		&i.Interstitial,
+		&i.DomainID,
+		&i.UtmCampaign,
+		&i.HasPixels,
+		&i.Cloaked,
+	)
+	return i, err
+}
+
+const updateCode = `-- name: UpdateCode :one
+update links
+set code = $1
+where id = $2
+returning id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+`
+
+type UpdateCodeParams struct {
+	Code string `json:"code"`
+	ID   int64  `json:"id"`
+}
+
+func (q *Queries) UpdateCode(ctx context.Context, arg UpdateCodeParams) (Link, error) {
+	row := q.db.QueryRow(ctx, updateCode, arg.Code, arg.ID)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Code,
+		&i.Link,
+		&i.CreatedAt,
+		&i.Active,
+		&i.Title,
+		&i.Notes,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaFavicon,
+		&i.MetaFetchedAt,
+		&i.OgTitle,
+		&i.OgDescription,
+		&i.OgImage,
+		&i.FolderID,
+		&i.ClickCount,
+		&i.HealthStatus,
+		&i.HealthLatencyMs,
+		&i.HealthError,
+		&i.HealthBroken,
+		&i.HealthCheckedAt,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.ExpiryNotified,
+		&i.Interstitial,
+		&i.DomainID,
+		&i.UtmCampaign,
+		&i.HasPixels,
+		&i.Cloaked,
+	)
+	return i, err
+}
+
+const updateLinkDetails = `-- name: UpdateLinkDetails :one
+update links
+set title          = coalesce($1, title),
+    notes          = coalesce($2, notes),
+    og_title       = coalesce($3, og_title),
+    og_description = coalesce($4, og_description),
+    og_image       = coalesce($5, og_image)
+where id = $6
+returning id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+`
+
+type UpdateLinkDetailsParams struct {
+	Title         pgtype.Text `json:"title"`
+	Notes         pgtype.Text `json:"notes"`
+	OgTitle       pgtype.Text `json:"og_title"`
+	OgDescription pgtype.Text `json:"og_description"`
+	OgImage       pgtype.Text `json:"og_image"`
+	ID            int64       `json:"id"`
+}
+
+func (q *Queries) UpdateLinkDetails(ctx context.Context, arg UpdateLinkDetailsParams) (Link, error) {
+	row := q.db.QueryRow(ctx, updateLinkDetails,
+		arg.Title,
+		arg.Notes,
+		arg.OgTitle,
+		arg.OgDescription,
+		arg.OgImage,
+		arg.ID,
+	)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Code,
+		&i.Link,
+		&i.CreatedAt,
+		&i.Active,
+		&i.Title,
+		&i.Notes,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaFavicon,
+		&i.MetaFetchedAt,
+		&i.OgTitle,
+		&i.OgDescription,
+		&i.OgImage,
+		&i.FolderID,
+		&i.ClickCount,
+		&i.HealthStatus,
+		&i.HealthLatencyMs,
+		&i.HealthError,
+		&i.HealthBroken,
+		&i.HealthCheckedAt,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.ExpiryNotified,
+		&i.Interstitial,
+		&i.DomainID,
+		&i.UtmCampaign,
+		&i.HasPixels,
+		&i.Cloaked,
+	)
+	return i, err
+}
+
+const updateLinkHealth = `-- name: UpdateLinkHealth :one
+update links
+set health_status     = $1,
+    health_latency_ms = $2,
+    health_error      = $3,
+    health_broken     = $4,
+    health_checked_at = now()
+where id = $5
+returning id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+`
+
+type UpdateLinkHealthParams struct {
+	HealthStatus    int32  `json:"health_status"`
+	HealthLatencyMs int32  `json:"health_latency_ms"`
+	HealthError     string `json:"health_error"`
+	HealthBroken    bool   `json:"health_broken"`
+	ID              int64  `json:"id"`
+}
+
+func (q *Queries) UpdateLinkHealth(ctx context.Context, arg UpdateLinkHealthParams) (Link, error) {
+	row := q.db.QueryRow(ctx, updateLinkHealth,
+		arg.HealthStatus,
+		arg.HealthLatencyMs,
+		arg.HealthError,
+		arg.HealthBroken,
+		arg.ID,
+	)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Code,
+		&i.Link,
+		&i.CreatedAt,
+		&i.Active,
+		&i.Title,
+		&i.Notes,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaFavicon,
+		&i.MetaFetchedAt,
+		&i.OgTitle,
+		&i.OgDescription,
+		&i.OgImage,
+		&i.FolderID,
+		&i.ClickCount,
+		&i.HealthStatus,
+		&i.HealthLatencyMs,
+		&i.HealthError,
+		&i.HealthBroken,
+		&i.HealthCheckedAt,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.ExpiryNotified,
+		&i.Interstitial,
+		&i.DomainID,
+		&i.UtmCampaign,
+		&i.HasPixels,
+		&i.Cloaked,
+	)
+	return i, err
+}
+
+const updateLinkMetadata = `-- name: UpdateLinkMetadata :one
+update links
+set meta_title       = $1,
+    meta_description = $2,
+    meta_favicon     = $3,
+    meta_fetched_at  = now()
+where id = $4
+returning id, user_id, code, link, created_at, active, title, notes, meta_title, meta_description, meta_favicon, meta_fetched_at, og_title, og_description, og_image, folder_id, click_count, health_status, health_latency_ms, health_error, health_broken, health_checked_at, expires_at, max_clicks, expiry_notified, interstitial, domain_id, utm_campaign, has_pixels, cloaked
+`
+
+type UpdateLinkMetadataParams struct {
+	MetaTitle       string `json:"meta_title"`
+	MetaDescription string `json:"meta_description"`
+	MetaFavicon     string `json:"meta_favicon"`
+	ID              int64  `json:"id"`
+}
+
+func (q *Queries) UpdateLinkMetadata(ctx context.Context, arg UpdateLinkMetadataParams) (Link, error) {
+	row := q.db.QueryRow(ctx, updateLinkMetadata,
+		arg.MetaTitle,
+		arg.MetaDescription,
+		arg.MetaFavicon,
+		arg.ID,
+	)
 	var i Link
 	err := row.Scan(
 		&i.ID,
@@ -207,6 +1918,30 @@ func (q *Queries) UpdateCode(ctx context.Context, arg UpdateCodeParams) (Link, e
 		&i.Link,
 		&i.CreatedAt,
 		&i.Active,
+		&i.Title,
+		&i.Notes,
+		&i.MetaTitle,
+		&i.MetaDescription,
+		&i.MetaFavicon,
+		&i.MetaFetchedAt,
+		&i.OgTitle,
+		&i.OgDescription,
+		&i.OgImage,
+		&i.FolderID,
+		&i.ClickCount,
+		&i.HealthStatus,
+		&i.HealthLatencyMs,
+		&i.HealthError,
+		&i.HealthBroken,
+		&i.HealthCheckedAt,
+		&i.ExpiresAt,
+		&i.MaxClicks,
+		&i.ExpiryNotified,
+		&i.Interstitial,
+		&i.DomainID,
+		&i.UtmCampaign,
+		&i.HasPixels,
+		&i.Cloaked,
 	)
 	return i, err
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/stretchr/testify/require"
 	"testing"
+	"time"
 )
 
 func createRandomDbLink(t *testing.T) Link {
@@ -204,8 +205,8 @@ func TestQueries_GetLinksByUser(t *testing.T) {
 
 				arg := GetLinksByUserParams{
 					UserID: user.ID,
+					Sort:   "-created",
 					Limit:  5,
-					Offset: 0,
 				}
 				links, err := testQueries.GetLinksByUser(context.Background(), arg)
 
@@ -221,7 +222,6 @@ func TestQueries_GetLinksByUser(t *testing.T) {
 				arg := GetLinksByUserParams{
 					UserID: -1,
 					Limit:  5,
-					Offset: 0,
 				}
 
 				links, _ := testQueries.GetLinksByUser(context.Background(), arg)
@@ -323,3 +323,394 @@ func TestQueries_UpdateLink(t *testing.T) {
 		})
 	}
 }
+
+func TestQueries_UpdateLinkDetails(t *testing.T) {
+	testCases := []struct {
+		name       string
+		buildStubs func(t *testing.T)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(t *testing.T) {
+				link := createRandomDbLink(t)
+
+				arg := UpdateLinkDetailsParams{
+					ID:    link.ID,
+					Title: pgtype.Text{String: util.RandomString(10), Valid: true},
+					Notes: pgtype.Text{String: util.RandomString(30), Valid: true},
+
+					OgTitle:       pgtype.Text{String: util.RandomString(10), Valid: true},
+					OgDescription: pgtype.Text{String: util.RandomString(30), Valid: true},
+					OgImage:       pgtype.Text{String: util.RandomLink() + "card.png", Valid: true},
+				}
+				updatedLink, err := testQueries.UpdateLinkDetails(context.Background(), arg)
+				require.NoError(t, err)
+
+				require.Equal(t, arg.Title.String, updatedLink.Title)
+				require.Equal(t, arg.Notes.String, updatedLink.Notes)
+				require.Equal(t, arg.OgTitle.String, updatedLink.OgTitle)
+				require.Equal(t, arg.OgDescription.String, updatedLink.OgDescription)
+				require.Equal(t, arg.OgImage.String, updatedLink.OgImage)
+				require.Equal(t, link.Code, updatedLink.Code)
+			},
+		},
+		{
+			name: "KeepsUnsetFields",
+			buildStubs: func(t *testing.T) {
+				link := createRandomDbLink(t)
+
+				arg := UpdateLinkDetailsParams{
+					ID:    link.ID,
+					Title: pgtype.Text{String: util.RandomString(10), Valid: true},
+				}
+				updatedLink, err := testQueries.UpdateLinkDetails(context.Background(), arg)
+				require.NoError(t, err)
+
+				require.Equal(t, arg.Title.String, updatedLink.Title)
+				require.Equal(t, link.Notes, updatedLink.Notes)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.buildStubs)
+	}
+}
+
+func TestQueries_LinkMetadata(t *testing.T) {
+	link := createRandomDbLink(t)
+	require.False(t, link.MetaFetchedAt.Valid)
+
+	arg := UpdateLinkMetadataParams{
+		ID:              link.ID,
+		MetaTitle:       util.RandomString(10),
+		MetaDescription: util.RandomString(30),
+		MetaFavicon:     util.RandomLink() + "favicon.ico",
+	}
+	updatedLink, err := testQueries.UpdateLinkMetadata(context.Background(), arg)
+	require.NoError(t, err)
+
+	require.Equal(t, arg.MetaTitle, updatedLink.MetaTitle)
+	require.Equal(t, arg.MetaDescription, updatedLink.MetaDescription)
+	require.Equal(t, arg.MetaFavicon, updatedLink.MetaFavicon)
+	require.True(t, updatedLink.MetaFetchedAt.Valid)
+
+	pending, err := testQueries.GetLinksPendingMetadata(context.Background(), 1000)
+	require.NoError(t, err)
+	for _, p := range pending {
+		require.NotEqual(t, link.ID, p.ID)
+	}
+}
+
+func TestQueries_SearchLinks(t *testing.T) {
+	user := createRandomDbUser(t)
+
+	createLink := func(link, title string) Link {
+		l, err := testQueries.CreateLink(context.Background(), CreateLinkParams{
+			Code:   util.RandomCode(),
+			Link:   link,
+			UserID: user.ID,
+			Title:  title,
+		})
+		require.NoError(t, err)
+		return l
+	}
+
+	spring := createLink("https://shop.example.com/sale?id=1", "Spring 50% sale")
+	docs := createLink("https://docs.example.org/start", "Getting started")
+	other := createLink("https://EXAMPLE.com/about", "About us")
+
+	testCases := []struct {
+		name  string
+		arg   GetLinksByUserParams
+		check func(t *testing.T, links []Link)
+	}{
+		{
+			name: "Search",
+			arg:  GetLinksByUserParams{Search: pgtype.Text{String: "spring", Valid: true}},
+			check: func(t *testing.T, links []Link) {
+				require.Len(t, links, 1)
+				require.Equal(t, spring.ID, links[0].ID)
+			},
+		},
+		{
+			name: "SearchEscapedWildcard",
+			arg:  GetLinksByUserParams{Search: pgtype.Text{String: `50\%`, Valid: true}},
+			check: func(t *testing.T, links []Link) {
+				require.Len(t, links, 1)
+				require.Equal(t, spring.ID, links[0].ID)
+			},
+		},
+		{
+			name: "Domain",
+			arg:  GetLinksByUserParams{Domain: pgtype.Text{String: "example.com", Valid: true}},
+			check: func(t *testing.T, links []Link) {
+				require.Len(t, links, 1)
+				require.Equal(t, other.ID, links[0].ID)
+			},
+		},
+		{
+			name: "SortByCode",
+			arg:  GetLinksByUserParams{Sort: "code"},
+			check: func(t *testing.T, links []Link) {
+				require.Len(t, links, 3)
+				require.True(t, links[0].Code <= links[1].Code)
+				require.True(t, links[1].Code <= links[2].Code)
+			},
+		},
+		{
+			name: "SortByCreatedAsc",
+			arg:  GetLinksByUserParams{Sort: "created"},
+			check: func(t *testing.T, links []Link) {
+				require.Len(t, links, 3)
+				require.Equal(t, spring.ID, links[0].ID)
+				require.Equal(t, docs.ID, links[1].ID)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tc.arg.UserID = user.ID
+			tc.arg.Limit = 10
+
+			links, err := testQueries.GetLinksByUser(context.Background(), tc.arg)
+			require.NoError(t, err)
+			tc.check(t, links)
+		})
+	}
+}
+
+func TestQueries_GetLinksByUserCursor(t *testing.T) {
+	user := createRandomDbUser(t)
+
+	n := 5
+	for i := 0; i < n; i++ {
+		_, err := testQueries.CreateLink(context.Background(), CreateLinkParams{
+			Code:   util.RandomCode(),
+			Link:   util.RandomLink(),
+			UserID: user.ID,
+		})
+		require.NoError(t, err)
+	}
+
+	all, err := testQueries.GetLinksByUser(context.Background(), GetLinksByUserParams{
+		UserID: user.ID,
+		Sort:   "-created",
+		Limit:  int32(n),
+	})
+	require.NoError(t, err)
+	require.Len(t, all, n)
+
+	arg := GetLinksByUserParams{
+		UserID:          user.ID,
+		Sort:            "-created",
+		CursorID:        pgtype.Int8{Int64: all[1].ID, Valid: true},
+		CursorCreatedAt: pgtype.Timestamptz{Time: all[1].CreatedAt, Valid: true},
+		Limit:           2,
+	}
+	page, err := testQueries.GetLinksByUser(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, all[2:4], page)
+
+	// The same cursor under the reversed ordering walks back to the start
+	arg.Sort = "created"
+	page, err = testQueries.GetLinksByUser(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, []Link{all[0]}, page)
+
+	total, err := testQueries.CountLinksByUser(context.Background(), CountLinksByUserParams{UserID: user.ID})
+	require.NoError(t, err)
+	require.Equal(t, int64(n), total)
+}
+
+func TestQueries_GetLinksByUserSorts(t *testing.T) {
+	user := createRandomDbUser(t)
+
+	n := 5
+	for i := 0; i < n; i++ {
+		_, err := testQueries.CreateLink(context.Background(), CreateLinkParams{
+			Code:   util.RandomCode(),
+			Link:   util.RandomLink(),
+			UserID: user.ID,
+		})
+		require.NoError(t, err)
+	}
+
+	for _, sort := range []string{"created", "-created", "clicks", "-clicks", "code", "-code"} {
+		t.Run(sort, func(t *testing.T) {
+			all, err := testQueries.GetLinksByUser(context.Background(), GetLinksByUserParams{
+				UserID: user.ID,
+				Sort:   sort,
+				Limit:  int32(n),
+			})
+			require.NoError(t, err)
+			require.Len(t, all, n)
+
+			page, err := testQueries.GetLinksByUser(context.Background(), GetLinksByUserParams{
+				UserID:          user.ID,
+				Sort:            sort,
+				CursorID:        pgtype.Int8{Int64: all[1].ID, Valid: true},
+				CursorCreatedAt: pgtype.Timestamptz{Time: all[1].CreatedAt, Valid: true},
+				CursorClicks:    pgtype.Int8{Int64: all[1].ClickCount, Valid: true},
+				CursorCode:      pgtype.Text{String: all[1].Code, Valid: true},
+				Limit:           2,
+			})
+			require.NoError(t, err)
+			require.Equal(t, all[2:4], page)
+		})
+	}
+
+	_, err := testQueries.GetLinksByUser(context.Background(), GetLinksByUserParams{
+		UserID: user.ID,
+		Sort:   "id",
+		Limit:  1,
+	})
+	require.Error(t, err)
+}
+
+func TestQueries_ExportLinksByUser(t *testing.T) {
+	user := createRandomDbUser(t)
+
+	n := 3
+	for i := 0; i < n; i++ {
+		_, err := testQueries.CreateLink(context.Background(), CreateLinkParams{
+			Code:   util.RandomCode(),
+			Link:   util.RandomLink(),
+			UserID: user.ID,
+		})
+		require.NoError(t, err)
+	}
+
+	first, err := testQueries.ExportLinksByUser(context.Background(), ExportLinksByUserParams{UserID: user.ID, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+	require.Less(t, first[0].ID, first[1].ID)
+
+	rest, err := testQueries.ExportLinksByUser(context.Background(), ExportLinksByUserParams{
+		UserID:  user.ID,
+		AfterID: first[1].ID,
+		Limit:   2,
+	})
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	require.Greater(t, rest[0].ID, first[1].ID)
+	require.Equal(t, user.ID, rest[0].UserID)
+}
+
+func TestQueries_GetActiveLinkByDestination(t *testing.T) {
+	user := createRandomDbUser(t)
+
+	link, err := testQueries.CreateLink(context.Background(), CreateLinkParams{
+		Code:   util.RandomCode(),
+		Link:   "https://Example.com:443/Some/Path/?q=1#top",
+		UserID: user.ID,
+	})
+	require.NoError(t, err)
+
+	for _, destination := range []string{
+		"https://example.com/Some/Path?q=1",
+		"HTTPS://EXAMPLE.COM/Some/Path/?q=1#elsewhere",
+	} {
+		found, err := testQueries.GetActiveLinkByDestination(context.Background(), GetActiveLinkByDestinationParams{
+			UserID: user.ID,
+			Link:   destination,
+		})
+		require.NoError(t, err)
+		require.Equal(t, link.ID, found.ID)
+	}
+
+	// The path and query keep their case
+	_, err = testQueries.GetActiveLinkByDestination(context.Background(), GetActiveLinkByDestinationParams{
+		UserID: user.ID,
+		Link:   "https://example.com/some/path?q=1",
+	})
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+
+	_, err = testQueries.ToggleStatus(context.Background(), ToggleStatusParams{ID: link.ID, Active: pgtype.Bool{Bool: false, Valid: true}})
+	require.NoError(t, err)
+
+	_, err = testQueries.GetActiveLinkByDestination(context.Background(), GetActiveLinkByDestinationParams{
+		UserID: user.ID,
+		Link:   link.Link,
+	})
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+}
+
+func TestQueries_LinkHealth(t *testing.T) {
+	link := createRandomDbLink(t)
+	require.False(t, link.HealthCheckedAt.Valid)
+
+	updated, err := testQueries.UpdateLinkHealth(context.Background(), UpdateLinkHealthParams{
+		ID:              link.ID,
+		HealthStatus:    404,
+		HealthLatencyMs: 120,
+		HealthBroken:    true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(404), updated.HealthStatus)
+	require.Equal(t, int32(120), updated.HealthLatencyMs)
+	require.True(t, updated.HealthBroken)
+	require.WithinDuration(t, time.Now(), updated.HealthCheckedAt.Time, time.Minute)
+
+	// A fresh check takes the link out of the queue
+	due, err := testQueries.GetLinksDueHealthCheck(context.Background(), GetLinksDueHealthCheckParams{
+		CheckedBefore: pgtype.Timestamptz{Time: time.Now().Add(-time.Hour), Valid: true},
+		Limit:         1000,
+	})
+	require.NoError(t, err)
+	require.NotContains(t, linkIDs(due), link.ID)
+
+	broken, err := testQueries.GetBrokenLinksByUser(context.Background(), GetBrokenLinksByUserParams{
+		UserID: link.UserID,
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, broken, 1)
+	require.Equal(t, link.ID, broken[0].ID)
+
+	total, err := testQueries.CountBrokenLinksByUser(context.Background(), link.UserID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+}
+
+func TestStore_UpdateLinkHealthTx(t *testing.T) {
+	store := NewStore(testDB)
+	link := createRandomDbLink(t)
+	hook := createRandomDbWebhook(t, link.UserID, "link.unhealthy")
+
+	arg := UpdateLinkHealthTxParams{
+		UpdateLinkHealthParams: UpdateLinkHealthParams{ID: link.ID, HealthStatus: 500, HealthBroken: true},
+		Deliveries: &CreateWebhookDeliveriesParams{
+			UserID:  link.UserID,
+			Event:   "link.unhealthy",
+			Payload: []byte("not json"),
+		},
+	}
+
+	// The update is not kept when the event cannot be queued
+	_, err := store.UpdateLinkHealthTx(context.Background(), arg)
+	require.Error(t, err)
+
+	unchanged, err := testQueries.GetLinkById(context.Background(), link.ID)
+	require.NoError(t, err)
+	require.False(t, unchanged.HealthBroken)
+
+	arg.Deliveries.Payload = []byte(`{"event":"link.unhealthy"}`)
+
+	updated, err := store.UpdateLinkHealthTx(context.Background(), arg)
+	require.NoError(t, err)
+	require.True(t, updated.HealthBroken)
+
+	total, err := testQueries.CountWebhookDeliveries(context.Background(), hook.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+}
+
+func linkIDs(links []Link) []int64 {
+	ids := make([]int64, len(links))
+	for i, link := range links {
+		ids[i] = link.ID
+	}
+	return ids
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+type GetLinksByUserParams struct {
+	UserID        int64              `json:"user_id"`
+	FolderID      pgtype.Int8        `json:"folder_id"`
+	TagID         pgtype.Int8        `json:"tag_id"`
+	Active        pgtype.Bool        `json:"active"`
+	CreatedAfter  pgtype.Timestamptz `json:"created_after"`
+	CreatedBefore pgtype.Timestamptz `json:"created_before"`
+	Domain        pgtype.Text        `json:"domain"`
+	Search        pgtype.Text        `json:"search"`
+	Campaign      pgtype.Text        `json:"campaign"`
+	// Sort is one of created, clicks or code, prefixed with - for descending order
+	Sort string `json:"sort"`
+	// CursorID, when set, starts the page after the link with this id and the cursor value of the sort key
+	CursorID        pgtype.Int8        `json:"cursor_id"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	CursorClicks    pgtype.Int8        `json:"cursor_clicks"`
+	CursorCode      pgtype.Text        `json:"cursor_code"`
+	Limit           int32              `json:"limit"`
+}
+
+// GetLinksByUser pages through a user's links in the given sort order. Each sort has its own query so the
+// keyset and order by stay plain enough for Postgres to walk the matching (user_id, key, id) index
+func (q *Queries) GetLinksByUser(ctx context.Context, arg GetLinksByUserParams) ([]Link, error) {
+	switch arg.Sort {
+	case "created":
+		return q.GetLinksByUserCreatedAsc(ctx, GetLinksByUserCreatedAscParams{
+			UserID: arg.UserID, FolderID: arg.FolderID, TagID: arg.TagID, Active: arg.Active,
+			CreatedAfter: arg.CreatedAfter, CreatedBefore: arg.CreatedBefore, Domain: arg.Domain,
+			Search: arg.Search, Campaign: arg.Campaign,
+			CursorID: arg.CursorID, CursorCreatedAt: arg.CursorCreatedAt, Limit: arg.Limit,
+		})
+	case "-created":
+		return q.GetLinksByUserCreatedDesc(ctx, GetLinksByUserCreatedDescParams{
+			UserID: arg.UserID, FolderID: arg.FolderID, TagID: arg.TagID, Active: arg.Active,
+			CreatedAfter: arg.CreatedAfter, CreatedBefore: arg.CreatedBefore, Domain: arg.Domain,
+			Search: arg.Search, Campaign: arg.Campaign,
+			CursorID: arg.CursorID, CursorCreatedAt: arg.CursorCreatedAt, Limit: arg.Limit,
+		})
+	case "clicks":
+		return q.GetLinksByUserClicksAsc(ctx, GetLinksByUserClicksAscParams{
+			UserID: arg.UserID, FolderID: arg.FolderID, TagID: arg.TagID, Active: arg.Active,
+			CreatedAfter: arg.CreatedAfter, CreatedBefore: arg.CreatedBefore, Domain: arg.Domain,
+			Search: arg.Search, Campaign: arg.Campaign,
+			CursorID: arg.CursorID, CursorClicks: arg.CursorClicks, Limit: arg.Limit,
+		})
+	case "-clicks":
+		return q.GetLinksByUserClicksDesc(ctx, GetLinksByUserClicksDescParams{
+			UserID: arg.UserID, FolderID: arg.FolderID, TagID: arg.TagID, Active: arg.Active,
+			CreatedAfter: arg.CreatedAfter, CreatedBefore: arg.CreatedBefore, Domain: arg.Domain,
+			Search: arg.Search, Campaign: arg.Campaign,
+			CursorID: arg.CursorID, CursorClicks: arg.CursorClicks, Limit: arg.Limit,
+		})
+	case "code":
+		return q.GetLinksByUserCodeAsc(ctx, GetLinksByUserCodeAscParams{
+			UserID: arg.UserID, FolderID: arg.FolderID, TagID: arg.TagID, Active: arg.Active,
+			CreatedAfter: arg.CreatedAfter, CreatedBefore: arg.CreatedBefore, Domain: arg.Domain,
+			Search: arg.Search, Campaign: arg.Campaign,
+			CursorID: arg.CursorID, CursorCode: arg.CursorCode, Limit: arg.Limit,
+		})
+	case "-code":
+		return q.GetLinksByUserCodeDesc(ctx, GetLinksByUserCodeDescParams{
+			UserID: arg.UserID, FolderID: arg.FolderID, TagID: arg.TagID, Active: arg.Active,
+			CreatedAfter: arg.CreatedAfter, CreatedBefore: arg.CreatedBefore, Domain: arg.Domain,
+			Search: arg.Search, Campaign: arg.Campaign,
+			CursorID: arg.CursorID, CursorCode: arg.CursorCode, Limit: arg.Limit,
+		})
+	}
+
+	return nil, fmt.Errorf("unknown link sort %q", arg.Sort)
+}
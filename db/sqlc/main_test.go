@@ -11,6 +11,7 @@ import (
 )
 
 var testQueries *Queries
+var testDB *pgxpool.Pool
 var tokenMaker token.Maker
 
 func TestMain(m *testing.M) {
@@ -20,7 +21,7 @@ func TestMain(m *testing.M) {
 		panic(err)
 	}
 
-	testDB, err := pgxpool.New(context.Background(), config.DBSource)
+	testDB, err = pgxpool.New(context.Background(), config.DBSource)
 
 	if err != nil {
 		panic(errors.Errorf("Error connecting to database: %v", err))
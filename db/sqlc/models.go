@@ -11,13 +11,161 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
-type Link struct {
+type BioPage struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"user_id"`
+	Slug        string    `json:"slug"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	AvatarUrl   string    `json:"avatar_url"`
+	Theme       string    `json:"theme"`
+	AccentColor string    `json:"accent_color"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type BioPageLink struct {
+	BioPageID int64  `json:"bio_page_id"`
+	LinkID    int64  `json:"link_id"`
+	Position  int32  `json:"position"`
+	Label     string `json:"label"`
+	IconUrl   string `json:"icon_url"`
+}
+
+type Campaign struct {
+	ID        int64              `json:"id"`
+	UserID    int64              `json:"user_id"`
+	Name      string             `json:"name"`
+	StartsAt  pgtype.Timestamptz `json:"starts_at"`
+	EndsAt    pgtype.Timestamptz `json:"ends_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type CampaignLink struct {
+	CampaignID int64     `json:"campaign_id"`
+	LinkID     int64     `json:"link_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+type CampaignTemplate struct {
+	ID          int64     `json:"id"`
+	UserID      int64     `json:"user_id"`
+	Name        string    `json:"name"`
+	UtmSource   string    `json:"utm_source"`
+	UtmMedium   string    `json:"utm_medium"`
+	UtmCampaign string    `json:"utm_campaign"`
+	UtmTerm     string    `json:"utm_term"`
+	UtmContent  string    `json:"utm_content"`
+	Params      []byte    `json:"params"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+type Click struct {
 	ID        int64       `json:"id"`
-	UserID    int64       `json:"user_id"`
-	Code      string      `json:"code"`
-	Link      string      `json:"link"`
+	LinkID    int64       `json:"link_id"`
+	Referrer  string      `json:"referrer"`
+	UserAgent string      `json:"user_agent"`
 	CreatedAt time.Time   `json:"created_at"`
-	Active    pgtype.Bool `json:"active"`
+	BioPageID pgtype.Int8 `json:"bio_page_id"`
+}
+
+type Domain struct {
+	ID                int64              `json:"id"`
+	UserID            int64              `json:"user_id"`
+	Hostname          string             `json:"hostname"`
+	VerificationToken string             `json:"verification_token"`
+	VerifiedAt        pgtype.Timestamptz `json:"verified_at"`
+	CreatedAt         time.Time          `json:"created_at"`
+	RootRedirectUrl   string             `json:"root_redirect_url"`
+	NotFoundUrl       string             `json:"not_found_url"`
+	NotFoundHtml      string             `json:"not_found_html"`
+}
+
+type Folder struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type ImportConflict struct {
+	ID           int64       `json:"id"`
+	JobID        int64       `json:"job_id"`
+	Line         int64       `json:"line"`
+	OriginalCode string      `json:"original_code"`
+	LinkID       pgtype.Int8 `json:"link_id"`
+	Reason       string      `json:"reason"`
+	CreatedAt    time.Time   `json:"created_at"`
+}
+
+type ImportJob struct {
+	ID          int64              `json:"id"`
+	UserID      int64              `json:"user_id"`
+	Source      string             `json:"source"`
+	Format      string             `json:"format"`
+	Payload     []byte             `json:"payload"`
+	Status      string             `json:"status"`
+	Total       int64              `json:"total"`
+	Processed   int64              `json:"processed"`
+	Imported    int64              `json:"imported"`
+	Conflicts   int64              `json:"conflicts"`
+	Error       string             `json:"error"`
+	CreatedAt   time.Time          `json:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at"`
+	LockedUntil pgtype.Timestamptz `json:"locked_until"`
+}
+
+type Link struct {
+	ID              int64              `json:"id"`
+	UserID          int64              `json:"user_id"`
+	Code            string             `json:"code"`
+	Link            string             `json:"link"`
+	CreatedAt       time.Time          `json:"created_at"`
+	Active          pgtype.Bool        `json:"active"`
+	Title           string             `json:"title"`
+	Notes           string             `json:"notes"`
+	MetaTitle       string             `json:"meta_title"`
+	MetaDescription string             `json:"meta_description"`
+	MetaFavicon     string             `json:"meta_favicon"`
+	MetaFetchedAt   pgtype.Timestamptz `json:"meta_fetched_at"`
+	OgTitle         string             `json:"og_title"`
+	OgDescription   string             `json:"og_description"`
+	OgImage         string             `json:"og_image"`
+	FolderID        pgtype.Int8        `json:"folder_id"`
+	ClickCount      int64              `json:"click_count"`
+	HealthStatus    int32              `json:"health_status"`
+	HealthLatencyMs int32              `json:"health_latency_ms"`
+	HealthError     string             `json:"health_error"`
+	HealthBroken    bool               `json:"health_broken"`
+	HealthCheckedAt pgtype.Timestamptz `json:"health_checked_at"`
+	ExpiresAt       pgtype.Timestamptz `json:"expires_at"`
+	MaxClicks       pgtype.Int8        `json:"max_clicks"`
+	ExpiryNotified  bool               `json:"expiry_notified"`
+	Interstitial    bool               `json:"interstitial"`
+	DomainID        pgtype.Int8        `json:"domain_id"`
+	UtmCampaign     string             `json:"utm_campaign"`
+	HasPixels       bool               `json:"has_pixels"`
+	Cloaked         bool               `json:"cloaked"`
+}
+
+type LinkTag struct {
+	LinkID    int64     `json:"link_id"`
+	TagID     int64     `json:"tag_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type LinkPixel struct {
+	LinkID  int64 `json:"link_id"`
+	PixelID int64 `json:"pixel_id"`
+}
+
+type Pixel struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Name      string    `json:"name"`
+	Provider  string    `json:"provider"`
+	AccountID string    `json:"account_id"`
+	Src       string    `json:"src"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 type Session struct {
@@ -31,13 +179,65 @@ type Session struct {
 	CreatedAt    time.Time `json:"created_at"`
 }
 
+type Tag struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
 type User struct {
-	ID                int64            `json:"id"`
-	Username          string           `json:"username"`
-	HashedPassword    string           `json:"hashed_password"`
-	FirstName         string           `json:"first_name"`
-	LastName          string           `json:"last_name"`
-	Email             string           `json:"email"`
-	PasswordChangedAt pgtype.Timestamp `json:"password_changed_at"`
-	CreatedAt         time.Time        `json:"created_at"`
+	ID                  int64              `json:"id"`
+	Username            string             `json:"username"`
+	HashedPassword      string             `json:"hashed_password"`
+	FirstName           string             `json:"first_name"`
+	LastName            string             `json:"last_name"`
+	Email               string             `json:"email"`
+	PasswordChangedAt   pgtype.Timestamp   `json:"password_changed_at"`
+	CreatedAt           time.Time          `json:"created_at"`
+	ReuseDuplicateLinks bool               `json:"reuse_duplicate_links"`
+	EmailVerifiedAt     pgtype.Timestamptz `json:"email_verified_at"`
+}
+
+type UserToken struct {
+	ID        int64              `json:"id"`
+	UserID    int64              `json:"user_id"`
+	Purpose   string             `json:"purpose"`
+	TokenHash string             `json:"token_hash"`
+	ExpiresAt time.Time          `json:"expires_at"`
+	UsedAt    pgtype.Timestamptz `json:"used_at"`
+	CreatedAt time.Time          `json:"created_at"`
+}
+
+type Webhook struct {
+	ID                  int64     `json:"id"`
+	UserID              int64     `json:"user_id"`
+	Url                 string    `json:"url"`
+	Secret              string    `json:"secret"`
+	Events              []string  `json:"events"`
+	Active              bool      `json:"active"`
+	CreatedAt           time.Time `json:"created_at"`
+	BatchSize           int32     `json:"batch_size"`
+	BatchMaxWaitSeconds int32     `json:"batch_max_wait_seconds"`
+}
+
+type WebhookClickOutbox struct {
+	ID        int64     `json:"id"`
+	WebhookID int64     `json:"webhook_id"`
+	Payload   []byte    `json:"payload"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type WebhookDelivery struct {
+	ID             int64              `json:"id"`
+	WebhookID      int64              `json:"webhook_id"`
+	Event          string             `json:"event"`
+	Payload        []byte             `json:"payload"`
+	Status         string             `json:"status"`
+	Attempts       int32              `json:"attempts"`
+	NextAttemptAt  time.Time          `json:"next_attempt_at"`
+	ResponseStatus int32              `json:"response_status"`
+	Error          string             `json:"error"`
+	DeliveredAt    pgtype.Timestamptz `json:"delivered_at"`
+	CreatedAt      time.Time          `json:"created_at"`
 }
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func createRandomDbPixel(t *testing.T, userID int64) Pixel {
+	arg := CreatePixelParams{
+		UserID:    userID,
+		Name:      "Retargeting",
+		Provider:  "meta",
+		AccountID: "1234567890",
+	}
+
+	pixel, err := testQueries.CreatePixel(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Provider, pixel.Provider)
+	require.Equal(t, arg.AccountID, pixel.AccountID)
+
+	return pixel
+}
+
+func TestStore_SetLinkPixelsTx(t *testing.T) {
+	store := NewStore(testDB)
+
+	link := createRandomDbLink(t)
+	require.False(t, link.HasPixels)
+
+	first := createRandomDbPixel(t, link.UserID)
+	second := createRandomDbPixel(t, link.UserID)
+
+	updated, err := store.SetLinkPixelsTx(context.Background(), SetLinkPixelsTxParams{
+		LinkID:   link.ID,
+		UserID:   link.UserID,
+		PixelIDs: []int64{first.ID, second.ID},
+	})
+	require.NoError(t, err)
+	require.True(t, updated.HasPixels)
+
+	pixels, err := testQueries.GetPixelsByLink(context.Background(), link.ID)
+	require.NoError(t, err)
+	require.Len(t, pixels, 2)
+
+	// Someone else's pixel rolls the whole replacement back
+	other := createRandomDbPixel(t, createRandomDbUser(t).ID)
+	_, err = store.SetLinkPixelsTx(context.Background(), SetLinkPixelsTxParams{
+		LinkID:   link.ID,
+		UserID:   link.UserID,
+		PixelIDs: []int64{other.ID},
+	})
+	require.ErrorIs(t, err, ErrLinkPixelNotOwned)
+
+	pixels, err = testQueries.GetPixelsByLink(context.Background(), link.ID)
+	require.NoError(t, err)
+	require.Len(t, pixels, 2)
+
+	updated, err = store.SetLinkPixelsTx(context.Background(), SetLinkPixelsTxParams{
+		LinkID: link.ID,
+		UserID: link.UserID,
+	})
+	require.NoError(t, err)
+	require.False(t, updated.HasPixels)
+
+	pixels, err = testQueries.GetPixelsByLink(context.Background(), link.ID)
+	require.NoError(t, err)
+	require.Empty(t, pixels)
+}
@@ -11,22 +11,129 @@ import (
 )
 
 type Querier interface {
+	AddBioPageLinks(ctx context.Context, arg AddBioPageLinksParams) (int64, error)
+	AddCampaignLinks(ctx context.Context, arg AddCampaignLinksParams) (int64, error)
+	AddLinkPixels(ctx context.Context, arg AddLinkPixelsParams) (int64, error)
+	AttachTag(ctx context.Context, arg AttachTagParams) (int64, error)
 	BlockSession(ctx context.Context, id uuid.UUID) (Session, error)
+	ClaimImportJob(ctx context.Context, arg ClaimImportJobParams) (ImportJob, error)
+	ClaimWebhookDeliveries(ctx context.Context, arg ClaimWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	CountBrokenLinksByUser(ctx context.Context, userID int64) (int64, error)
+	CountLinksByUser(ctx context.Context, arg CountLinksByUserParams) (int64, error)
+	CountRecentUserTokens(ctx context.Context, arg CountRecentUserTokensParams) (int64, error)
+	CountSessions(ctx context.Context, userID int64) (int64, error)
+	CountWebhookDeliveries(ctx context.Context, webhookID int64) (int64, error)
+	CreateBioPage(ctx context.Context, arg CreateBioPageParams) (BioPage, error)
+	CreateCampaign(ctx context.Context, arg CreateCampaignParams) (Campaign, error)
+	CreateCampaignTemplate(ctx context.Context, arg CreateCampaignTemplateParams) (CampaignTemplate, error)
+	CreateClickBatchDelivery(ctx context.Context, arg CreateClickBatchDeliveryParams) (WebhookDelivery, error)
+	CreateDomain(ctx context.Context, arg CreateDomainParams) (Domain, error)
+	CreateFolder(ctx context.Context, arg CreateFolderParams) (Folder, error)
+	CreateImportConflict(ctx context.Context, arg CreateImportConflictParams) (ImportConflict, error)
+	CreateImportJob(ctx context.Context, arg CreateImportJobParams) (ImportJob, error)
 	CreateLink(ctx context.Context, arg CreateLinkParams) (Link, error)
+	CreatePixel(ctx context.Context, arg CreatePixelParams) (Pixel, error)
 	CreateSession(ctx context.Context, arg CreateSessionParams) (Session, error)
+	CreateTag(ctx context.Context, arg CreateTagParams) (Tag, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserToken(ctx context.Context, arg CreateUserTokenParams) (UserToken, error)
+	CreateWebhook(ctx context.Context, arg CreateWebhookParams) (Webhook, error)
+	CreateWebhookDeliveries(ctx context.Context, arg CreateWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	DeleteBioPage(ctx context.Context, id int64) error
+	DeleteBioPageLinks(ctx context.Context, bioPageID int64) error
+	DeleteCampaign(ctx context.Context, id int64) error
+	DeleteCampaignTemplate(ctx context.Context, id int64) error
+	DeleteDomain(ctx context.Context, id int64) error
+	DeleteFolder(ctx context.Context, id int64) error
+	DeleteLinkPixels(ctx context.Context, linkID int64) error
+	DeletePixel(ctx context.Context, id int64) error
+	DeleteTag(ctx context.Context, id int64) error
+	DeleteWebhook(ctx context.Context, id int64) error
+	DetachTag(ctx context.Context, arg DetachTagParams) (int64, error)
+	ExportLinksByUser(ctx context.Context, arg ExportLinksByUserParams) ([]Link, error)
+	FinishImportJob(ctx context.Context, arg FinishImportJobParams) (ImportJob, error)
+	GetActiveLinkByDestination(ctx context.Context, arg GetActiveLinkByDestinationParams) (Link, error)
 	GetActiveSessions(ctx context.Context, arg GetActiveSessionsParams) ([]Session, error)
+	GetBioPage(ctx context.Context, id int64) (BioPage, error)
+	GetBioPageBySlug(ctx context.Context, slug string) (BioPage, error)
+	GetBioPageClickStats(ctx context.Context, arg GetBioPageClickStatsParams) ([]GetBioPageClickStatsRow, error)
+	GetBioPageLink(ctx context.Context, arg GetBioPageLinkParams) (Link, error)
+	GetBioPageLinks(ctx context.Context, bioPageID int64) ([]GetBioPageLinksRow, error)
+	GetBioPagesByUser(ctx context.Context, userID int64) ([]BioPage, error)
+	GetBrokenLinksByUser(ctx context.Context, arg GetBrokenLinksByUserParams) ([]Link, error)
+	GetCampaign(ctx context.Context, id int64) (Campaign, error)
+	GetCampaignChannelStats(ctx context.Context, arg GetCampaignChannelStatsParams) ([]GetCampaignChannelStatsRow, error)
+	GetCampaignLinkStats(ctx context.Context, arg GetCampaignLinkStatsParams) ([]GetCampaignLinkStatsRow, error)
+	GetCampaignTemplate(ctx context.Context, id int64) (CampaignTemplate, error)
+	GetCampaignTemplatesByUser(ctx context.Context, userID int64) ([]CampaignTemplate, error)
+	GetCampaignsByUser(ctx context.Context, userID int64) ([]Campaign, error)
+	GetClickStatsByLinks(ctx context.Context, arg GetClickStatsByLinksParams) ([]GetClickStatsByLinksRow, error)
+	GetDomain(ctx context.Context, id int64) (Domain, error)
+	GetDomainsByUser(ctx context.Context, userID int64) ([]Domain, error)
+	GetExpiredLinksToNotify(ctx context.Context, limit int32) ([]Link, error)
+	GetFolder(ctx context.Context, id int64) (Folder, error)
+	GetFoldersByUser(ctx context.Context, userID int64) ([]Folder, error)
+	GetImportConflicts(ctx context.Context, arg GetImportConflictsParams) ([]ImportConflict, error)
+	GetImportJob(ctx context.Context, id int64) (ImportJob, error)
 	GetLinkByCode(ctx context.Context, code string) (Link, error)
+	GetLinkByDomainCode(ctx context.Context, arg GetLinkByDomainCodeParams) (Link, error)
 	GetLinkById(ctx context.Context, id int64) (Link, error)
 	GetLinks(ctx context.Context, arg GetLinksParams) ([]Link, error)
-	GetLinksByUser(ctx context.Context, arg GetLinksByUserParams) ([]Link, error)
+	GetLinksByUserClicksAsc(ctx context.Context, arg GetLinksByUserClicksAscParams) ([]Link, error)
+	GetLinksByUserClicksDesc(ctx context.Context, arg GetLinksByUserClicksDescParams) ([]Link, error)
+	GetLinksByUserCodeAsc(ctx context.Context, arg GetLinksByUserCodeAscParams) ([]Link, error)
+	GetLinksByUserCodeDesc(ctx context.Context, arg GetLinksByUserCodeDescParams) ([]Link, error)
+	GetLinksByUserCreatedAsc(ctx context.Context, arg GetLinksByUserCreatedAscParams) ([]Link, error)
+	GetLinksByUserCreatedDesc(ctx context.Context, arg GetLinksByUserCreatedDescParams) ([]Link, error)
+	GetLinksDueHealthCheck(ctx context.Context, arg GetLinksDueHealthCheckParams) ([]Link, error)
+	GetLinksPendingMetadata(ctx context.Context, limit int32) ([]Link, error)
+	GetPixel(ctx context.Context, id int64) (Pixel, error)
+	GetPixelsByLink(ctx context.Context, linkID int64) ([]Pixel, error)
+	GetPixelsByUser(ctx context.Context, userID int64) ([]Pixel, error)
 	GetSession(ctx context.Context, id uuid.UUID) (Session, error)
 	GetSessions(ctx context.Context, arg GetSessionsParams) ([]Session, error)
+	GetTag(ctx context.Context, id int64) (Tag, error)
+	GetTagsByLink(ctx context.Context, linkID int64) ([]Tag, error)
+	GetTagsByUser(ctx context.Context, userID int64) ([]Tag, error)
+	GetUnblockedSessionIDs(ctx context.Context, userID int64) ([]uuid.UUID, error)
 	GetUser(ctx context.Context, usernameoremail string) (User, error)
 	GetUserById(ctx context.Context, id int64) (User, error)
+	GetVerifiedDomainByHostname(ctx context.Context, hostname string) (Domain, error)
+	GetWebhook(ctx context.Context, id int64) (Webhook, error)
+	GetWebhookDeliveries(ctx context.Context, arg GetWebhookDeliveriesParams) ([]WebhookDelivery, error)
+	GetWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	GetWebhooksByUser(ctx context.Context, userID int64) ([]Webhook, error)
+	GetWebhooksDueClickBatch(ctx context.Context, limit int32) ([]Webhook, error)
+	ImportLink(ctx context.Context, arg ImportLinkParams) (Link, error)
+	MarkDomainVerified(ctx context.Context, id int64) (Domain, error)
+	MarkLinkExpiryNotified(ctx context.Context, id int64) error
+	RecordClick(ctx context.Context, arg RecordClickParams) (int64, error)
+	RecordWebhookAttempt(ctx context.Context, arg RecordWebhookAttemptParams) (WebhookDelivery, error)
+	RedeliverWebhookDelivery(ctx context.Context, id int64) (WebhookDelivery, error)
+	RemoveCampaignLinks(ctx context.Context, arg RemoveCampaignLinksParams) (int64, error)
+	RevokeUserTokens(ctx context.Context, arg RevokeUserTokensParams) error
+	SetDomainFallbacks(ctx context.Context, arg SetDomainFallbacksParams) (Domain, error)
+	SetLinkCloaked(ctx context.Context, arg SetLinkCloakedParams) (Link, error)
+	SetLinkFolder(ctx context.Context, arg SetLinkFolderParams) (Link, error)
+	SetLinkHasPixels(ctx context.Context, arg SetLinkHasPixelsParams) (Link, error)
+	SetLinkInterstitial(ctx context.Context, arg SetLinkInterstitialParams) (Link, error)
+	SetLinkLimits(ctx context.Context, arg SetLinkLimitsParams) (Link, error)
+	StartImportJob(ctx context.Context, arg StartImportJobParams) (ImportJob, error)
 	ToggleStatus(ctx context.Context, arg ToggleStatusParams) (Link, error)
+	UpdateBioPage(ctx context.Context, arg UpdateBioPageParams) (BioPage, error)
 	UpdateCode(ctx context.Context, arg UpdateCodeParams) (Link, error)
+	UpdateFolder(ctx context.Context, arg UpdateFolderParams) (Folder, error)
+	UpdateImportJobProgress(ctx context.Context, arg UpdateImportJobProgressParams) (ImportJob, error)
+	UpdateLinkDetails(ctx context.Context, arg UpdateLinkDetailsParams) (Link, error)
+	UpdateLinkHealth(ctx context.Context, arg UpdateLinkHealthParams) (Link, error)
+	UpdateLinkMetadata(ctx context.Context, arg UpdateLinkMetadataParams) (Link, error)
+	UpdateTag(ctx context.Context, arg UpdateTagParams) (Tag, error)
 	UpdateUser(ctx context.Context, arg UpdateUserParams) (User, error)
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error)
+	UpdateUserPreferences(ctx context.Context, arg UpdateUserPreferencesParams) (User, error)
+	UpsertTag(ctx context.Context, arg UpsertTagParams) (Tag, error)
+	UseUserToken(ctx context.Context, arg UseUserTokenParams) (UserToken, error)
+	VerifyUserEmail(ctx context.Context, id int64) (User, error)
 }
 
 var _ Querier = (*Queries)(nil)
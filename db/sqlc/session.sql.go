@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const blockSession = `-- name: BlockSession :one
@@ -35,6 +36,19 @@ func (q *Queries) BlockSession(ctx context.Context, id uuid.UUID) (Session, erro
 	return i, err
 }
 
+const countSessions = `-- name: CountSessions :one
+SELECT count(*)
+FROM sessions
+WHERE user_id = $1
+`
+
+func (q *Queries) CountSessions(ctx context.Context, userID int64) (int64, error) {
+	row := q.db.QueryRow(ctx, countSessions, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createSession = `-- name: CreateSession :one
 INSERT INTO sessions (id, user_id, refresh_token, client_ip, user_agent, is_blocked, expires_at)
 VALUES ($1, $2, $3, $4, $5, $6, $7)
@@ -145,18 +159,33 @@ const getSessions = `-- name: GetSessions :many
 SELECT id, user_id, refresh_token, client_ip, user_agent, is_blocked, expires_at, created_at
 FROM sessions
 WHERE user_id = $1
-ORDER BY created_at DESC
-LIMIT $2 OFFSET $3
+  AND ($2::uuid IS NULL OR CASE
+        WHEN $3::bool
+            THEN (created_at, id) > ($4::timestamptz, $2)
+        ELSE (created_at, id) < ($4, $2) END)
+ORDER BY CASE WHEN $3 THEN created_at END,
+         CASE WHEN $3 THEN id END,
+         created_at DESC,
+         id DESC
+LIMIT $5
 `
 
 type GetSessionsParams struct {
-	UserID int64 `json:"user_id"`
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	UserID          int64              `json:"user_id"`
+	CursorID        pgtype.UUID        `json:"cursor_id"`
+	Backward        bool               `json:"backward"`
+	CursorCreatedAt pgtype.Timestamptz `json:"cursor_created_at"`
+	Limit           int32              `json:"limit"`
 }
 
 func (q *Queries) GetSessions(ctx context.Context, arg GetSessionsParams) ([]Session, error) {
-	rows, err := q.db.Query(ctx, getSessions, arg.UserID, arg.Limit, arg.Offset)
+	rows, err := q.db.Query(ctx, getSessions,
+		arg.UserID,
+		arg.CursorID,
+		arg.Backward,
+		arg.CursorCreatedAt,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -183,3 +212,29 @@ func (q *Queries) GetSessions(ctx context.Context, arg GetSessionsParams) ([]Ses
 	}
 	return items, nil
 }
+
+const getUnblockedSessionIDs = `-- name: GetUnblockedSessionIDs :many
+SELECT id
+FROM sessions
+WHERE user_id = $1 AND is_blocked = false AND expires_at > NOW()
+`
+
+func (q *Queries) GetUnblockedSessionIDs(ctx context.Context, userID int64) ([]uuid.UUID, error) {
+	rows, err := q.db.Query(ctx, getUnblockedSessionIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []uuid.UUID{}
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -11,7 +11,11 @@ import (
 
 func createRandomDbSession(t *testing.T) Session {
 	user := createRandomDbUser(t)
-	token, tokenPayload, err := tokenMaker.CreateToken(user.ID, time.Minute)
+	return createDbSessionForUser(t, user.ID)
+}
+
+func createDbSessionForUser(t *testing.T, userID int64) Session {
+	token, tokenPayload, err := tokenMaker.CreateToken(userID, time.Minute)
 	require.NoError(t, err)
 
 	arg := CreateSessionParams{
@@ -181,7 +185,6 @@ func TestQueries_GetSessions(t *testing.T) {
 				args := GetSessionsParams{
 					UserID: user.ID,
 					Limit:  int32(n),
-					Offset: 0,
 				}
 				fetchedSessions, err := testQueries.GetSessions(context.Background(), args)
 				require.NoError(t, err)
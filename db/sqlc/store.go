@@ -1,17 +1,53 @@
 package db
 
-import "github.com/jackc/pgx/v5/pgxpool"
+import (
+	"context"
+	"fmt"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
 
 type Store interface {
 	Querier
+	ChangePasswordTx(ctx context.Context, arg ChangePasswordTxParams) (User, error)
+	CreateLinkTx(ctx context.Context, arg CreateLinkTxParams) (CreateLinkTxResult, error)
+	CreateLinksTx(ctx context.Context, arg []CreateLinkTxParams) ([]CreateLinkTxResult, error)
+	GetLinksByUser(ctx context.Context, arg GetLinksByUserParams) ([]Link, error)
+	ImportLinksTx(ctx context.Context, arg ImportLinksTxParams) (ImportJob, error)
+	NotifyLinkExpiredTx(ctx context.Context, arg NotifyLinkExpiredTxParams) error
+	ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (User, error)
+	SetBioPageLinksTx(ctx context.Context, arg SetBioPageLinksTxParams) error
+	SetLinkPixelsTx(ctx context.Context, arg SetLinkPixelsTxParams) (Link, error)
+	UpdateLinkHealthTx(ctx context.Context, arg UpdateLinkHealthTxParams) (Link, error)
+	VerifyEmailTx(ctx context.Context, tokenHash string) (User, error)
 }
 
 type SQLStore struct {
+	connPool *pgxpool.Pool
 	*Queries
 }
 
-func NewStore(db *pgxpool.Pool) Store {
+func NewStore(connPool *pgxpool.Pool) Store {
 	return &SQLStore{
-		Queries: New(db),
+		connPool: connPool,
+		Queries:  New(connPool),
 	}
 }
+
+// execTx runs fn inside a database transaction, rolling back when it returns an error
+func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) error {
+	tx, err := store.connPool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	q := New(tx)
+	err = fn(q)
+	if err != nil {
+		if rbErr := tx.Rollback(ctx); rbErr != nil {
+			return fmt.Errorf("tx err: %w, rb err: %v", err, rbErr)
+		}
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
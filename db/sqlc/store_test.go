@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestStore_CreateLinksTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomDbUser(t)
+	tagName := util.RandomString(8)
+
+	newItem := func(code string) CreateLinkTxParams {
+		return CreateLinkTxParams{
+			CreateLinkParams: CreateLinkParams{
+				Code:   code,
+				Link:   util.RandomLink(),
+				UserID: user.ID,
+			},
+			Tags: []string{tagName},
+		}
+	}
+
+	results, err := store.CreateLinksTx(context.Background(), []CreateLinkTxParams{
+		newItem(util.RandomCode()),
+		newItem(util.RandomCode()),
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	// Both links share the one tag created for the first
+	require.Equal(t, results[0].Tags, results[1].Tags)
+	tags, err := testQueries.GetTagsByLink(context.Background(), results[1].Link.ID)
+	require.NoError(t, err)
+	require.Equal(t, results[1].Tags, tags)
+
+	// A taken code rolls back the whole batch
+	freshCode := util.RandomCode()
+	_, err = store.CreateLinksTx(context.Background(), []CreateLinkTxParams{
+		newItem(freshCode),
+		newItem(results[0].Link.Code),
+	})
+
+	var txErr *LinkTxError
+	require.True(t, errors.As(err, &txErr))
+	require.Equal(t, 1, txErr.Index)
+	require.Equal(t, UniqueViolation, ErrorCode(err))
+
+	_, err = testQueries.GetLinkByCode(context.Background(), freshCode)
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+}
@@ -0,0 +1,125 @@
+package db
+
+import (
+	"context"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func createRandomDbTag(t *testing.T, userID int64) Tag {
+	arg := CreateTagParams{
+		UserID: userID,
+		Name:   util.RandomString(8),
+	}
+
+	tag, err := testQueries.CreateTag(context.Background(), arg)
+	require.NoError(t, err)
+
+	require.Equal(t, arg.UserID, tag.UserID)
+	require.Equal(t, arg.Name, tag.Name)
+	require.NotZero(t, tag.CreatedAt)
+
+	return tag
+}
+
+func TestQueries_CreateTag(t *testing.T) {
+	testCases := []struct {
+		name       string
+		buildStubs func(t *testing.T)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(t *testing.T) {
+				createRandomDbTag(t, createRandomDbUser(t).ID)
+			},
+		},
+		{
+			name: "NameExists",
+			buildStubs: func(t *testing.T) {
+				tag := createRandomDbTag(t, createRandomDbUser(t).ID)
+
+				arg := CreateTagParams{
+					UserID: tag.UserID,
+					Name:   tag.Name,
+				}
+				_, err := testQueries.CreateTag(context.Background(), arg)
+				require.ErrorContains(t, err, ErrUniqueViolation.Code)
+			},
+		},
+		{
+			name: "SameNameOtherUser",
+			buildStubs: func(t *testing.T) {
+				tag := createRandomDbTag(t, createRandomDbUser(t).ID)
+
+				arg := CreateTagParams{
+					UserID: createRandomDbUser(t).ID,
+					Name:   tag.Name,
+				}
+				_, err := testQueries.CreateTag(context.Background(), arg)
+				require.NoError(t, err)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, tc.buildStubs)
+	}
+}
+
+func TestQueries_AttachTag(t *testing.T) {
+	link := createRandomDbLink(t)
+	otherLink := createRandomDbLink(t)
+	tag := createRandomDbTag(t, link.UserID)
+
+	arg := AttachTagParams{
+		TagID:   tag.ID,
+		LinkIds: []int64{link.ID, otherLink.ID},
+		UserID:  link.UserID,
+	}
+
+	// Only the caller's own link is tagged
+	affected, err := testQueries.AttachTag(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), affected)
+
+	// Attaching twice is a no-op
+	affected, err = testQueries.AttachTag(context.Background(), arg)
+	require.NoError(t, err)
+	require.Zero(t, affected)
+
+	tags, err := testQueries.GetTagsByLink(context.Background(), link.ID)
+	require.NoError(t, err)
+	require.Equal(t, []Tag{tag}, tags)
+
+	links, err := testQueries.GetLinksByUser(context.Background(), GetLinksByUserParams{
+		UserID: link.UserID,
+		TagID:  pgtype.Int8{Int64: tag.ID, Valid: true},
+		Limit:  10,
+	})
+	require.NoError(t, err)
+	require.Len(t, links, 1)
+	require.Equal(t, link.ID, links[0].ID)
+
+	affected, err = testQueries.DetachTag(context.Background(), DetachTagParams{
+		TagID:   tag.ID,
+		LinkIds: []int64{link.ID},
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(1), affected)
+
+	tags, err = testQueries.GetTagsByLink(context.Background(), link.ID)
+	require.NoError(t, err)
+	require.Empty(t, tags)
+}
+
+func TestQueries_DeleteTag(t *testing.T) {
+	tag := createRandomDbTag(t, createRandomDbUser(t).ID)
+
+	err := testQueries.DeleteTag(context.Background(), tag.ID)
+	require.NoError(t, err)
+
+	_, err = testQueries.GetTag(context.Background(), tag.ID)
+	require.EqualError(t, err, ErrRecordNotFound.Error())
+}
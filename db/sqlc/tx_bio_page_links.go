@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrBioPageLinkNotOwned is returned when a bio page is given a link that is missing or belongs to someone else
+var ErrBioPageLinkNotOwned = errors.New("link does not exist or does not belong to the page's owner")
+
+type SetBioPageLinksTxParams struct {
+	BioPageID int64 `json:"bio_page_id"`
+	UserID    int64 `json:"user_id"`
+	// Links are listed in the order the page shows them
+	Links []BioPageLinkItem `json:"links"`
+}
+
+type BioPageLinkItem struct {
+	LinkID  int64  `json:"link_id"`
+	Label   string `json:"label"`
+	IconUrl string `json:"icon_url"`
+}
+
+// SetBioPageLinksTx replaces every link of a bio page, keeping the old ones when any new link is not the owner's
+func (store *SQLStore) SetBioPageLinksTx(ctx context.Context, arg SetBioPageLinksTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		if err := q.DeleteBioPageLinks(ctx, arg.BioPageID); err != nil {
+			return err
+		}
+
+		add := AddBioPageLinksParams{
+			BioPageID: arg.BioPageID,
+			LinkIds:   make([]int64, len(arg.Links)),
+			Labels:    make([]string, len(arg.Links)),
+			IconUrls:  make([]string, len(arg.Links)),
+			UserID:    arg.UserID,
+		}
+		for i, item := range arg.Links {
+			add.LinkIds[i] = item.LinkID
+			add.Labels[i] = item.Label
+			add.IconUrls[i] = item.IconUrl
+		}
+
+		added, err := q.AddBioPageLinks(ctx, add)
+		if err != nil {
+			return err
+		}
+		if added != int64(len(arg.Links)) {
+			return ErrBioPageLinkNotOwned
+		}
+
+		return nil
+	})
+}
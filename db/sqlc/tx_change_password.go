@@ -0,0 +1,40 @@
+package db
+
+import (
+	"context"
+	"github.com/google/uuid"
+)
+
+type ChangePasswordTxParams struct {
+	UserID         int64  `json:"user_id"`
+	HashedPassword string `json:"hashed_password"`
+	// KeepSessionID is the session that stays signed in; uuid.Nil blocks them all
+	KeepSessionID uuid.UUID `json:"keep_session_id"`
+}
+
+// ChangePasswordTx sets a user's new password and blocks every other session.
+// Reset emails sent before the change can no longer be used.
+func (store *SQLStore) ChangePasswordTx(ctx context.Context, arg ChangePasswordTxParams) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		user, err = q.UpdateUserPassword(ctx, UpdateUserPasswordParams{
+			HashedPassword:    arg.HashedPassword,
+			PasswordChangedAt: passwordChangedNow(),
+			ID:                arg.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = q.RevokeUserTokens(ctx, RevokeUserTokensParams{UserID: user.ID, Purpose: UserTokenPasswordReset})
+		if err != nil {
+			return err
+		}
+
+		return blockSessions(ctx, q, user.ID, arg.KeepSessionID)
+	})
+
+	return user, err
+}
@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"fmt"
+)
+
+type CreateLinkTxParams struct {
+	CreateLinkParams
+	// Tags are tag names, created for the user when they do not exist yet
+	Tags []string `json:"tags"`
+}
+
+type CreateLinkTxResult struct {
+	Link Link  `json:"link"`
+	Tags []Tag `json:"tags"`
+}
+
+// LinkTxError reports which item of a batch stopped the transaction
+type LinkTxError struct {
+	Index int
+	Err   error
+}
+
+func (e *LinkTxError) Error() string {
+	return fmt.Sprintf("link %d: %v", e.Index, e.Err)
+}
+
+func (e *LinkTxError) Unwrap() error {
+	return e.Err
+}
+
+// CreateLinkTx creates a link together with its tags
+func (store *SQLStore) CreateLinkTx(ctx context.Context, arg CreateLinkTxParams) (CreateLinkTxResult, error) {
+	var result CreateLinkTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		result, err = createLinkWithTags(ctx, q, arg)
+		return err
+	})
+
+	return result, err
+}
+
+// CreateLinksTx creates every link in one transaction, nothing is kept when any of them fails
+func (store *SQLStore) CreateLinksTx(ctx context.Context, arg []CreateLinkTxParams) ([]CreateLinkTxResult, error) {
+	results := make([]CreateLinkTxResult, len(arg))
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		for i, item := range arg {
+			result, err := createLinkWithTags(ctx, q, item)
+			if err != nil {
+				return &LinkTxError{Index: i, Err: err}
+			}
+			results[i] = result
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func createLinkWithTags(ctx context.Context, q *Queries, arg CreateLinkTxParams) (CreateLinkTxResult, error) {
+	var result CreateLinkTxResult
+	var err error
+
+	result.Link, err = q.CreateLink(ctx, arg.CreateLinkParams)
+	if err != nil {
+		return result, err
+	}
+
+	result.Tags = []Tag{}
+	for _, name := range arg.Tags {
+		tag, err := q.UpsertTag(ctx, UpsertTagParams{UserID: arg.UserID, Name: name})
+		if err != nil {
+			return result, err
+		}
+
+		_, err = q.AttachTag(ctx, AttachTagParams{
+			TagID:   tag.ID,
+			LinkIds: []int64{result.Link.ID},
+			UserID:  arg.UserID,
+		})
+		if err != nil {
+			return result, err
+		}
+
+		result.Tags = append(result.Tags, tag)
+	}
+
+	return result, nil
+}
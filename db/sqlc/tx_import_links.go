@@ -0,0 +1,126 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const ConflictCodeTaken = "code already in use"
+
+// ErrStaleImportBatch means the job was taken over and moved past where the batch starts, so the batch was dropped
+var ErrStaleImportBatch = errors.New("import job has moved on since the batch was read")
+
+type ImportLinkRecord struct {
+	Line      int64
+	Code      string
+	Link      string
+	Title     string
+	CreatedAt pgtype.Timestamptz
+	Clicks    int64
+	// UtmCampaign is the campaign named by the destination's utm_campaign parameter
+	UtmCampaign string
+	// Conflict explains why the record cannot be imported as it is.
+	// The link is still created under a new code unless Skip is set.
+	Conflict string
+	Skip     bool
+}
+
+type ImportLinksTxParams struct {
+	JobID   int64
+	UserID  int64
+	Records []ImportLinkRecord
+	// Start is how many records of the job were processed before this batch
+	Start int64
+	// LeaseSeconds renews the worker's hold on the job along with its progress
+	LeaseSeconds int64
+}
+
+// ImportLinksTx imports one batch of records and advances the job's progress in the same transaction,
+// so a job resumed after a crash never imports a record twice. A worker whose lease ran out and whose job
+// was resumed elsewhere gets ErrStaleImportBatch, with nothing of its batch kept.
+func (store *SQLStore) ImportLinksTx(ctx context.Context, arg ImportLinksTxParams) (ImportJob, error) {
+	var job ImportJob
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var imported, conflicts int64
+
+		for _, record := range arg.Records {
+			importArg := ImportLinkParams{
+				Code:        record.Code,
+				Link:        record.Link,
+				UserID:      arg.UserID,
+				Title:       record.Title,
+				CreatedAt:   record.CreatedAt,
+				ClickCount:  record.Clicks,
+				UtmCampaign: record.UtmCampaign,
+			}
+
+			reason := record.Conflict
+
+			if reason == "" {
+				_, err := q.ImportLink(ctx, importArg)
+				if errors.Is(err, ErrRecordNotFound) {
+					reason = ConflictCodeTaken
+				} else if err != nil {
+					return err
+				}
+			}
+
+			if reason == "" {
+				imported++
+				continue
+			}
+
+			conflictArg := CreateImportConflictParams{
+				JobID:        arg.JobID,
+				Line:         record.Line,
+				OriginalCode: record.Code,
+				Reason:       reason,
+			}
+
+			if !record.Skip {
+				link, err := importWithNewCode(ctx, q, importArg)
+				if err != nil {
+					return err
+				}
+				conflictArg.LinkID = pgtype.Int8{Int64: link.ID, Valid: true}
+				imported++
+			}
+
+			if _, err := q.CreateImportConflict(ctx, conflictArg); err != nil {
+				return err
+			}
+			conflicts++
+		}
+
+		var err error
+		job, err = q.UpdateImportJobProgress(ctx, UpdateImportJobProgressParams{
+			ID:           arg.JobID,
+			Processed:    int64(len(arg.Records)),
+			Imported:     imported,
+			Conflicts:    conflicts,
+			LeaseSeconds: arg.LeaseSeconds,
+			Start:        arg.Start,
+		})
+		if errors.Is(err, ErrRecordNotFound) {
+			return ErrStaleImportBatch
+		}
+		return err
+	})
+
+	return job, err
+}
+
+// importWithNewCode imports under a generated code, retrying on the rare collision
+func importWithNewCode(ctx context.Context, q *Queries, arg ImportLinkParams) (Link, error) {
+	for attempt := 1; ; attempt++ {
+		arg.Code = util.RandomCode()
+
+		link, err := q.ImportLink(ctx, arg)
+		if !errors.Is(err, ErrRecordNotFound) || attempt == 3 {
+			return link, err
+		}
+	}
+}
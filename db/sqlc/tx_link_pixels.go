@@ -0,0 +1,50 @@
+package db
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrLinkPixelNotOwned is returned when a link is given a pixel that is missing or belongs to someone else
+var ErrLinkPixelNotOwned = errors.New("pixel does not exist or does not belong to the link's owner")
+
+type SetLinkPixelsTxParams struct {
+	LinkID   int64   `json:"link_id"`
+	UserID   int64   `json:"user_id"`
+	PixelIDs []int64 `json:"pixel_ids"`
+}
+
+// SetLinkPixelsTx replaces the pixels a link fires, keeping the old ones when any new pixel is not the owner's.
+// An empty list turns the link back into a plain redirect.
+func (store *SQLStore) SetLinkPixelsTx(ctx context.Context, arg SetLinkPixelsTxParams) (Link, error) {
+	var link Link
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if err := q.DeleteLinkPixels(ctx, arg.LinkID); err != nil {
+			return err
+		}
+
+		if len(arg.PixelIDs) > 0 {
+			added, err := q.AddLinkPixels(ctx, AddLinkPixelsParams{
+				LinkID:   arg.LinkID,
+				PixelIds: arg.PixelIDs,
+				UserID:   arg.UserID,
+			})
+			if err != nil {
+				return err
+			}
+			if added != int64(len(arg.PixelIDs)) {
+				return ErrLinkPixelNotOwned
+			}
+		}
+
+		var err error
+		link, err = q.SetLinkHasPixels(ctx, SetLinkHasPixelsParams{
+			HasPixels: len(arg.PixelIDs) > 0,
+			ID:        arg.LinkID,
+		})
+		return err
+	})
+
+	return link, err
+}
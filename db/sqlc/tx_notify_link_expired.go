@@ -0,0 +1,22 @@
+package db
+
+import (
+	"context"
+)
+
+type NotifyLinkExpiredTxParams struct {
+	LinkID     int64
+	Deliveries CreateWebhookDeliveriesParams
+}
+
+// NotifyLinkExpiredTx queues a link's expiry deliveries and marks it notified, so the event is neither lost
+// nor queued again on the next run
+func (store *SQLStore) NotifyLinkExpiredTx(ctx context.Context, arg NotifyLinkExpiredTxParams) error {
+	return store.execTx(ctx, func(q *Queries) error {
+		if _, err := q.CreateWebhookDeliveries(ctx, arg.Deliveries); err != nil {
+			return err
+		}
+
+		return q.MarkLinkExpiryNotified(ctx, arg.LinkID)
+	})
+}
@@ -0,0 +1,76 @@
+package db
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"time"
+)
+
+// UserTokenPasswordReset marks the tokens emailed to let a user choose a new password
+const UserTokenPasswordReset = "password_reset"
+
+type ResetPasswordTxParams struct {
+	TokenHash      string `json:"token_hash"`
+	HashedPassword string `json:"hashed_password"`
+}
+
+// ResetPasswordTx uses up a password reset token, sets the new password and blocks all of the user's sessions.
+// A token that is unknown, expired or already used returns ErrRecordNotFound.
+func (store *SQLStore) ResetPasswordTx(ctx context.Context, arg ResetPasswordTxParams) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		token, err := q.UseUserToken(ctx, UseUserTokenParams{
+			TokenHash: arg.TokenHash,
+			Purpose:   UserTokenPasswordReset,
+		})
+		if err != nil {
+			return err
+		}
+
+		user, err = q.UpdateUserPassword(ctx, UpdateUserPasswordParams{
+			HashedPassword:    arg.HashedPassword,
+			PasswordChangedAt: passwordChangedNow(),
+			ID:                token.UserID,
+		})
+		if err != nil {
+			return err
+		}
+
+		// Reset emails still waiting in the inbox are void once the password has changed
+		err = q.RevokeUserTokens(ctx, RevokeUserTokensParams{UserID: user.ID, Purpose: UserTokenPasswordReset})
+		if err != nil {
+			return err
+		}
+
+		return blockSessions(ctx, q, user.ID, uuid.Nil)
+	})
+
+	return user, err
+}
+
+// passwordChangedNow is the time to record a password change at. It comes from the same clock that
+// stamps access tokens, which are compared against it, and is stored as UTC in the timestamp column.
+func passwordChangedNow() pgtype.Timestamp {
+	return pgtype.Timestamp{Time: time.Now().UTC(), Valid: true}
+}
+
+// blockSessions blocks every live session of a user apart from keep
+func blockSessions(ctx context.Context, q *Queries, userID int64, keep uuid.UUID) error {
+	ids, err := q.GetUnblockedSessionIDs(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if id == keep {
+			continue
+		}
+		if _, err := q.BlockSession(ctx, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
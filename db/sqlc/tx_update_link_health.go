@@ -0,0 +1,32 @@
+package db
+
+import (
+	"context"
+)
+
+type UpdateLinkHealthTxParams struct {
+	UpdateLinkHealthParams
+	// Deliveries, when set, are queued with the new health, so the event is never lost or sent for a change
+	// that was not saved
+	Deliveries *CreateWebhookDeliveriesParams
+}
+
+// UpdateLinkHealthTx records a link's health check and queues the webhook deliveries it triggers
+func (store *SQLStore) UpdateLinkHealthTx(ctx context.Context, arg UpdateLinkHealthTxParams) (Link, error) {
+	var link Link
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		link, err = q.UpdateLinkHealth(ctx, arg.UpdateLinkHealthParams)
+		if err != nil {
+			return err
+		}
+
+		if arg.Deliveries != nil {
+			_, err = q.CreateWebhookDeliveries(ctx, *arg.Deliveries)
+		}
+		return err
+	})
+
+	return link, err
+}
@@ -0,0 +1,29 @@
+package db
+
+import (
+	"context"
+)
+
+// UserTokenEmailVerification marks the tokens emailed to prove an address belongs to its user
+const UserTokenEmailVerification = "email_verification"
+
+// VerifyEmailTx uses up an email verification token and marks its user's email as verified.
+// A token that is unknown, expired or already used returns ErrRecordNotFound.
+func (store *SQLStore) VerifyEmailTx(ctx context.Context, tokenHash string) (User, error) {
+	var user User
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		token, err := q.UseUserToken(ctx, UseUserTokenParams{
+			TokenHash: tokenHash,
+			Purpose:   UserTokenEmailVerification,
+		})
+		if err != nil {
+			return err
+		}
+
+		user, err = q.VerifyUserEmail(ctx, token.UserID)
+		return err
+	})
+
+	return user, err
+}
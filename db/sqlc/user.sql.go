@@ -7,12 +7,14 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 const createUser = `-- name: CreateUser :one
 INSERT INTO users (first_name, last_name, email, username, hashed_password)
 VALUES ($1, $2, $3, $4, $5)
-RETURNING id, username, hashed_password, first_name, last_name, email, password_changed_at, created_at
+RETURNING id, username, hashed_password, first_name, last_name, email, password_changed_at, created_at, reuse_duplicate_links, email_verified_at
 `
 
 type CreateUserParams struct {
@@ -41,12 +43,14 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.ReuseDuplicateLinks,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT id, username, hashed_password, first_name, last_name, email, password_changed_at, created_at
+SELECT id, username, hashed_password, first_name, last_name, email, password_changed_at, created_at, reuse_duplicate_links, email_verified_at
 FROM users
 WHERE username = $1 OR email = $1
 LIMIT 1
@@ -64,12 +68,14 @@ func (q *Queries) GetUser(ctx context.Context, usernameoremail string) (User, er
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.ReuseDuplicateLinks,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
 
 const getUserById = `-- name: GetUserById :one
-SELECT id, username, hashed_password, first_name, last_name, email, password_changed_at, created_at
+SELECT id, username, hashed_password, first_name, last_name, email, password_changed_at, created_at, reuse_duplicate_links, email_verified_at
 FROM users
 WHERE id = $1
 LIMIT 1
@@ -87,6 +93,8 @@ func (q *Queries) GetUserById(ctx context.Context, id int64) (User, error) {
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.ReuseDuplicateLinks,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
@@ -98,7 +106,7 @@ set first_name = $1,
     email      = $3,
     username   = $4
 where id = $5
-returning id, username, hashed_password, first_name, last_name, email, password_changed_at, created_at
+returning id, username, hashed_password, first_name, last_name, email, password_changed_at, created_at, reuse_duplicate_links, email_verified_at
 `
 
 type UpdateUserParams struct {
@@ -127,6 +135,95 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (User, e
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.ReuseDuplicateLinks,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :one
+update users
+set hashed_password     = $1,
+    password_changed_at = $2
+where id = $3
+returning id, username, hashed_password, first_name, last_name, email, password_changed_at, created_at, reuse_duplicate_links, email_verified_at
+`
+
+type UpdateUserPasswordParams struct {
+	HashedPassword    string           `json:"hashed_password"`
+	PasswordChangedAt pgtype.Timestamp `json:"password_changed_at"`
+	ID                int64            `json:"id"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserPassword, arg.HashedPassword, arg.PasswordChangedAt, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.HashedPassword,
+		&i.FirstName,
+		&i.LastName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.ReuseDuplicateLinks,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const updateUserPreferences = `-- name: UpdateUserPreferences :one
+update users
+set reuse_duplicate_links = $1
+where id = $2
+returning id, username, hashed_password, first_name, last_name, email, password_changed_at, created_at, reuse_duplicate_links, email_verified_at
+`
+
+type UpdateUserPreferencesParams struct {
+	ReuseDuplicateLinks bool  `json:"reuse_duplicate_links"`
+	ID                  int64 `json:"id"`
+}
+
+func (q *Queries) UpdateUserPreferences(ctx context.Context, arg UpdateUserPreferencesParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserPreferences, arg.ReuseDuplicateLinks, arg.ID)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.HashedPassword,
+		&i.FirstName,
+		&i.LastName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.ReuseDuplicateLinks,
+		&i.EmailVerifiedAt,
+	)
+	return i, err
+}
+
+const verifyUserEmail = `-- name: VerifyUserEmail :one
+update users
+set email_verified_at = coalesce(email_verified_at, now())
+where id = $1
+returning id, username, hashed_password, first_name, last_name, email, password_changed_at, created_at, reuse_duplicate_links, email_verified_at
+`
+
+func (q *Queries) VerifyUserEmail(ctx context.Context, id int64) (User, error) {
+	row := q.db.QueryRow(ctx, verifyUserEmail, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.HashedPassword,
+		&i.FirstName,
+		&i.LastName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.ReuseDuplicateLinks,
+		&i.EmailVerifiedAt,
 	)
 	return i, err
 }
@@ -258,3 +258,16 @@ func requireBodyMatchUser(t *testing.T, user User, otherUser User) {
 	require.Equal(t, user.LastName, otherUser.LastName)
 	require.Equal(t, user.Email, otherUser.Email)
 }
+
+func TestQueries_UpdateUserPreferences(t *testing.T) {
+	user := createRandomDbUser(t)
+	require.False(t, user.ReuseDuplicateLinks)
+
+	updated, err := testQueries.UpdateUserPreferences(context.Background(), UpdateUserPreferencesParams{
+		ID:                  user.ID,
+		ReuseDuplicateLinks: true,
+	})
+	require.NoError(t, err)
+	require.True(t, updated.ReuseDuplicateLinks)
+	require.Equal(t, user.Username, updated.Username)
+}
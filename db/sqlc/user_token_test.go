@@ -0,0 +1,151 @@
+package db
+
+import (
+	"context"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func createRandomUserToken(t *testing.T, userID int64, purpose string, expiresAt time.Time) (UserToken, string) {
+	hash := util.RandomString(64)
+
+	token, err := testQueries.CreateUserToken(context.Background(), CreateUserTokenParams{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: hash,
+		ExpiresAt: expiresAt,
+	})
+	require.NoError(t, err)
+	require.False(t, token.UsedAt.Valid)
+
+	return token, hash
+}
+
+func TestSQLStore_VerifyEmailTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomDbUser(t)
+	require.False(t, user.EmailVerifiedAt.Valid)
+
+	_, hash := createRandomUserToken(t, user.ID, UserTokenEmailVerification, time.Now().Add(time.Hour))
+
+	verified, err := store.VerifyEmailTx(context.Background(), hash)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, verified.ID)
+	require.True(t, verified.EmailVerifiedAt.Valid)
+
+	// Tokens are single use
+	_, err = store.VerifyEmailTx(context.Background(), hash)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	_, expired := createRandomUserToken(t, user.ID, UserTokenEmailVerification, time.Now().Add(-time.Minute))
+	_, err = store.VerifyEmailTx(context.Background(), expired)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+
+	// A token issued for something else does not verify the email
+	_, other := createRandomUserToken(t, user.ID, "other", time.Now().Add(time.Hour))
+	_, err = store.VerifyEmailTx(context.Background(), other)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestQueries_RevokeUserTokens(t *testing.T) {
+	user := createRandomDbUser(t)
+	_, hash := createRandomUserToken(t, user.ID, UserTokenEmailVerification, time.Now().Add(time.Hour))
+
+	err := testQueries.RevokeUserTokens(context.Background(), RevokeUserTokensParams{
+		UserID:  user.ID,
+		Purpose: UserTokenEmailVerification,
+	})
+	require.NoError(t, err)
+
+	_, err = testQueries.UseUserToken(context.Background(), UseUserTokenParams{
+		TokenHash: hash,
+		Purpose:   UserTokenEmailVerification,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestSQLStore_ResetPasswordTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomDbUser(t)
+	first := createDbSessionForUser(t, user.ID)
+	second := createDbSessionForUser(t, user.ID)
+	other := createRandomDbSession(t)
+
+	_, hash := createRandomUserToken(t, user.ID, UserTokenPasswordReset, time.Now().Add(time.Hour))
+	_, pending := createRandomUserToken(t, user.ID, UserTokenPasswordReset, time.Now().Add(time.Hour))
+
+	hashedPassword, err := util.HashPassword(util.RandomString(10))
+	require.NoError(t, err)
+
+	updated, err := store.ResetPasswordTx(context.Background(), ResetPasswordTxParams{
+		TokenHash:      hash,
+		HashedPassword: hashedPassword,
+	})
+	require.NoError(t, err)
+	require.Equal(t, hashedPassword, updated.HashedPassword)
+	require.WithinDuration(t, time.Now(), updated.PasswordChangedAt.Time, time.Minute)
+
+	for _, session := range []Session{first, second} {
+		got, err := testQueries.GetSession(context.Background(), session.ID)
+		require.NoError(t, err)
+		require.True(t, got.IsBlocked)
+	}
+
+	got, err := testQueries.GetSession(context.Background(), other.ID)
+	require.NoError(t, err)
+	require.False(t, got.IsBlocked)
+
+	// Neither the used token nor one sent before it can reset the password again
+	for _, tokenHash := range []string{hash, pending} {
+		_, err = store.ResetPasswordTx(context.Background(), ResetPasswordTxParams{
+			TokenHash:      tokenHash,
+			HashedPassword: hashedPassword,
+		})
+		require.ErrorIs(t, err, ErrRecordNotFound)
+	}
+
+	recent, err := testQueries.CountRecentUserTokens(context.Background(), CountRecentUserTokensParams{
+		UserID:    user.ID,
+		Purpose:   UserTokenPasswordReset,
+		CreatedAt: time.Now().Add(-time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), recent)
+}
+
+func TestSQLStore_ChangePasswordTx(t *testing.T) {
+	store := NewStore(testDB)
+	user := createRandomDbUser(t)
+	current := createDbSessionForUser(t, user.ID)
+	other := createDbSessionForUser(t, user.ID)
+
+	_, pending := createRandomUserToken(t, user.ID, UserTokenPasswordReset, time.Now().Add(time.Hour))
+
+	hashedPassword, err := util.HashPassword(util.RandomString(10))
+	require.NoError(t, err)
+
+	updated, err := store.ChangePasswordTx(context.Background(), ChangePasswordTxParams{
+		UserID:         user.ID,
+		HashedPassword: hashedPassword,
+		KeepSessionID:  current.ID,
+	})
+	require.NoError(t, err)
+	require.Equal(t, hashedPassword, updated.HashedPassword)
+	require.WithinDuration(t, time.Now(), updated.PasswordChangedAt.Time, time.Minute)
+
+	kept, err := testQueries.GetSession(context.Background(), current.ID)
+	require.NoError(t, err)
+	require.False(t, kept.IsBlocked)
+
+	blocked, err := testQueries.GetSession(context.Background(), other.ID)
+	require.NoError(t, err)
+	require.True(t, blocked.IsBlocked)
+
+	_, err = store.ResetPasswordTx(context.Background(), ResetPasswordTxParams{
+		TokenHash:      pending,
+		HashedPassword: hashedPassword,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
@@ -0,0 +1,256 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/bolusarz/urlmini/util"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func createRandomDbWebhook(t *testing.T, userID int64, events ...string) Webhook {
+	arg := CreateWebhookParams{
+		UserID:              userID,
+		Url:                 util.RandomLink(),
+		Secret:              util.RandomString(32),
+		Events:              events,
+		BatchSize:           2,
+		BatchMaxWaitSeconds: 60,
+	}
+
+	hook, err := testQueries.CreateWebhook(context.Background(), arg)
+	require.NoError(t, err)
+	require.Equal(t, arg.Url, hook.Url)
+	require.Equal(t, arg.Events, hook.Events)
+	require.True(t, hook.Active)
+
+	return hook
+}
+
+func TestQueries_WebhookDeliveries(t *testing.T) {
+	user := createRandomDbUser(t)
+	expired := createRandomDbWebhook(t, user.ID, "link.expired", "link.unhealthy")
+	createRandomDbWebhook(t, user.ID, "link.click_limit_reached")
+
+	deliveries, err := testQueries.CreateWebhookDeliveries(context.Background(), CreateWebhookDeliveriesParams{
+		UserID:  user.ID,
+		Event:   "link.expired",
+		Payload: []byte(`{"event":"link.expired"}`),
+	})
+	require.NoError(t, err)
+	require.Len(t, deliveries, 1)
+
+	delivery := deliveries[0]
+	require.Equal(t, expired.ID, delivery.WebhookID)
+	require.Equal(t, "pending", delivery.Status)
+	require.JSONEq(t, `{"event":"link.expired"}`, string(delivery.Payload))
+
+	claimed, err := testQueries.ClaimWebhookDeliveries(context.Background(), ClaimWebhookDeliveriesParams{
+		LeaseUntil: time.Now().Add(time.Minute),
+		Limit:      1000,
+	})
+	require.NoError(t, err)
+
+	var found bool
+	for _, c := range claimed {
+		found = found || c.ID == delivery.ID
+	}
+	require.True(t, found)
+
+	// A leased delivery is not handed out twice
+	claimed, err = testQueries.ClaimWebhookDeliveries(context.Background(), ClaimWebhookDeliveriesParams{
+		LeaseUntil: time.Now().Add(time.Minute),
+		Limit:      1000,
+	})
+	require.NoError(t, err)
+	for _, c := range claimed {
+		require.NotEqual(t, delivery.ID, c.ID)
+	}
+
+	failed, err := testQueries.RecordWebhookAttempt(context.Background(), RecordWebhookAttemptParams{
+		ID:             delivery.ID,
+		Status:         "pending",
+		ResponseStatus: 500,
+		Error:          "boom",
+		NextAttemptAt:  time.Now().Add(time.Hour),
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(1), failed.Attempts)
+	require.Equal(t, int32(500), failed.ResponseStatus)
+	require.False(t, failed.DeliveredAt.Valid)
+
+	succeeded, err := testQueries.RecordWebhookAttempt(context.Background(), RecordWebhookAttemptParams{
+		ID:             delivery.ID,
+		Status:         "succeeded",
+		ResponseStatus: 200,
+		NextAttemptAt:  failed.NextAttemptAt,
+	})
+	require.NoError(t, err)
+	require.Equal(t, int32(2), succeeded.Attempts)
+	require.True(t, succeeded.DeliveredAt.Valid)
+
+	redelivered, err := testQueries.RedeliverWebhookDelivery(context.Background(), delivery.ID)
+	require.NoError(t, err)
+	require.Equal(t, "pending", redelivered.Status)
+	require.Zero(t, redelivered.Attempts)
+
+	page, err := testQueries.GetWebhookDeliveries(context.Background(), GetWebhookDeliveriesParams{
+		WebhookID: expired.ID,
+		Limit:     10,
+	})
+	require.NoError(t, err)
+	require.Len(t, page, 1)
+
+	page, err = testQueries.GetWebhookDeliveries(context.Background(), GetWebhookDeliveriesParams{
+		WebhookID: expired.ID,
+		CursorID:  pgtype.Int8{Int64: delivery.ID, Valid: true},
+		Limit:     10,
+	})
+	require.NoError(t, err)
+	require.Empty(t, page)
+
+	total, err := testQueries.CountWebhookDeliveries(context.Background(), expired.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+
+	// Deleting the webhook takes its deliveries with it
+	require.NoError(t, testQueries.DeleteWebhook(context.Background(), expired.ID))
+	_, err = testQueries.GetWebhookDelivery(context.Background(), delivery.ID)
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
+
+func TestQueries_LinkLimits(t *testing.T) {
+	link := createRandomDbLink(t)
+
+	updated, err := testQueries.SetLinkLimits(context.Background(), SetLinkLimitsParams{
+		ID:        link.ID,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true},
+		MaxClicks: pgtype.Int8{Int64: 5, Valid: true},
+	})
+	require.NoError(t, err)
+	require.True(t, updated.ExpiresAt.Valid)
+	require.Equal(t, int64(5), updated.MaxClicks.Int64)
+
+	expired, err := testQueries.GetExpiredLinksToNotify(context.Background(), 1000)
+	require.NoError(t, err)
+	require.Contains(t, linkIDs(expired), link.ID)
+
+	require.NoError(t, testQueries.MarkLinkExpiryNotified(context.Background(), link.ID))
+
+	expired, err = testQueries.GetExpiredLinksToNotify(context.Background(), 1000)
+	require.NoError(t, err)
+	require.NotContains(t, linkIDs(expired), link.ID)
+
+	cleared, err := testQueries.SetLinkLimits(context.Background(), SetLinkLimitsParams{ID: link.ID})
+	require.NoError(t, err)
+	require.False(t, cleared.ExpiresAt.Valid)
+	require.False(t, cleared.MaxClicks.Valid)
+	require.False(t, cleared.ExpiryNotified)
+}
+
+func TestStore_NotifyLinkExpiredTx(t *testing.T) {
+	store := NewStore(testDB)
+	link := createRandomDbLink(t)
+	hook := createRandomDbWebhook(t, link.UserID, "link.expired")
+
+	_, err := testQueries.SetLinkLimits(context.Background(), SetLinkLimitsParams{
+		ID:        link.ID,
+		ExpiresAt: pgtype.Timestamptz{Time: time.Now().Add(-time.Minute), Valid: true},
+	})
+	require.NoError(t, err)
+
+	arg := NotifyLinkExpiredTxParams{
+		LinkID: link.ID,
+		Deliveries: CreateWebhookDeliveriesParams{
+			UserID:  link.UserID,
+			Event:   "link.expired",
+			Payload: []byte("not json"),
+		},
+	}
+
+	// The link stays due when its event cannot be queued
+	require.Error(t, store.NotifyLinkExpiredTx(context.Background(), arg))
+
+	expired, err := testQueries.GetExpiredLinksToNotify(context.Background(), 1000)
+	require.NoError(t, err)
+	require.Contains(t, linkIDs(expired), link.ID)
+
+	arg.Deliveries.Payload = []byte(`{"event":"link.expired"}`)
+	require.NoError(t, store.NotifyLinkExpiredTx(context.Background(), arg))
+
+	expired, err = testQueries.GetExpiredLinksToNotify(context.Background(), 1000)
+	require.NoError(t, err)
+	require.NotContains(t, linkIDs(expired), link.ID)
+
+	total, err := testQueries.CountWebhookDeliveries(context.Background(), hook.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), total)
+}
+
+func TestQueries_ClickOutbox(t *testing.T) {
+	link := createRandomDbLink(t)
+	hook := createRandomDbWebhook(t, link.UserID, "link.clicked")
+	createRandomDbWebhook(t, link.UserID, "link.expired")
+
+	for i := 0; i < 3; i++ {
+		_, err := testQueries.RecordClick(context.Background(), RecordClickParams{LinkID: link.ID, Referrer: "https://example.com"})
+		require.NoError(t, err)
+	}
+
+	// Three queued clicks fill a batch of two
+	due, err := testQueries.GetWebhooksDueClickBatch(context.Background(), 1000)
+	require.NoError(t, err)
+
+	var found bool
+	for _, d := range due {
+		found = found || d.ID == hook.ID
+	}
+	require.True(t, found)
+
+	delivery, err := testQueries.CreateClickBatchDelivery(context.Background(), CreateClickBatchDeliveryParams{
+		WebhookID: hook.ID,
+		Limit:     hook.BatchSize,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "link.clicked", delivery.Event)
+
+	var payload struct {
+		Event string `json:"event"`
+		Data  struct {
+			Clicks []struct {
+				LinkID   int64  `json:"link_id"`
+				Code     string `json:"code"`
+				Referrer string `json:"referrer"`
+			} `json:"clicks"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(delivery.Payload, &payload))
+	require.Equal(t, "link.clicked", payload.Event)
+	require.Len(t, payload.Data.Clicks, 2)
+	require.Equal(t, link.ID, payload.Data.Clicks[0].LinkID)
+	require.Equal(t, link.Code, payload.Data.Clicks[0].Code)
+	require.Equal(t, "https://example.com", payload.Data.Clicks[0].Referrer)
+
+	// The single click left waits for the batch to fill or grow old
+	due, err = testQueries.GetWebhooksDueClickBatch(context.Background(), 1000)
+	require.NoError(t, err)
+	for _, d := range due {
+		require.NotEqual(t, hook.ID, d.ID)
+	}
+
+	delivery, err = testQueries.CreateClickBatchDelivery(context.Background(), CreateClickBatchDeliveryParams{
+		WebhookID: hook.ID,
+		Limit:     hook.BatchSize,
+	})
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(delivery.Payload, &payload))
+	require.Len(t, payload.Data.Clicks, 1)
+
+	_, err = testQueries.CreateClickBatchDelivery(context.Background(), CreateClickBatchDeliveryParams{
+		WebhookID: hook.ID,
+		Limit:     hook.BatchSize,
+	})
+	require.ErrorIs(t, err, ErrRecordNotFound)
+}
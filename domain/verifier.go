@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/bolusarz/urlmini/metadata"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	MethodDNS  = "dns"
+	MethodHTTP = "http"
+
+	// TXTRecordPrefix is prepended to the hostname to name the TXT record holding the token
+	TXTRecordPrefix = "_urlmini-challenge."
+	TXTValuePrefix  = "urlmini-verification="
+	// WellKnownPath is where the HTTP method expects the token, served as the whole body
+	WellKnownPath = "/.well-known/urlmini-verification"
+
+	DefaultTimeout = 10 * time.Second
+
+	maxBodySize = 1024
+)
+
+var (
+	ErrUnknownMethod = errors.New("domain: unknown verification method")
+	ErrNotVerified   = errors.New("domain: verification token was not found")
+)
+
+// Resolver looks up TXT records; *net.Resolver satisfies it and tests substitute their own
+type Resolver interface {
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+type Options struct {
+	Resolver Resolver
+	Timeout  time.Duration
+	// AllowPrivateNetworks is handed to metadata.NewClient
+	AllowPrivateNetworks bool
+}
+
+// Verifier checks that whoever registered a domain controls it
+type Verifier struct {
+	resolver Resolver
+	client   *http.Client
+}
+
+func NewVerifier(opts Options) *Verifier {
+	if opts.Resolver == nil {
+		opts.Resolver = net.DefaultResolver
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+
+	client := metadata.NewClient(metadata.ClientOptions{
+		Timeout: opts.Timeout,
+		// The token has to be served by the domain itself, not by wherever it redirects
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+		AllowPrivateNetworks: opts.AllowPrivateNetworks,
+	})
+
+	return &Verifier{
+		resolver: opts.Resolver,
+		client:   client,
+	}
+}
+
+// NewToken generates the value a domain owner publishes to prove control
+func NewToken() (string, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(token), nil
+}
+
+// TXTRecord is the name of the record the DNS method looks up
+func TXTRecord(hostname string) string {
+	return TXTRecordPrefix + hostname
+}
+
+// TXTValue is the value the DNS method expects in the record
+func TXTValue(token string) string {
+	return TXTValuePrefix + token
+}
+
+// WellKnownURL is the address the HTTP method requests
+func WellKnownURL(hostname string) string {
+	return "http://" + hostname + WellKnownPath
+}
+
+// Verify looks for token with the given method and returns ErrNotVerified when it is missing
+func (verifier *Verifier) Verify(ctx context.Context, method, hostname, token string) error {
+	switch method {
+	case MethodDNS:
+		return verifier.verifyDNS(ctx, hostname, token)
+	case MethodHTTP:
+		return verifier.verifyHTTP(ctx, hostname, token)
+	default:
+		return ErrUnknownMethod
+	}
+}
+
+func (verifier *Verifier) verifyDNS(ctx context.Context, hostname, token string) error {
+	records, err := verifier.resolver.LookupTXT(ctx, TXTRecord(hostname))
+	if err != nil {
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			return ErrNotVerified
+		}
+		return fmt.Errorf("domain: cannot look up %s: %w", TXTRecord(hostname), err)
+	}
+
+	expected := TXTValue(token)
+	for _, record := range records {
+		if strings.TrimSpace(record) == expected {
+			return nil
+		}
+	}
+	return ErrNotVerified
+}
+
+func (verifier *Verifier) verifyHTTP(ctx context.Context, hostname, token string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, WellKnownURL(hostname), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "urlmini-domain-verifier/1.0")
+
+	resp, err := verifier.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("domain: cannot reach %s: %w", hostname, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ErrNotVerified
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(string(body)) != token {
+		return ErrNotVerified
+	}
+	return nil
+}
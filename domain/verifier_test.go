@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/require"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeResolver map[string][]string
+
+func (resolver fakeResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	records, ok := resolver[name]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return records, nil
+}
+
+type failingResolver struct{}
+
+func (failingResolver) LookupTXT(_ context.Context, name string) ([]string, error) {
+	return nil, &net.DNSError{Err: "server misbehaving", Name: name, IsTemporary: true}
+}
+
+func TestVerifyDNS(t *testing.T) {
+	resolver := fakeResolver{
+		"_urlmini-challenge.go.acme.com": {"v=spf1 -all", TXTValue("token123")},
+	}
+	verifier := NewVerifier(Options{Resolver: resolver})
+
+	require.NoError(t, verifier.Verify(context.Background(), MethodDNS, "go.acme.com", "token123"))
+	require.ErrorIs(t, verifier.Verify(context.Background(), MethodDNS, "go.acme.com", "other"), ErrNotVerified)
+	require.ErrorIs(t, verifier.Verify(context.Background(), MethodDNS, "links.acme.com", "token123"), ErrNotVerified)
+
+	err := NewVerifier(Options{Resolver: failingResolver{}}).Verify(context.Background(), MethodDNS, "go.acme.com", "token123")
+	require.Error(t, err)
+	require.False(t, errors.Is(err, ErrNotVerified))
+}
+
+func TestVerifyHTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != WellKnownPath {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte("token123\n"))
+	}))
+	defer server.Close()
+
+	hostname := strings.TrimPrefix(server.URL, "http://")
+	verifier := NewVerifier(Options{AllowPrivateNetworks: true, Timeout: time.Second})
+
+	require.NoError(t, verifier.Verify(context.Background(), MethodHTTP, hostname, "token123"))
+	require.ErrorIs(t, verifier.Verify(context.Background(), MethodHTTP, hostname, "other"), ErrNotVerified)
+
+	// Loopback is off limits outside tests
+	guarded := NewVerifier(Options{Timeout: time.Second})
+	require.Error(t, guarded.Verify(context.Background(), MethodHTTP, hostname, "token123"))
+}
+
+func TestVerifyUnknownMethod(t *testing.T) {
+	verifier := NewVerifier(Options{Resolver: fakeResolver{}})
+	require.ErrorIs(t, verifier.Verify(context.Background(), "email", "go.acme.com", "token"), ErrUnknownMethod)
+}
+
+func TestNewToken(t *testing.T) {
+	a, err := NewToken()
+	require.NoError(t, err)
+	b, err := NewToken()
+	require.NoError(t, err)
+
+	require.Len(t, a, 32)
+	require.NotEqual(t, a, b)
+}
@@ -0,0 +1,179 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/bolusarz/urlmini/metadata"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultTimeout      = 10 * time.Second
+	DefaultMaxRedirects = 5
+	// DefaultHostInterval spaces out requests to the same host so a user with many links to one site cannot hammer it
+	DefaultHostInterval = 2 * time.Second
+)
+
+var (
+	ErrUnsupportedScheme = errors.New("health: unsupported url scheme")
+	ErrTooManyRedirects  = errors.New("health: too many redirects")
+)
+
+// Result is the outcome of one check of a destination
+type Result struct {
+	StatusCode int
+	Latency    time.Duration
+	Err        error
+}
+
+// Broken reports whether the destination failed to resolve, connect or answered with a 4xx/5xx status
+func (result Result) Broken() bool {
+	return result.Err != nil || result.StatusCode >= 400
+}
+
+type Options struct {
+	Timeout      time.Duration
+	MaxRedirects int
+	HostInterval time.Duration
+	// AllowPrivateNetworks is handed to metadata.NewClient
+	AllowPrivateNetworks bool
+}
+
+// Checker requests link destinations to see whether they still work
+type Checker struct {
+	client *http.Client
+	hosts  *hostLimiter
+}
+
+func NewChecker(opts Options) *Checker {
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxRedirects == 0 {
+		opts.MaxRedirects = DefaultMaxRedirects
+	}
+	if opts.HostInterval == 0 {
+		opts.HostInterval = DefaultHostInterval
+	}
+
+	client := metadata.NewClient(metadata.ClientOptions{
+		Timeout:      opts.Timeout,
+		MaxIdleConns: 20,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= opts.MaxRedirects {
+				return ErrTooManyRedirects
+			}
+			return checkScheme(req.URL)
+		},
+		AllowPrivateNetworks: opts.AllowPrivateNetworks,
+	})
+
+	return &Checker{
+		client: client,
+		hosts:  newHostLimiter(opts.HostInterval),
+	}
+}
+
+// Check sends a HEAD request to the destination, falling back to GET for servers that do not support HEAD
+func (checker *Checker) Check(ctx context.Context, destination string) Result {
+	target, err := url.Parse(destination)
+	if err != nil {
+		return Result{Err: fmt.Errorf("health: invalid url: %w", err)}
+	}
+	if err := checkScheme(target); err != nil {
+		return Result{Err: err}
+	}
+
+	result := checker.do(ctx, http.MethodHead, target)
+	if result.StatusCode == http.StatusMethodNotAllowed || result.StatusCode == http.StatusNotImplemented {
+		result = checker.do(ctx, http.MethodGet, target)
+	}
+	return result
+}
+
+func (checker *Checker) do(ctx context.Context, method string, target *url.URL) Result {
+	if err := checker.hosts.wait(ctx, strings.ToLower(target.Hostname())); err != nil {
+		return Result{Err: err}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, target.String(), nil)
+	if err != nil {
+		return Result{Err: err}
+	}
+	req.Header.Set("User-Agent", "urlmini-health/1.0")
+
+	start := time.Now()
+	resp, err := checker.client.Do(req)
+	latency := time.Since(start)
+	if err != nil {
+		return Result{Latency: latency, Err: err}
+	}
+	defer resp.Body.Close()
+
+	// Drain a little of the body so the connection can be reused
+	io.CopyN(io.Discard, resp.Body, 4096)
+
+	return Result{StatusCode: resp.StatusCode, Latency: latency}
+}
+
+func checkScheme(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ErrUnsupportedScheme
+	}
+	return nil
+}
+
+// hostLimiter hands out request slots at most once per interval for every host
+type hostLimiter struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	next map[string]time.Time
+}
+
+func newHostLimiter(interval time.Duration) *hostLimiter {
+	return &hostLimiter{
+		interval: interval,
+		next:     make(map[string]time.Time),
+	}
+}
+
+// wait blocks until host may be requested again or ctx is done
+func (limiter *hostLimiter) wait(ctx context.Context, host string) error {
+	limiter.mu.Lock()
+	now := time.Now()
+	slot := limiter.next[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	limiter.next[host] = slot.Add(limiter.interval)
+
+	// Forget hosts whose slot has passed so the map does not grow with every destination ever checked
+	for key, next := range limiter.next {
+		if next.Before(now) {
+			delete(limiter.next, key)
+		}
+	}
+	limiter.mu.Unlock()
+
+	delay := time.Until(slot)
+	if delay <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
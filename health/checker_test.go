@@ -0,0 +1,146 @@
+package health
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestChecker() *Checker {
+	return NewChecker(Options{AllowPrivateNetworks: true, Timeout: time.Second, HostInterval: time.Millisecond})
+}
+
+func TestCheck(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/get-only", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/missing", http.StatusFound)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	testCases := []struct {
+		name   string
+		url    string
+		check  func(t *testing.T, result Result)
+		broken bool
+	}{
+		{
+			name: "OK",
+			url:  server.URL + "/ok",
+			check: func(t *testing.T, result Result) {
+				require.NoError(t, result.Err)
+				require.Equal(t, http.StatusOK, result.StatusCode)
+				require.Positive(t, result.Latency)
+			},
+		},
+		{
+			name:   "NotFound",
+			url:    server.URL + "/missing",
+			broken: true,
+			check: func(t *testing.T, result Result) {
+				require.NoError(t, result.Err)
+				require.Equal(t, http.StatusNotFound, result.StatusCode)
+			},
+		},
+		{
+			name: "HeadNotAllowed",
+			url:  server.URL + "/get-only",
+			check: func(t *testing.T, result Result) {
+				require.Equal(t, http.StatusOK, result.StatusCode)
+			},
+		},
+		{
+			name:   "RedirectToMissing",
+			url:    server.URL + "/redirect",
+			broken: true,
+			check: func(t *testing.T, result Result) {
+				require.Equal(t, http.StatusNotFound, result.StatusCode)
+			},
+		},
+		{
+			name:   "RedirectLoop",
+			url:    server.URL + "/loop",
+			broken: true,
+			check: func(t *testing.T, result Result) {
+				require.ErrorIs(t, result.Err, ErrTooManyRedirects)
+			},
+		},
+		{
+			name:   "DNSFailure",
+			url:    "http://urlmini-health.invalid/",
+			broken: true,
+			check: func(t *testing.T, result Result) {
+				require.Error(t, result.Err)
+				require.Zero(t, result.StatusCode)
+			},
+		},
+		{
+			name:   "UnsupportedScheme",
+			url:    "ftp://example.com/file",
+			broken: true,
+			check: func(t *testing.T, result Result) {
+				require.ErrorIs(t, result.Err, ErrUnsupportedScheme)
+			},
+		},
+	}
+
+	checker := newTestChecker()
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			result := checker.Check(context.Background(), tc.url)
+			tc.check(t, result)
+			require.Equal(t, tc.broken, result.Broken())
+		})
+	}
+}
+
+func TestCheckPrivateNetwork(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	result := NewChecker(Options{Timeout: time.Second}).Check(context.Background(), server.URL)
+	require.Error(t, result.Err)
+	require.True(t, result.Broken())
+}
+
+func TestHostLimiter(t *testing.T) {
+	limiter := newHostLimiter(50 * time.Millisecond)
+
+	start := time.Now()
+	require.NoError(t, limiter.wait(context.Background(), "a.example"))
+	require.NoError(t, limiter.wait(context.Background(), "b.example"))
+	require.Less(t, time.Since(start), 50*time.Millisecond)
+
+	require.NoError(t, limiter.wait(context.Background(), "a.example"))
+	require.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+
+	// A cancelled wait gives up instead of sleeping out its slot
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.ErrorIs(t, limiter.wait(ctx, "a.example"), context.Canceled)
+}
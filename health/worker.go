@@ -0,0 +1,158 @@
+package health
+
+import (
+	"context"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/webhook"
+	"github.com/jackc/pgx/v5/pgtype"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultInterval    = time.Minute
+	DefaultBatchSize   = 50
+	DefaultConcurrency = 8
+	// DefaultRecheckAfter is how long a destination's health is trusted before it is checked again
+	DefaultRecheckAfter = 24 * time.Hour
+
+	maxErrorLength = 300
+)
+
+// Worker periodically checks the destinations of active links, oldest check first
+type Worker struct {
+	store        db.Store
+	checker      *Checker
+	interval     time.Duration
+	batchSize    int32
+	concurrency  int
+	recheckAfter time.Duration
+}
+
+func NewWorker(store db.Store, checker *Checker, interval time.Duration) *Worker {
+	if interval == 0 {
+		interval = DefaultInterval
+	}
+
+	return &Worker{
+		store:        store,
+		checker:      checker,
+		interval:     interval,
+		batchSize:    DefaultBatchSize,
+		concurrency:  DefaultConcurrency,
+		recheckAfter: DefaultRecheckAfter,
+	}
+}
+
+// Run checks due links until ctx is cancelled
+func (worker *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(worker.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := worker.ProcessBatch(ctx); err != nil {
+			log.Printf("health: cannot process batch: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessBatch checks one batch of due links, at most concurrency at a time, and returns how many were handled
+func (worker *Worker) ProcessBatch(ctx context.Context) (int, error) {
+	links, err := worker.store.GetLinksDueHealthCheck(ctx, db.GetLinksDueHealthCheckParams{
+		CheckedBefore: pgtype.Timestamptz{Time: time.Now().Add(-worker.recheckAfter), Valid: true},
+		Limit:         worker.batchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	var firstErr error
+
+	slots := make(chan struct{}, worker.concurrency)
+
+	for _, link := range links {
+		slots <- struct{}{}
+		wg.Add(1)
+
+		go func(link db.Link) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			if err := worker.check(ctx, link); err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(link)
+	}
+
+	wg.Wait()
+
+	if firstErr != nil {
+		return 0, firstErr
+	}
+	return len(links), nil
+}
+
+func (worker *Worker) check(ctx context.Context, link db.Link) error {
+	result := worker.checker.Check(ctx, link.Link)
+
+	// A check cut short by shutdown says nothing about the destination
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+
+	arg := db.UpdateLinkHealthParams{
+		ID:              link.ID,
+		HealthStatus:    int32(result.StatusCode),
+		HealthLatencyMs: int32(result.Latency.Milliseconds()),
+		HealthBroken:    result.Broken(),
+	}
+	if result.Err != nil {
+		arg.HealthError = truncate(result.Err.Error(), maxErrorLength)
+	}
+
+	txArg := db.UpdateLinkHealthTxParams{UpdateLinkHealthParams: arg}
+
+	turnedBroken := arg.HealthBroken && !link.HealthBroken
+	if turnedBroken {
+		link.HealthStatus = arg.HealthStatus
+		link.HealthError = arg.HealthError
+
+		deliveries, err := webhook.NewDeliveries(link.UserID, webhook.EventLinkUnhealthy, webhook.NewLinkData(link))
+		if err != nil {
+			return err
+		}
+		txArg.Deliveries = &deliveries
+	}
+
+	if _, err := worker.store.UpdateLinkHealthTx(ctx, txArg); err != nil {
+		return err
+	}
+
+	if turnedBroken {
+		log.Printf("health: link %d is broken: status %d %s", link.ID, arg.HealthStatus, arg.HealthError)
+	}
+	return nil
+}
+
+func truncate(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) > length {
+		return string(runes[:length])
+	}
+	return value
+}
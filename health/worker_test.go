@@ -0,0 +1,134 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/webhook"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWorkerProcessBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	links := []db.Link{
+		{ID: 1, UserID: 7, Link: server.URL + "/page"},
+		{ID: 2, UserID: 7, Link: server.URL + "/gone"},
+	}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		check      func(t *testing.T, n int, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLinksDueHealthCheck(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.GetLinksDueHealthCheckParams) ([]db.Link, error) {
+						require.Equal(t, int32(DefaultBatchSize), arg.Limit)
+						require.WithinDuration(t, time.Now().Add(-DefaultRecheckAfter), arg.CheckedBefore.Time, time.Minute)
+						return links, nil
+					})
+
+				store.EXPECT().
+					UpdateLinkHealthTx(gomock.Any(), gomock.Any()).
+					Times(2).
+					DoAndReturn(func(_ any, arg db.UpdateLinkHealthTxParams) (db.Link, error) {
+						switch arg.ID {
+						case 1:
+							require.Equal(t, int32(http.StatusOK), arg.HealthStatus)
+							require.False(t, arg.HealthBroken)
+							require.Nil(t, arg.Deliveries)
+						case 2:
+							require.Equal(t, int32(http.StatusGone), arg.HealthStatus)
+							require.True(t, arg.HealthBroken)
+
+							// Only the link that turned broken is announced, in the same transaction as its update
+							require.NotNil(t, arg.Deliveries)
+							require.Equal(t, int64(7), arg.Deliveries.UserID)
+							require.Equal(t, webhook.EventLinkUnhealthy, arg.Deliveries.Event)
+						default:
+							t.Fatalf("unexpected link %d", arg.ID)
+						}
+						require.Empty(t, arg.HealthError)
+						return db.Link{ID: arg.ID}, nil
+					})
+
+				store.EXPECT().
+					CreateWebhookDeliveries(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			check: func(t *testing.T, n int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 2, n)
+			},
+		},
+		{
+			name: "ListError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLinksDueHealthCheck(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+
+				store.EXPECT().
+					UpdateLinkHealthTx(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			check: func(t *testing.T, n int, err error) {
+				require.Error(t, err)
+				require.Zero(t, n)
+			},
+		},
+		{
+			name: "UpdateError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLinksDueHealthCheck(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(links[:1], nil)
+
+				store.EXPECT().
+					UpdateLinkHealthTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.Link{}, sql.ErrConnDone)
+			},
+			check: func(t *testing.T, n int, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+				require.Zero(t, n)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			worker := NewWorker(store, newTestChecker(), 0)
+			n, err := worker.ProcessBatch(context.Background())
+			tc.check(t, n, err)
+		})
+	}
+}
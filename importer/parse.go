@@ -0,0 +1,261 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SourceBitly  = "bitly"
+	SourceYOURLS = "yourls"
+
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+var (
+	ErrUnknownSource = errors.New("unknown import source")
+	ErrUnknownFormat = errors.New("unknown import format")
+	ErrNoURLColumn   = errors.New("no destination url column found")
+)
+
+// Record is one link from another shortener's export
+type Record struct {
+	Code      string
+	URL       string
+	Title     string
+	CreatedAt time.Time
+	Clicks    int64
+}
+
+// fieldAliases maps our fields to the column or key names each shortener uses in its exports
+var fieldAliases = map[string]map[string][]string{
+	SourceBitly: {
+		"code":    {"bitlink", "link", "short_link", "short_url", "id"},
+		"url":     {"long_url", "destination", "url"},
+		"title":   {"title"},
+		"created": {"created", "created_at", "date_created", "creation_date"},
+		"clicks":  {"clicks", "total_clicks", "user_clicks"},
+	},
+	SourceYOURLS: {
+		"code":    {"keyword", "shorturl", "short_url"},
+		"url":     {"url", "long_url"},
+		"title":   {"title"},
+		"created": {"timestamp", "date", "created"},
+		"clicks":  {"clicks"},
+	},
+}
+
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05-0700",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// Parse reads an export into records, keeping the order of the file so a job can resume by position
+func Parse(source, format string, data []byte) ([]Record, error) {
+	aliases, ok := fieldAliases[source]
+	if !ok {
+		return nil, ErrUnknownSource
+	}
+
+	var rows []map[string]string
+	var err error
+
+	switch format {
+	case FormatCSV:
+		rows, err = readCSV(data)
+	case FormatJSON:
+		rows, err = readJSON(data)
+	default:
+		return nil, ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]Record, 0, len(rows))
+	for i, row := range rows {
+		if i == 0 && !hasAny(row, aliases["url"]) {
+			return nil, ErrNoURLColumn
+		}
+
+		record := Record{
+			Code:  shortCode(lookup(row, aliases["code"])),
+			URL:   lookup(row, aliases["url"]),
+			Title: lookup(row, aliases["title"]),
+		}
+		record.CreatedAt = parseTime(lookup(row, aliases["created"]))
+		record.Clicks, _ = strconv.ParseInt(lookup(row, aliases["clicks"]), 10, 64)
+
+		records = append(records, record)
+	}
+
+	return records, nil
+}
+
+func readCSV(data []byte) ([]map[string]string, error) {
+	reader := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read csv header: %w", err)
+	}
+	for i := range header {
+		header[i] = normalizeKey(header[i])
+	}
+
+	var rows []map[string]string
+	for {
+		values, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read csv: %w", err)
+		}
+
+		row := make(map[string]string, len(header))
+		for i, value := range values {
+			if i < len(header) {
+				row[header[i]] = strings.TrimSpace(value)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// readJSON accepts a plain array of links or an object holding them under "links",
+// either as an array (Bitly) or as an object keyed link_1, link_2, ... (YOURLS)
+func readJSON(data []byte) ([]map[string]string, error) {
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("cannot read json: %w", err)
+	}
+
+	if obj, ok := doc.(map[string]interface{}); ok {
+		doc = obj["links"]
+	}
+
+	var items []interface{}
+	switch links := doc.(type) {
+	case []interface{}:
+		items = links
+	case map[string]interface{}:
+		keys := make([]string, 0, len(links))
+		for key := range links {
+			keys = append(keys, key)
+		}
+		sort.Slice(keys, func(i, j int) bool {
+			return naturalLess(keys[i], keys[j])
+		})
+		for _, key := range keys {
+			items = append(items, links[key])
+		}
+	default:
+		return nil, errors.New("cannot find links in json")
+	}
+
+	rows := make([]map[string]string, 0, len(items))
+	for _, item := range items {
+		obj, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, errors.New("json link is not an object")
+		}
+
+		row := make(map[string]string, len(obj))
+		for key, value := range obj {
+			switch v := value.(type) {
+			case string:
+				row[normalizeKey(key)] = strings.TrimSpace(v)
+			case float64:
+				row[normalizeKey(key)] = strconv.FormatFloat(v, 'f', -1, 64)
+			}
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func normalizeKey(key string) string {
+	key = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(key, "\xef\xbb\xbf")))
+	return strings.ReplaceAll(key, " ", "_")
+}
+
+func lookup(row map[string]string, keys []string) string {
+	for _, key := range keys {
+		if value := row[key]; value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func hasAny(row map[string]string, keys []string) bool {
+	for _, key := range keys {
+		if _, ok := row[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// shortCode takes the code out of a full short link such as https://bit.ly/abc or bit.ly/abc
+func shortCode(value string) string {
+	if !strings.Contains(value, "/") {
+		return value
+	}
+
+	if !strings.Contains(value, "://") {
+		value = "https://" + value
+	}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		return ""
+	}
+	return strings.Trim(u.Path, "/")
+}
+
+func parseTime(value string) time.Time {
+	if value == "" {
+		return time.Time{}
+	}
+
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0).UTC()
+	}
+
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+var trailingNumber = regexp.MustCompile(`\d+$`)
+
+func naturalLess(a, b string) bool {
+	na, errA := strconv.Atoi(trailingNumber.FindString(a))
+	nb, errB := strconv.Atoi(trailingNumber.FindString(b))
+	if errA == nil && errB == nil && na != nb {
+		return na < nb
+	}
+	return a < b
+}
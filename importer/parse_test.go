@@ -0,0 +1,113 @@
+package importer
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	created := time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC)
+
+	testCases := []struct {
+		name    string
+		source  string
+		format  string
+		data    string
+		records []Record
+		err     error
+	}{
+		{
+			name:   "BitlyCSV",
+			source: SourceBitly,
+			format: FormatCSV,
+			data: "\xef\xbb\xbfBitlink,Long URL,Title,Created,Clicks\n" +
+				"bit.ly/abcDEF,https://example.com/a,First,2023-04-05T06:07:08+0000,12\n" +
+				"https://bit.ly/xyz,https://example.com/b,,,\n",
+			records: []Record{
+				{Code: "abcDEF", URL: "https://example.com/a", Title: "First", CreatedAt: created, Clicks: 12},
+				{Code: "xyz", URL: "https://example.com/b"},
+			},
+		},
+		{
+			name:   "BitlyJSON",
+			source: SourceBitly,
+			format: FormatJSON,
+			data:   `{"links": [{"id": "bit.ly/abc", "long_url": "https://example.com", "created_at": "2023-04-05T06:07:08+0000"}]}`,
+			records: []Record{
+				{Code: "abc", URL: "https://example.com", CreatedAt: created},
+			},
+		},
+		{
+			name:   "YOURLSCSV",
+			source: SourceYOURLS,
+			format: FormatCSV,
+			data:   "keyword,url,title,timestamp,clicks\nhello,https://example.com,Hi,2023-04-05 06:07:08,3\n",
+			records: []Record{
+				{Code: "hello", URL: "https://example.com", Title: "Hi", CreatedAt: created, Clicks: 3},
+			},
+		},
+		{
+			name:   "YOURLSJSON",
+			source: SourceYOURLS,
+			format: FormatJSON,
+			data: `{"links": {
+				"link_10": {"shorturl": "https://sho.rt/ten", "url": "https://example.com/10", "clicks": "5"},
+				"link_2": {"shorturl": "https://sho.rt/two", "url": "https://example.com/2", "timestamp": "1680674828"}
+			}}`,
+			records: []Record{
+				{Code: "two", URL: "https://example.com/2", CreatedAt: created},
+				{Code: "ten", URL: "https://example.com/10", Clicks: 5},
+			},
+		},
+		{
+			name:   "NoURLColumn",
+			source: SourceBitly,
+			format: FormatCSV,
+			data:   "bitlink,title\nbit.ly/abc,First\n",
+			err:    ErrNoURLColumn,
+		},
+		{
+			name:   "UnknownSource",
+			source: "tinyurl",
+			format: FormatCSV,
+			err:    ErrUnknownSource,
+		},
+		{
+			name:   "UnknownFormat",
+			source: SourceBitly,
+			format: "xml",
+			err:    ErrUnknownFormat,
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			records, err := Parse(tc.source, tc.format, []byte(tc.data))
+			if tc.err != nil {
+				require.ErrorIs(t, err, tc.err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, records, len(tc.records))
+			for i, record := range records {
+				require.Equal(t, tc.records[i].Code, record.Code)
+				require.Equal(t, tc.records[i].URL, record.URL)
+				require.Equal(t, tc.records[i].Title, record.Title)
+				require.Equal(t, tc.records[i].Clicks, record.Clicks)
+				require.True(t, tc.records[i].CreatedAt.Equal(record.CreatedAt))
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := Parse(SourceBitly, FormatJSON, []byte(`{"links": "nope"}`))
+	require.Error(t, err)
+
+	_, err = Parse(SourceBitly, FormatCSV, []byte(""))
+	require.Error(t, err)
+}
@@ -0,0 +1,184 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/utm"
+	"github.com/jackc/pgx/v5/pgtype"
+	"log"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+const (
+	DefaultInterval  = 10 * time.Second
+	DefaultBatchSize = 100
+	// DefaultLease is how long a claimed job stays with its worker without progress before another may take it over
+	DefaultLease = 5 * time.Minute
+
+	StatusPending   = "pending"
+	StatusRunning   = "running"
+	StatusCompleted = "completed"
+	StatusFailed    = "failed"
+
+	ConflictInvalidCode = "code format not supported"
+	ConflictInvalidURL  = "destination is not a valid http(s) url"
+)
+
+// validCode mirrors the codes GetLinkByCode can serve
+var validCode = regexp.MustCompile(`^[A-Za-z]+$`)
+
+// Worker runs pending import jobs and picks up jobs that were interrupted where they stopped
+type Worker struct {
+	store     db.Store
+	interval  time.Duration
+	batchSize int
+	lease     time.Duration
+}
+
+func NewWorker(store db.Store, interval time.Duration) *Worker {
+	if interval == 0 {
+		interval = DefaultInterval
+	}
+
+	return &Worker{
+		store:     store,
+		interval:  interval,
+		batchSize: DefaultBatchSize,
+		lease:     DefaultLease,
+	}
+}
+
+// Run processes unfinished jobs until ctx is cancelled
+func (worker *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(worker.interval)
+	defer ticker.Stop()
+
+	for {
+		worker.runClaimedJobs(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// runClaimedJobs claims unfinished jobs one at a time until there are none left that another worker does not hold
+func (worker *Worker) runClaimedJobs(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := worker.claim(ctx, pgtype.Int8{})
+		if err != nil {
+			if !errors.Is(err, db.ErrRecordNotFound) {
+				log.Printf("import: cannot claim a job: %v", err)
+			}
+			return
+		}
+
+		if _, err := worker.RunJob(ctx, job, nil); err != nil {
+			log.Printf("import: job %d stopped: %v", job.ID, err)
+		}
+	}
+}
+
+// ClaimJob takes the job with the given id for this worker. It fails with db.ErrRecordNotFound
+// when the job is finished or another worker holds it.
+func (worker *Worker) ClaimJob(ctx context.Context, id int64) (db.ImportJob, error) {
+	return worker.claim(ctx, pgtype.Int8{Int64: id, Valid: true})
+}
+
+func (worker *Worker) claim(ctx context.Context, id pgtype.Int8) (db.ImportJob, error) {
+	return worker.store.ClaimImportJob(ctx, db.ClaimImportJobParams{
+		LeaseSeconds: worker.leaseSeconds(),
+		ID:           id,
+	})
+}
+
+func (worker *Worker) leaseSeconds() int64 {
+	return int64(worker.lease / time.Second)
+}
+
+// RunJob imports the records of job that have not been processed yet, calling progress after every batch.
+// The worker must hold the job's claim, which each batch renews.
+func (worker *Worker) RunJob(ctx context.Context, job db.ImportJob, progress func(db.ImportJob)) (db.ImportJob, error) {
+	records, err := Parse(job.Source, job.Format, job.Payload)
+	if err != nil {
+		return worker.store.FinishImportJob(ctx, db.FinishImportJobParams{
+			ID:     job.ID,
+			Status: StatusFailed,
+			Error:  err.Error(),
+		})
+	}
+
+	if job.Status == StatusPending {
+		job, err = worker.store.StartImportJob(ctx, db.StartImportJobParams{
+			ID:    job.ID,
+			Total: int64(len(records)),
+		})
+		if err != nil {
+			return job, err
+		}
+	}
+
+	for start := int(job.Processed); start < len(records); start += worker.batchSize {
+		if err := ctx.Err(); err != nil {
+			return job, err
+		}
+
+		end := min(start+worker.batchSize, len(records))
+
+		arg := db.ImportLinksTxParams{
+			JobID:        job.ID,
+			UserID:       job.UserID,
+			Records:      make([]db.ImportLinkRecord, 0, end-start),
+			Start:        int64(start),
+			LeaseSeconds: worker.leaseSeconds(),
+		}
+		for i := start; i < end; i++ {
+			arg.Records = append(arg.Records, newImportRecord(int64(i+1), records[i]))
+		}
+
+		job, err = worker.store.ImportLinksTx(ctx, arg)
+		if err != nil {
+			return job, fmt.Errorf("cannot import batch at line %d: %w", start+1, err)
+		}
+
+		if progress != nil {
+			progress(job)
+		}
+	}
+
+	return worker.store.FinishImportJob(ctx, db.FinishImportJobParams{
+		ID:     job.ID,
+		Status: StatusCompleted,
+	})
+}
+
+// newImportRecord flags records whose code we cannot keep and skips those without a usable destination
+func newImportRecord(line int64, record Record) db.ImportLinkRecord {
+	result := db.ImportLinkRecord{
+		Line:        line,
+		Code:        record.Code,
+		Link:        record.URL,
+		Title:       record.Title,
+		Clicks:      record.Clicks,
+		UtmCampaign: utm.Campaign(record.URL),
+	}
+
+	if !record.CreatedAt.IsZero() {
+		result.CreatedAt = pgtype.Timestamptz{Time: record.CreatedAt, Valid: true}
+	}
+
+	if u, err := url.Parse(record.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		result.Conflict = ConflictInvalidURL
+		result.Skip = true
+	} else if !validCode.MatchString(record.Code) {
+		result.Conflict = ConflictInvalidCode
+	}
+
+	return result
+}
@@ -0,0 +1,282 @@
+package importer
+
+import (
+	"context"
+	"database/sql"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+const testExport = "bitlink,long_url\n" +
+	"bit.ly/first,https://example.com/1\n" +
+	"bit.ly/2nd,https://example.com/2\n" +
+	"bit.ly/third,not a url\n"
+
+func TestWorkerRunJob(t *testing.T) {
+	pending := db.ImportJob{
+		ID:      1,
+		UserID:  2,
+		Source:  SourceBitly,
+		Format:  FormatCSV,
+		Payload: []byte(testExport),
+		Status:  StatusPending,
+	}
+
+	testCases := []struct {
+		name       string
+		job        db.ImportJob
+		buildStubs func(store *mockdb.MockStore)
+		check      func(t *testing.T, job db.ImportJob, batches int, err error)
+	}{
+		{
+			name: "OK",
+			job:  pending,
+			buildStubs: func(store *mockdb.MockStore) {
+				started := pending
+				started.Status = StatusRunning
+				started.Total = 3
+
+				store.EXPECT().
+					StartImportJob(gomock.Any(), gomock.Eq(db.StartImportJobParams{ID: pending.ID, Total: 3})).
+					Times(1).
+					Return(started, nil)
+
+				first := started
+				first.Processed = 2
+
+				store.EXPECT().
+					ImportLinksTx(gomock.Any(), gomock.Eq(db.ImportLinksTxParams{
+						JobID:        pending.ID,
+						UserID:       pending.UserID,
+						LeaseSeconds: int64(DefaultLease / time.Second),
+						Records: []db.ImportLinkRecord{
+							{Line: 1, Code: "first", Link: "https://example.com/1"},
+							{Line: 2, Code: "2nd", Link: "https://example.com/2", Conflict: ConflictInvalidCode},
+						},
+					})).
+					Times(1).
+					Return(first, nil)
+
+				second := first
+				second.Processed = 3
+
+				store.EXPECT().
+					ImportLinksTx(gomock.Any(), gomock.Eq(db.ImportLinksTxParams{
+						JobID:        pending.ID,
+						UserID:       pending.UserID,
+						LeaseSeconds: int64(DefaultLease / time.Second),
+						Start:        2,
+						Records: []db.ImportLinkRecord{
+							{Line: 3, Code: "third", Link: "not a url", Conflict: ConflictInvalidURL, Skip: true},
+						},
+					})).
+					Times(1).
+					Return(second, nil)
+
+				finished := second
+				finished.Status = StatusCompleted
+
+				store.EXPECT().
+					FinishImportJob(gomock.Any(), gomock.Eq(db.FinishImportJobParams{ID: pending.ID, Status: StatusCompleted})).
+					Times(1).
+					Return(finished, nil)
+			},
+			check: func(t *testing.T, job db.ImportJob, batches int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, StatusCompleted, job.Status)
+				require.Equal(t, 2, batches)
+			},
+		},
+		{
+			name: "Resume",
+			job: func() db.ImportJob {
+				job := pending
+				job.Status = StatusRunning
+				job.Total = 3
+				job.Processed = 2
+				return job
+			}(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					StartImportJob(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				store.EXPECT().
+					ImportLinksTx(gomock.Any(), gomock.Eq(db.ImportLinksTxParams{
+						JobID:        pending.ID,
+						UserID:       pending.UserID,
+						LeaseSeconds: int64(DefaultLease / time.Second),
+						Start:        2,
+						Records: []db.ImportLinkRecord{
+							{Line: 3, Code: "third", Link: "not a url", Conflict: ConflictInvalidURL, Skip: true},
+						},
+					})).
+					Times(1).
+					Return(db.ImportJob{ID: pending.ID, Processed: 3}, nil)
+
+				store.EXPECT().
+					FinishImportJob(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ImportJob{Status: StatusCompleted}, nil)
+			},
+			check: func(t *testing.T, job db.ImportJob, batches int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 1, batches)
+			},
+		},
+		{
+			name: "ParseError",
+			job: func() db.ImportJob {
+				job := pending
+				job.Payload = []byte("bitlink,title\nbit.ly/abc,First\n")
+				return job
+			}(),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					ImportLinksTx(gomock.Any(), gomock.Any()).
+					Times(0)
+
+				store.EXPECT().
+					FinishImportJob(gomock.Any(), gomock.Eq(db.FinishImportJobParams{
+						ID:     pending.ID,
+						Status: StatusFailed,
+						Error:  ErrNoURLColumn.Error(),
+					})).
+					Times(1).
+					Return(db.ImportJob{Status: StatusFailed}, nil)
+			},
+			check: func(t *testing.T, job db.ImportJob, batches int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, StatusFailed, job.Status)
+				require.Zero(t, batches)
+			},
+		},
+		{
+			name: "BatchError",
+			job:  pending,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					StartImportJob(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ImportJob{ID: pending.ID, Status: StatusRunning}, nil)
+
+				store.EXPECT().
+					ImportLinksTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ImportJob{}, sql.ErrConnDone)
+
+				// The job stays running so the worker picks it up again
+				store.EXPECT().
+					FinishImportJob(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			check: func(t *testing.T, job db.ImportJob, batches int, err error) {
+				require.ErrorIs(t, err, sql.ErrConnDone)
+				require.Zero(t, batches)
+			},
+		},
+		{
+			name: "TakenOver",
+			job:  pending,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					StartImportJob(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ImportJob{ID: pending.ID, Status: StatusRunning}, nil)
+
+				store.EXPECT().
+					ImportLinksTx(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.ImportJob{}, db.ErrStaleImportBatch)
+
+				// The worker that took the job over finishes it
+				store.EXPECT().
+					FinishImportJob(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			check: func(t *testing.T, job db.ImportJob, batches int, err error) {
+				require.ErrorIs(t, err, db.ErrStaleImportBatch)
+				require.Zero(t, batches)
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			worker := NewWorker(store, 0)
+			worker.batchSize = 2
+
+			var batches int
+			job, err := worker.RunJob(context.Background(), tc.job, func(db.ImportJob) {
+				batches++
+			})
+			tc.check(t, job, batches, err)
+		})
+	}
+}
+
+func TestWorkerRunClaimedJobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	claimed := db.ImportJob{
+		ID:      1,
+		UserID:  2,
+		Source:  SourceBitly,
+		Format:  FormatCSV,
+		Payload: []byte("bitlink,long_url\n"),
+		Status:  StatusRunning,
+	}
+
+	arg := db.ClaimImportJobParams{LeaseSeconds: int64(DefaultLease / time.Second)}
+
+	gomock.InOrder(
+		store.EXPECT().
+			ClaimImportJob(gomock.Any(), gomock.Eq(arg)).
+			Times(1).
+			Return(claimed, nil),
+		store.EXPECT().
+			FinishImportJob(gomock.Any(), gomock.Eq(db.FinishImportJobParams{ID: claimed.ID, Status: StatusCompleted})).
+			Times(1).
+			Return(claimed, nil),
+		// Jobs held by other workers are not returned, so the worker stops once nothing is left to claim
+		store.EXPECT().
+			ClaimImportJob(gomock.Any(), gomock.Eq(arg)).
+			Times(1).
+			Return(db.ImportJob{}, db.ErrRecordNotFound),
+	)
+
+	NewWorker(store, 0).runClaimedJobs(context.Background())
+}
+
+func TestWorkerClaimJob(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().
+		ClaimImportJob(gomock.Any(), gomock.Eq(db.ClaimImportJobParams{
+			LeaseSeconds: int64(DefaultLease / time.Second),
+			ID:           pgtype.Int8{Int64: 5, Valid: true},
+		})).
+		Times(1).
+		Return(db.ImportJob{}, db.ErrRecordNotFound)
+
+	_, err := NewWorker(store, 0).ClaimJob(context.Background(), 5)
+	require.ErrorIs(t, err, db.ErrRecordNotFound)
+}
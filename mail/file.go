@@ -0,0 +1,42 @@
+package mail
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileMailer appends every email to a file instead of sending it.
+// It is meant for local development, where the links in the emails still need to be followed.
+type FileMailer struct {
+	path string
+	from string
+	mu   sync.Mutex
+}
+
+func NewFileMailer(path, from string) *FileMailer {
+	return &FileMailer{path: path, from: from}
+}
+
+func (m *FileMailer) Send(ctx context.Context, msg Message) error {
+	data, err := format(m.from, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	file, err := os.OpenFile(m.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := file.Write(append(data, "\r\n\r\n"...)); err != nil {
+		file.Close()
+		return err
+	}
+
+	return file.Close()
+}
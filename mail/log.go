@@ -0,0 +1,27 @@
+package mail
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// LogMailer prints every email to the log instead of sending it.
+// The emails carry live tokens, so it must only be used in development.
+type LogMailer struct {
+	from string
+}
+
+func NewLogMailer(from string) *LogMailer {
+	return &LogMailer{from: from}
+}
+
+func (m *LogMailer) Send(ctx context.Context, msg Message) error {
+	data, err := format(m.from, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	log.Printf("mail to %s:\n%s", msg.To, data)
+	return nil
+}
@@ -0,0 +1,48 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidHeader = errors.New("mail: header values must not contain line breaks")
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers emails; SMTPMailer sends them for real and FileMailer keeps them for local testing
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// format renders a message as RFC 5322 text, refusing header values that could inject extra headers
+func format(from string, msg Message, date time.Time) ([]byte, error) {
+	for _, value := range []string{from, msg.To, msg.Subject} {
+		if strings.ContainsAny(value, "\r\n") {
+			return nil, ErrInvalidHeader
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from)
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", msg.Subject)
+	fmt.Fprintf(&buf, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("\r\n")
+
+	// SMTP wants CRLF line endings throughout the body as well
+	body := strings.ReplaceAll(msg.Body, "\r\n", "\n")
+	buf.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+
+	return buf.Bytes(), nil
+}
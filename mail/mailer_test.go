@@ -0,0 +1,57 @@
+package mail
+
+import (
+	"context"
+	"github.com/stretchr/testify/require"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFormat(t *testing.T) {
+	date := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	data, err := format("noreply@example.com", Message{
+		To:      "ada@example.com",
+		Subject: "Verify your email",
+		Body:    "Hello\nthere",
+	}, date)
+	require.NoError(t, err)
+
+	text := string(data)
+	require.True(t, strings.HasPrefix(text, "From: noreply@example.com\r\nTo: ada@example.com\r\nSubject: Verify your email\r\n"))
+	require.Contains(t, text, "Date: Fri, 01 Mar 2024 12:00:00 +0000\r\n")
+	require.True(t, strings.HasSuffix(text, "\r\n\r\nHello\r\nthere"))
+}
+
+func TestFormatRejectsHeaderInjection(t *testing.T) {
+	testCases := []Message{
+		{To: "ada@example.com\r\nBcc: eve@example.com", Subject: "Hi"},
+		{To: "ada@example.com", Subject: "Hi\nBcc: eve@example.com"},
+	}
+
+	for _, msg := range testCases {
+		_, err := format("noreply@example.com", msg, time.Now())
+		require.ErrorIs(t, err, ErrInvalidHeader)
+	}
+}
+
+func TestFileMailer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail.log")
+	mailer := NewFileMailer(path, "noreply@example.com")
+
+	require.NoError(t, mailer.Send(context.Background(), Message{To: "ada@example.com", Subject: "First", Body: "one"}))
+	require.NoError(t, mailer.Send(context.Background(), Message{To: "bob@example.com", Subject: "Second", Body: "two"}))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Contains(t, string(data), "To: ada@example.com")
+	require.Contains(t, string(data), "Subject: Second")
+}
+
+func TestLogMailer(t *testing.T) {
+	require.NoError(t, NewLogMailer("noreply@example.com").Send(context.Background(), Message{To: "ada@example.com"}))
+	require.Error(t, NewLogMailer("noreply@example.com").Send(context.Background(), Message{To: "ada@example.com\r\nBcc: eve@example.com"}))
+}
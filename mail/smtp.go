@@ -0,0 +1,98 @@
+package mail
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+const DefaultTimeout = 10 * time.Second
+
+type SMTPOptions struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	Timeout  time.Duration
+}
+
+// SMTPMailer sends emails through an SMTP relay, upgrading to TLS whenever the server offers it
+type SMTPMailer struct {
+	opts SMTPOptions
+}
+
+func NewSMTPMailer(opts SMTPOptions) *SMTPMailer {
+	if opts.Port == 0 {
+		opts.Port = 587
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+
+	return &SMTPMailer{opts: opts}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, msg Message) error {
+	data, err := format(m.opts.From, msg, time.Now())
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, m.opts.Timeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(m.opts.Host, strconv.Itoa(m.opts.Port)))
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		return err
+	}
+
+	client, err := smtp.NewClient(conn, m.opts.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: m.opts.Host}); err != nil {
+			return err
+		}
+	}
+
+	// smtp.PlainAuth refuses to send credentials over an unencrypted connection to a remote host
+	if m.opts.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", m.opts.Username, m.opts.Password, m.opts.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(m.opts.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
@@ -4,7 +4,11 @@ import (
 	"context"
 	"github.com/bolusarz/urlmini/api"
 	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/health"
+	"github.com/bolusarz/urlmini/importer"
+	"github.com/bolusarz/urlmini/metadata"
 	"github.com/bolusarz/urlmini/util"
+	"github.com/bolusarz/urlmini/webhook"
 	"github.com/jackc/pgx/v5/pgxpool"
 	"log"
 )
@@ -22,6 +26,15 @@ func main() {
 	}
 
 	store := db.NewStore(conn)
+
+	fetcher := metadata.NewFetcher(metadata.Options{})
+	go metadata.NewWorker(store, fetcher, config.MetadataFetchInterval).Run(context.Background())
+	go importer.NewWorker(store, 0).Run(context.Background())
+
+	checker := health.NewChecker(health.Options{})
+	go health.NewWorker(store, checker, config.HealthCheckInterval).Run(context.Background())
+	go webhook.NewDispatcher(store, config.WebhookInterval, webhook.Options{}).Run(context.Background())
+
 	server, err := api.NewServer(store, config)
 	if err != nil {
 		log.Fatalf("cannot create server: %v", err)
@@ -0,0 +1,292 @@
+package metadata
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"golang.org/x/net/html"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	DefaultTimeout      = 10 * time.Second
+	DefaultMaxBodySize  = 512 * 1024
+	DefaultMaxRedirects = 5
+	DefaultMaxIdleConns = 10
+
+	maxTitleLength       = 300
+	maxDescriptionLength = 1000
+)
+
+var (
+	ErrUnsupportedScheme  = errors.New("metadata: unsupported url scheme")
+	ErrForbiddenAddress   = errors.New("metadata: destination resolves to a forbidden address")
+	ErrTooManyRedirects   = errors.New("metadata: too many redirects")
+	ErrUnexpectedStatus   = errors.New("metadata: unexpected response status")
+	ErrUnsupportedContent = errors.New("metadata: destination is not an html page")
+)
+
+// Metadata is the information shown alongside a link in listings
+type Metadata struct {
+	Title       string
+	Description string
+	Favicon     string
+}
+
+type Options struct {
+	Timeout      time.Duration
+	MaxBodySize  int64
+	MaxRedirects int
+	// AllowPrivateNetworks is handed to NewClient
+	AllowPrivateNetworks bool
+}
+
+// Fetcher retrieves page metadata from link destinations
+type Fetcher struct {
+	client      *http.Client
+	maxBodySize int64
+}
+
+func NewFetcher(opts Options) *Fetcher {
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxBodySize == 0 {
+		opts.MaxBodySize = DefaultMaxBodySize
+	}
+	if opts.MaxRedirects == 0 {
+		opts.MaxRedirects = DefaultMaxRedirects
+	}
+
+	client := NewClient(ClientOptions{
+		Timeout: opts.Timeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= opts.MaxRedirects {
+				return ErrTooManyRedirects
+			}
+			return checkScheme(req.URL)
+		},
+		AllowPrivateNetworks: opts.AllowPrivateNetworks,
+	})
+
+	return &Fetcher{
+		client:      client,
+		maxBodySize: opts.MaxBodySize,
+	}
+}
+
+// Fetch downloads the destination page and extracts its title, description and favicon
+func (fetcher *Fetcher) Fetch(ctx context.Context, destination string) (Metadata, error) {
+	target, err := url.Parse(destination)
+	if err != nil {
+		return Metadata{}, fmt.Errorf("metadata: invalid url: %w", err)
+	}
+	if err := checkScheme(target); err != nil {
+		return Metadata{}, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return Metadata{}, err
+	}
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+	req.Header.Set("User-Agent", "urlmini-metadata/1.0")
+
+	resp, err := fetcher.client.Do(req)
+	if err != nil {
+		return Metadata{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return Metadata{}, fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if mediaType != "text/html" && mediaType != "application/xhtml+xml" {
+		return Metadata{}, ErrUnsupportedContent
+	}
+
+	body := io.LimitReader(resp.Body, fetcher.maxBodySize)
+	return parse(body, resp.Request.URL)
+}
+
+func parse(body io.Reader, base *url.URL) (Metadata, error) {
+	var title, ogTitle, ogDescription, description, favicon string
+
+	tokenizer := html.NewTokenizer(body)
+	inTitle := false
+
+loop:
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			// A body cut off by the size cap still yields whatever was read before it
+			if err := tokenizer.Err(); err != io.EOF && title == "" && ogTitle == "" {
+				return Metadata{}, err
+			}
+			break loop
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := tokenizer.Token()
+			switch token.Data {
+			case "title":
+				inTitle = title == ""
+			case "meta":
+				content := attr(token, "content")
+				switch {
+				case strings.EqualFold(attr(token, "property"), "og:title"):
+					ogTitle = content
+				case strings.EqualFold(attr(token, "property"), "og:description"):
+					ogDescription = content
+				case strings.EqualFold(attr(token, "name"), "description"):
+					description = content
+				}
+			case "link":
+				for _, rel := range strings.Fields(strings.ToLower(attr(token, "rel"))) {
+					if rel == "icon" && favicon == "" {
+						favicon = attr(token, "href")
+					}
+				}
+			}
+		case html.TextToken:
+			if inTitle {
+				title += string(tokenizer.Text())
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			switch string(name) {
+			case "title":
+				inTitle = false
+			case "head":
+				// Everything we need lives in <head>, skip the rest of the document
+				break loop
+			}
+		}
+	}
+
+	if title == "" {
+		title = ogTitle
+	}
+	if ogDescription != "" {
+		description = ogDescription
+	}
+	if favicon == "" {
+		favicon = "/favicon.ico"
+	}
+
+	return Metadata{
+		Title:       truncate(title, maxTitleLength),
+		Description: truncate(description, maxDescriptionLength),
+		Favicon:     resolve(base, favicon),
+	}, nil
+}
+
+func attr(token html.Token, key string) string {
+	for _, a := range token.Attr {
+		if strings.EqualFold(a.Key, key) {
+			return strings.TrimSpace(a.Val)
+		}
+	}
+	return ""
+}
+
+func resolve(base *url.URL, ref string) string {
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return ""
+	}
+	resolved := base.ResolveReference(parsed)
+	if resolved.Scheme != "http" && resolved.Scheme != "https" {
+		return ""
+	}
+	return resolved.String()
+}
+
+func truncate(value string, length int) string {
+	value = strings.Join(strings.Fields(value), " ")
+	runes := []rune(value)
+	if len(runes) > length {
+		return string(runes[:length])
+	}
+	return value
+}
+
+func checkScheme(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return ErrUnsupportedScheme
+	}
+	return nil
+}
+
+type ClientOptions struct {
+	Timeout time.Duration
+	// MaxIdleConns defaults to DefaultMaxIdleConns
+	MaxIdleConns  int
+	CheckRedirect func(req *http.Request, via []*http.Request) error
+	// AllowPrivateNetworks disables the SSRF guard; it only exists so tests can reach httptest servers
+	AllowPrivateNetworks bool
+}
+
+// NewClient returns a client for requesting urls that users supply. GuardAddress keeps its connections,
+// redirects included, on the public internet, and the timeout bounds every step of a request.
+func NewClient(opts ClientOptions) *http.Client {
+	if opts.MaxIdleConns == 0 {
+		opts.MaxIdleConns = DefaultMaxIdleConns
+	}
+
+	dialer := &net.Dialer{Timeout: opts.Timeout}
+	if !opts.AllowPrivateNetworks {
+		dialer.Control = GuardAddress
+	}
+
+	return &http.Client{
+		Transport: &http.Transport{
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			TLSHandshakeTimeout:   opts.Timeout,
+			ResponseHeaderTimeout: opts.Timeout,
+			MaxIdleConns:          opts.MaxIdleConns,
+			IdleConnTimeout:       30 * time.Second,
+		},
+		Timeout:       opts.Timeout,
+		CheckRedirect: opts.CheckRedirect,
+	}
+}
+
+// GuardAddress runs after DNS resolution so redirects and rebinding cannot reach internal hosts
+func GuardAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublicIP(ip) {
+		return ErrForbiddenAddress
+	}
+	return nil
+}
+
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0), Mask: net.CIDRMask(10, 32)}
+
+// IsPublicIP reports whether ip is routable on the public internet
+func IsPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	if ip4 := ip.To4(); ip4 != nil {
+		if ip4[0] == 0 || sharedAddressSpace.Contains(ip4) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,200 @@
+package metadata
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/require"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const testPage = `<!doctype html>
+<html>
+<head>
+	<title>  Example
+	Page </title>
+	<meta name="description" content="plain description">
+	<meta property="og:description" content="open graph description">
+	<link rel="shortcut icon" href="/static/icon.png">
+</head>
+<body><title>not this one</title></body>
+</html>`
+
+func newTestFetcher() *Fetcher {
+	return NewFetcher(Options{AllowPrivateNetworks: true, Timeout: time.Second})
+}
+
+func TestFetch(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/page", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, testPage)
+	})
+	mux.HandleFunc("/og-only", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<head><meta property="og:title" content="OG Title"></head>`)
+	})
+	mux.HandleFunc("/redirect", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/page", http.StatusFound)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/loop", http.StatusFound)
+	})
+	mux.HandleFunc("/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{}`)
+	})
+	mux.HandleFunc("/missing", func(w http.ResponseWriter, r *http.Request) {
+		http.NotFound(w, r)
+	})
+	mux.HandleFunc("/huge", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, "<html><head><title>Huge</title>")
+		fmt.Fprint(w, strings.Repeat("<meta name=x content=y>", DefaultMaxBodySize))
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * time.Second)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	testCases := []struct {
+		name  string
+		path  string
+		check func(t *testing.T, meta Metadata, err error)
+	}{
+		{
+			name: "OK",
+			path: "/page",
+			check: func(t *testing.T, meta Metadata, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "Example Page", meta.Title)
+				require.Equal(t, "open graph description", meta.Description)
+				require.Equal(t, server.URL+"/static/icon.png", meta.Favicon)
+			},
+		},
+		{
+			name: "FallsBackToOpenGraphTitle",
+			path: "/og-only",
+			check: func(t *testing.T, meta Metadata, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "OG Title", meta.Title)
+				require.Equal(t, server.URL+"/favicon.ico", meta.Favicon)
+			},
+		},
+		{
+			name: "FollowsRedirects",
+			path: "/redirect",
+			check: func(t *testing.T, meta Metadata, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "Example Page", meta.Title)
+			},
+		},
+		{
+			name: "TooManyRedirects",
+			path: "/loop",
+			check: func(t *testing.T, meta Metadata, err error) {
+				require.ErrorIs(t, err, ErrTooManyRedirects)
+			},
+		},
+		{
+			name: "NotHTML",
+			path: "/json",
+			check: func(t *testing.T, meta Metadata, err error) {
+				require.ErrorIs(t, err, ErrUnsupportedContent)
+			},
+		},
+		{
+			name: "NotFound",
+			path: "/missing",
+			check: func(t *testing.T, meta Metadata, err error) {
+				require.ErrorIs(t, err, ErrUnexpectedStatus)
+			},
+		},
+		{
+			name: "SizeCapped",
+			path: "/huge",
+			check: func(t *testing.T, meta Metadata, err error) {
+				require.NoError(t, err)
+				require.Equal(t, "Huge", meta.Title)
+			},
+		},
+		{
+			name: "Timeout",
+			path: "/slow",
+			check: func(t *testing.T, meta Metadata, err error) {
+				require.Error(t, err)
+			},
+		},
+	}
+
+	fetcher := newTestFetcher()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			meta, err := fetcher.Fetch(context.Background(), server.URL+tc.path)
+			tc.check(t, meta, err)
+		})
+	}
+}
+
+func TestFetchBlocksPrivateNetworks(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, testPage)
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(Options{})
+
+	_, err := fetcher.Fetch(context.Background(), server.URL)
+	require.ErrorIs(t, err, ErrForbiddenAddress)
+
+	_, err = fetcher.Fetch(context.Background(), "file:///etc/passwd")
+	require.ErrorIs(t, err, ErrUnsupportedScheme)
+}
+
+func TestNewClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	_, err := NewClient(ClientOptions{Timeout: time.Second}).Get(server.URL)
+	require.ErrorIs(t, err, ErrForbiddenAddress)
+
+	rsp, err := NewClient(ClientOptions{Timeout: time.Second, AllowPrivateNetworks: true}).Get(server.URL)
+	require.NoError(t, err)
+	rsp.Body.Close()
+	require.Equal(t, http.StatusNoContent, rsp.StatusCode)
+}
+
+func TestIsPublicIP(t *testing.T) {
+	testCases := []struct {
+		ip     string
+		public bool
+	}{
+		{ip: "8.8.8.8", public: true},
+		{ip: "2606:4700:4700::1111", public: true},
+		{ip: "127.0.0.1", public: false},
+		{ip: "10.1.2.3", public: false},
+		{ip: "172.16.0.1", public: false},
+		{ip: "192.168.1.1", public: false},
+		{ip: "169.254.169.254", public: false},
+		{ip: "100.64.0.1", public: false},
+		{ip: "0.0.0.0", public: false},
+		{ip: "::1", public: false},
+		{ip: "fd00::1", public: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.ip, func(t *testing.T) {
+			require.Equal(t, tc.public, IsPublicIP(net.ParseIP(tc.ip)))
+		})
+	}
+}
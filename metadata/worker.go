@@ -0,0 +1,81 @@
+package metadata
+
+import (
+	"context"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"log"
+	"time"
+)
+
+const (
+	DefaultInterval  = 30 * time.Second
+	DefaultBatchSize = 20
+)
+
+// Worker periodically fills in metadata for links that have not been fetched yet
+type Worker struct {
+	store     db.Store
+	fetcher   *Fetcher
+	interval  time.Duration
+	batchSize int32
+}
+
+func NewWorker(store db.Store, fetcher *Fetcher, interval time.Duration) *Worker {
+	if interval == 0 {
+		interval = DefaultInterval
+	}
+
+	return &Worker{
+		store:     store,
+		fetcher:   fetcher,
+		interval:  interval,
+		batchSize: DefaultBatchSize,
+	}
+}
+
+// Run processes pending links until ctx is cancelled
+func (worker *Worker) Run(ctx context.Context) {
+	ticker := time.NewTicker(worker.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := worker.ProcessBatch(ctx); err != nil {
+			log.Printf("metadata: cannot process batch: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessBatch fetches metadata for one batch of pending links and returns how many were handled
+func (worker *Worker) ProcessBatch(ctx context.Context) (int, error) {
+	links, err := worker.store.GetLinksPendingMetadata(ctx, worker.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, link := range links {
+		meta, err := worker.fetcher.Fetch(ctx, link.Link)
+		if err != nil {
+			// The link is still marked as fetched so a dead destination is not retried forever
+			log.Printf("metadata: cannot fetch %s for link %d: %v", link.Link, link.ID, err)
+		}
+
+		arg := db.UpdateLinkMetadataParams{
+			ID:              link.ID,
+			MetaTitle:       meta.Title,
+			MetaDescription: meta.Description,
+			MetaFavicon:     meta.Favicon,
+		}
+
+		if _, err := worker.store.UpdateLinkMetadata(ctx, arg); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(links), nil
+}
@@ -0,0 +1,98 @@
+package metadata
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWorkerProcessBatch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/broken" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, testPage)
+	}))
+	defer server.Close()
+
+	links := []db.Link{
+		{ID: 1, Link: server.URL + "/page"},
+		{ID: 2, Link: server.URL + "/broken"},
+	}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(store *mockdb.MockStore)
+		check      func(t *testing.T, n int, err error)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLinksPendingMetadata(gomock.Any(), gomock.Eq(int32(DefaultBatchSize))).
+					Times(1).
+					Return(links, nil)
+
+				store.EXPECT().
+					UpdateLinkMetadata(gomock.Any(), gomock.Eq(db.UpdateLinkMetadataParams{
+						ID:              1,
+						MetaTitle:       "Example Page",
+						MetaDescription: "open graph description",
+						MetaFavicon:     server.URL + "/static/icon.png",
+					})).
+					Times(1).
+					Return(db.Link{}, nil)
+
+				// Failed fetches are still recorded so they leave the pending queue
+				store.EXPECT().
+					UpdateLinkMetadata(gomock.Any(), gomock.Eq(db.UpdateLinkMetadataParams{ID: 2})).
+					Times(1).
+					Return(db.Link{}, nil)
+			},
+			check: func(t *testing.T, n int, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 2, n)
+			},
+		},
+		{
+			name: "ListError",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetLinksPendingMetadata(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(nil, sql.ErrConnDone)
+
+				store.EXPECT().
+					UpdateLinkMetadata(gomock.Any(), gomock.Any()).
+					Times(0)
+			},
+			check: func(t *testing.T, n int, err error) {
+				require.Error(t, err)
+				require.Zero(t, n)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			worker := NewWorker(store, newTestFetcher(), 0)
+			n, err := worker.ProcessBatch(context.Background())
+			tc.check(t, n, err)
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package qr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/skip2/go-qrcode"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultSize   = 256
+	DefaultMargin = 4
+)
+
+var (
+	ErrInvalidLevel = errors.New("qr: invalid error correction level")
+	ErrInvalidColor = errors.New("qr: invalid colour")
+)
+
+// Options controls how a QR code is rendered
+type Options struct {
+	// Size is the width and height of the output in pixels
+	Size int
+	// Level is the error-correction level of the symbol
+	Level qrcode.RecoveryLevel
+	// Margin is the width of the quiet zone in modules
+	Margin     int
+	Foreground color.Color
+	Background color.Color
+	// Logo, when set, is drawn over the centre of the code
+	Logo image.Image
+}
+
+// DefaultOptions returns the options used when a caller does not override them
+func DefaultOptions() Options {
+	return Options{
+		Size:       DefaultSize,
+		Level:      qrcode.Medium,
+		Margin:     DefaultMargin,
+		Foreground: color.Black,
+		Background: color.White,
+	}
+}
+
+// ParseLevel converts one of L, M, Q or H into a recovery level
+func ParseLevel(level string) (qrcode.RecoveryLevel, error) {
+	switch strings.ToUpper(level) {
+	case "L":
+		return qrcode.Low, nil
+	case "M":
+		return qrcode.Medium, nil
+	case "Q":
+		return qrcode.High, nil
+	case "H":
+		return qrcode.Highest, nil
+	}
+	return 0, ErrInvalidLevel
+}
+
+// ParseColor converts a hex colour such as #ff0000, ff0000 or #f00 into a colour
+func ParseColor(value string) (color.RGBA, error) {
+	value = strings.TrimPrefix(value, "#")
+	if len(value) == 3 {
+		value = string([]byte{value[0], value[0], value[1], value[1], value[2], value[2]})
+	}
+	if len(value) != 6 {
+		return color.RGBA{}, ErrInvalidColor
+	}
+
+	rgb, err := strconv.ParseUint(value, 16, 32)
+	if err != nil {
+		return color.RGBA{}, ErrInvalidColor
+	}
+
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xff}, nil
+}
+
+// matrix encodes content and surrounds the symbol with a quiet zone of margin modules
+func matrix(content string, opts Options) ([][]bool, error) {
+	code, err := qrcode.New(content, opts.Level)
+	if err != nil {
+		return nil, fmt.Errorf("qr: cannot encode content: %w", err)
+	}
+	code.DisableBorder = true
+
+	symbol := code.Bitmap()
+	n := len(symbol) + 2*opts.Margin
+
+	modules := make([][]bool, n)
+	for y := range modules {
+		modules[y] = make([]bool, n)
+	}
+	for y, row := range symbol {
+		copy(modules[y+opts.Margin][opts.Margin:], row)
+	}
+
+	return modules, nil
+}
+
+// PNG renders content as a PNG image
+func PNG(content string, opts Options) ([]byte, error) {
+	modules, err := matrix(content, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(modules)
+	scale := opts.Size / n
+	if scale < 1 {
+		scale = 1
+	}
+	size := opts.Size
+	if size < n*scale {
+		size = n * scale
+	}
+	offset := (size - n*scale) / 2
+
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), image.NewUniform(opts.Background), image.Point{}, draw.Src)
+
+	fg := image.NewUniform(opts.Foreground)
+	for y, row := range modules {
+		for x, set := range row {
+			if !set {
+				continue
+			}
+			rect := image.Rect(offset+x*scale, offset+y*scale, offset+(x+1)*scale, offset+(y+1)*scale)
+			draw.Draw(img, rect, fg, image.Point{}, draw.Src)
+		}
+	}
+
+	if opts.Logo != nil {
+		drawLogo(img, opts.Logo, opts.Background, size/5)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("qr: cannot encode png: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// drawLogo paints logo, scaled to fit a square of side pixels, over the centre of img
+func drawLogo(img *image.RGBA, logo image.Image, background color.Color, side int) {
+	if side < 1 {
+		return
+	}
+
+	center := img.Bounds().Dx() / 2
+	padding := side / 10
+	box := image.Rect(center-side/2-padding, center-side/2-padding, center+side/2+padding, center+side/2+padding)
+	draw.Draw(img, box, image.NewUniform(background), image.Point{}, draw.Src)
+
+	scaled := scale(logo, side)
+	origin := image.Pt(center-scaled.Bounds().Dx()/2, center-scaled.Bounds().Dy()/2)
+	draw.Draw(img, scaled.Bounds().Add(origin), scaled, image.Point{}, draw.Over)
+}
+
+// scale resizes src with nearest-neighbour sampling so its longest side is side pixels
+func scale(src image.Image, side int) image.Image {
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w == 0 || h == 0 {
+		return image.NewRGBA(image.Rect(0, 0, 0, 0))
+	}
+
+	dw, dh := side, side
+	if w > h {
+		dh = side * h / w
+	} else {
+		dw = side * w / h
+	}
+
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for y := 0; y < dh; y++ {
+		for x := 0; x < dw; x++ {
+			dst.Set(x, y, src.At(bounds.Min.X+x*w/dw, bounds.Min.Y+y*h/dh))
+		}
+	}
+	return dst
+}
+
+// SVG renders content as an SVG document
+func SVG(content string, opts Options) ([]byte, error) {
+	modules, err := matrix(content, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(modules)
+
+	var buf bytes.Buffer
+	buf.WriteString(`<?xml version="1.0" encoding="UTF-8"?>` + "\n")
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`, opts.Size, opts.Size, n, n)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="%s"/>`, n, n, hex(opts.Background))
+
+	buf.WriteString(`<path fill="` + hex(opts.Foreground) + `" d="`)
+	for y, row := range modules {
+		for x, set := range row {
+			if set {
+				fmt.Fprintf(&buf, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	buf.WriteString(`"/>`)
+
+	if opts.Logo != nil {
+		var logo bytes.Buffer
+		if err := png.Encode(&logo, opts.Logo); err != nil {
+			return nil, fmt.Errorf("qr: cannot encode logo: %w", err)
+		}
+
+		side := float64(n) / 5
+		padding := side / 10
+		start := (float64(n) - side) / 2
+		fmt.Fprintf(&buf, `<rect x="%g" y="%g" width="%g" height="%g" fill="%s"/>`,
+			start-padding, start-padding, side+2*padding, side+2*padding, hex(opts.Background))
+		fmt.Fprintf(&buf, `<image x="%g" y="%g" width="%g" height="%g" href="data:image/png;base64,%s"/>`,
+			start, start, side, side, base64.StdEncoding.EncodeToString(logo.Bytes()))
+	}
+
+	buf.WriteString("</svg>\n")
+	return buf.Bytes(), nil
+}
+
+func hex(c color.Color) string {
+	r, g, b, _ := c.RGBA()
+	return fmt.Sprintf("#%02x%02x%02x", r>>8, g>>8, b>>8)
+}
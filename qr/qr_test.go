@@ -0,0 +1,113 @@
+package qr
+
+import (
+	"bytes"
+	"github.com/skip2/go-qrcode"
+	"github.com/stretchr/testify/require"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	testCases := []struct {
+		level    string
+		expected qrcode.RecoveryLevel
+		err      error
+	}{
+		{level: "L", expected: qrcode.Low},
+		{level: "m", expected: qrcode.Medium},
+		{level: "Q", expected: qrcode.High},
+		{level: "H", expected: qrcode.Highest},
+		{level: "X", err: ErrInvalidLevel},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.level, func(t *testing.T) {
+			level, err := ParseLevel(tc.level)
+			require.Equal(t, tc.err, err)
+			require.Equal(t, tc.expected, level)
+		})
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	testCases := []struct {
+		value    string
+		expected color.RGBA
+		err      error
+	}{
+		{value: "#ff0000", expected: color.RGBA{R: 0xff, A: 0xff}},
+		{value: "00ff00", expected: color.RGBA{G: 0xff, A: 0xff}},
+		{value: "#00f", expected: color.RGBA{B: 0xff, A: 0xff}},
+		{value: "#zzzzzz", err: ErrInvalidColor},
+		{value: "#ff00", err: ErrInvalidColor},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.value, func(t *testing.T) {
+			c, err := ParseColor(tc.value)
+			require.Equal(t, tc.err, err)
+			require.Equal(t, tc.expected, c)
+		})
+	}
+}
+
+func TestPNG(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Size = 300
+	opts.Foreground = color.RGBA{R: 0xff, A: 0xff}
+
+	data, err := PNG("https://mini.url/abcdef", opts)
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	require.Equal(t, 300, img.Bounds().Dx())
+	require.Equal(t, 300, img.Bounds().Dy())
+
+	r, g, b, _ := img.At(0, 0).RGBA()
+	require.Equal(t, []uint32{0xffff, 0xffff, 0xffff}, []uint32{r, g, b})
+}
+
+func TestPNGWithLogo(t *testing.T) {
+	logo := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	for y := 0; y < 10; y++ {
+		for x := 0; x < 10; x++ {
+			logo.Set(x, y, color.RGBA{B: 0xff, A: 0xff})
+		}
+	}
+
+	opts := DefaultOptions()
+	opts.Level = qrcode.Highest
+	opts.Logo = logo
+
+	data, err := PNG("https://mini.url/abcdef", opts)
+	require.NoError(t, err)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+
+	center := opts.Size / 2
+	r, g, b, _ := img.At(center, center).RGBA()
+	require.Equal(t, []uint32{0, 0, 0xffff}, []uint32{r, g, b})
+}
+
+func TestSVG(t *testing.T) {
+	opts := DefaultOptions()
+	opts.Margin = 0
+	opts.Background = color.RGBA{R: 0x12, G: 0x34, B: 0x56, A: 0xff}
+
+	data, err := SVG("https://mini.url/abcdef", opts)
+	require.NoError(t, err)
+
+	svg := string(data)
+	require.True(t, strings.HasPrefix(svg, "<?xml"))
+	require.Contains(t, svg, `width="256"`)
+	require.Contains(t, svg, `fill="#123456"`)
+	// Margin 0 means the finder pattern starts at the origin
+	require.Contains(t, svg, "M0 0h1v1h-1z")
+	require.NotContains(t, svg, "<image")
+}
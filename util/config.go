@@ -6,11 +6,42 @@ import (
 )
 
 type Config struct {
-	DBSource             string        `mapstructure:"DB_SOURCE"`
-	HTTPServerAddress    string        `mapstructure:"HTTP_SERVER_ADDRESS"`
-	TokenSymmetricKey    string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
-	AccessTokenDuration  time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
-	RefreshTokenDuration time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	DBSource              string        `mapstructure:"DB_SOURCE"`
+	HTTPServerAddress     string        `mapstructure:"HTTP_SERVER_ADDRESS"`
+	TokenSymmetricKey     string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
+	AccessTokenDuration   time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
+	RefreshTokenDuration  time.Duration `mapstructure:"REFRESH_TOKEN_DURATION"`
+	BaseURL               string        `mapstructure:"BASE_URL"`
+	QRLogoPath            string        `mapstructure:"QR_LOGO_PATH"`
+	MetadataFetchInterval time.Duration `mapstructure:"METADATA_FETCH_INTERVAL"`
+	HealthCheckInterval   time.Duration `mapstructure:"HEALTH_CHECK_INTERVAL"`
+	WebhookInterval       time.Duration `mapstructure:"WEBHOOK_INTERVAL"`
+	InterstitialPolicy    string        `mapstructure:"INTERSTITIAL_POLICY"`
+	InterstitialCountdown time.Duration `mapstructure:"INTERSTITIAL_COUNTDOWN"`
+	RootRedirectURL       string        `mapstructure:"ROOT_REDIRECT_URL"`
+	NotFoundURL           string        `mapstructure:"NOT_FOUND_URL"`
+	NotFoundPagePath      string        `mapstructure:"NOT_FOUND_PAGE_PATH"`
+	// PixelAllowedHosts are the hosts custom script and image pixels may be loaded from, comma separated
+	PixelAllowedHosts []string `mapstructure:"PIXEL_ALLOWED_HOSTS"`
+	// TrustedProxies are the addresses or CIDRs of the reverse proxies whose X-Forwarded-For names the client,
+	// comma separated. Without them every request counts as coming from the address that connected
+	TrustedProxies []string `mapstructure:"TRUSTED_PROXIES"`
+	// Mailer is "smtp" to send emails, while "file" appends them to MailFilePath and "log" prints them, both only
+	// for development. It falls back to "log" when unset, and must be set when email verification is required
+	Mailer       string `mapstructure:"MAILER"`
+	MailFrom     string `mapstructure:"MAIL_FROM"`
+	MailFilePath string `mapstructure:"MAIL_FILE_PATH"`
+	SMTPHost     string `mapstructure:"SMTP_HOST"`
+	SMTPPort     int    `mapstructure:"SMTP_PORT"`
+	SMTPUsername string `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword string `mapstructure:"SMTP_PASSWORD"`
+	// EmailVerificationURL is the page the verification email links to, with the token appended as ?token=
+	EmailVerificationURL           string        `mapstructure:"EMAIL_VERIFICATION_URL"`
+	EmailVerificationTokenDuration time.Duration `mapstructure:"EMAIL_VERIFICATION_TOKEN_DURATION"`
+	EmailVerificationPolicy        string        `mapstructure:"EMAIL_VERIFICATION_POLICY"`
+	// PasswordResetURL is the page the reset email links to, with the token appended as ?token=
+	PasswordResetURL           string        `mapstructure:"PASSWORD_RESET_URL"`
+	PasswordResetTokenDuration time.Duration `mapstructure:"PASSWORD_RESET_TOKEN_DURATION"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
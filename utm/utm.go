@@ -0,0 +1,109 @@
+package utm
+
+import (
+	"errors"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+const (
+	KeySource   = "utm_source"
+	KeyMedium   = "utm_medium"
+	KeyCampaign = "utm_campaign"
+	KeyTerm     = "utm_term"
+	KeyContent  = "utm_content"
+)
+
+var ErrInvalidURL = errors.New("utm: destination is not an absolute url")
+
+// Params are the tracking parameters added to a destination; empty values are left out
+type Params struct {
+	Source   string
+	Medium   string
+	Campaign string
+	Term     string
+	Content  string
+	// Custom holds any other parameters, such as a partner or ad id
+	Custom map[string]string
+}
+
+// pairs lists the parameters to set, the standard ones first and the custom ones sorted by key
+func (params Params) pairs() [][2]string {
+	pairs := make([][2]string, 0, 5+len(params.Custom))
+
+	for _, pair := range [][2]string{
+		{KeySource, params.Source},
+		{KeyMedium, params.Medium},
+		{KeyCampaign, params.Campaign},
+		{KeyTerm, params.Term},
+		{KeyContent, params.Content},
+	} {
+		if pair[1] != "" {
+			pairs = append(pairs, pair)
+		}
+	}
+
+	keys := make([]string, 0, len(params.Custom))
+	for key, value := range params.Custom {
+		if key != "" && value != "" {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		pairs = append(pairs, [2]string{key, params.Custom[key]})
+	}
+
+	return pairs
+}
+
+// Apply sets params on destination's query string. Parameters already there keep their place and encoding
+// unless params replaces them; the fragment is kept.
+func Apply(destination string, params Params) (string, error) {
+	u, err := url.Parse(destination)
+	if err != nil || !u.IsAbs() || u.Host == "" {
+		return "", ErrInvalidURL
+	}
+
+	pairs := params.pairs()
+	if len(pairs) == 0 {
+		return destination, nil
+	}
+
+	replaced := make(map[string]bool, len(pairs))
+	for _, pair := range pairs {
+		replaced[pair[0]] = true
+	}
+
+	var query []string
+	for _, part := range strings.Split(u.RawQuery, "&") {
+		if part == "" {
+			continue
+		}
+		rawKey, _, _ := strings.Cut(part, "=")
+		if key, err := url.QueryUnescape(rawKey); err == nil && replaced[key] {
+			continue
+		}
+		query = append(query, part)
+	}
+
+	for _, pair := range pairs {
+		query = append(query, url.QueryEscape(pair[0])+"="+url.QueryEscape(pair[1]))
+	}
+
+	u.RawQuery = strings.Join(query, "&")
+	u.ForceQuery = false
+
+	return u.String(), nil
+}
+
+// Campaign reads the utm_campaign a destination carries, empty when it has none
+func Campaign(destination string) string {
+	u, err := url.Parse(destination)
+	if err != nil {
+		return ""
+	}
+	return u.Query().Get(KeyCampaign)
+}
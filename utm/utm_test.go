@@ -0,0 +1,62 @@
+package utm
+
+import (
+	"github.com/stretchr/testify/require"
+	"testing"
+)
+
+func TestApply(t *testing.T) {
+	testCases := []struct {
+		name        string
+		destination string
+		params      Params
+		expected    string
+	}{
+		{
+			name:        "NoQuery",
+			destination: "https://example.com/pricing",
+			params:      Params{Source: "newsletter", Medium: "email", Campaign: "spring sale"},
+			expected:    "https://example.com/pricing?utm_source=newsletter&utm_medium=email&utm_campaign=spring+sale",
+		},
+		{
+			name:        "KeepsExistingQueryAndFragment",
+			destination: "https://example.com/p?id=7&ref=a%2Fb#reviews",
+			params:      Params{Source: "x", Custom: map[string]string{"partner": "acme", "ad": "12"}},
+			expected:    "https://example.com/p?id=7&ref=a%2Fb&utm_source=x&ad=12&partner=acme#reviews",
+		},
+		{
+			name:        "ReplacesExistingParams",
+			destination: "https://example.com/?utm_source=old&page=2&utm_%63ampaign=old",
+			params:      Params{Source: "new", Campaign: "launch"},
+			expected:    "https://example.com/?page=2&utm_source=new&utm_campaign=launch",
+		},
+		{
+			name:        "EmptyParams",
+			destination: "https://example.com/?a=1",
+			params:      Params{Custom: map[string]string{"empty": ""}},
+			expected:    "https://example.com/?a=1",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			result, err := Apply(tc.destination, tc.params)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestApplyInvalidURL(t *testing.T) {
+	for _, destination := range []string{"example.com/path", "/relative", "http://%zz"} {
+		_, err := Apply(destination, Params{Source: "x"})
+		require.ErrorIs(t, err, ErrInvalidURL)
+	}
+}
+
+func TestCampaign(t *testing.T) {
+	require.Equal(t, "spring sale", Campaign("https://example.com/?utm_campaign=spring%20sale"))
+	require.Equal(t, "launch", Campaign("https://example.com/?utm_campaign=launch&x=1"))
+	require.Empty(t, Campaign("https://example.com/"))
+	require.Empty(t, Campaign("http://%zz"))
+}
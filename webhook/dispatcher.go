@@ -0,0 +1,274 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/bolusarz/urlmini/metadata"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultInterval    = 10 * time.Second
+	DefaultBatchSize   = 50
+	DefaultTimeout     = 10 * time.Second
+	DefaultMaxAttempts = 8
+	DefaultBaseDelay   = 30 * time.Second
+	DefaultMaxDelay    = 6 * time.Hour
+
+	StatusPending   = "pending"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+
+	maxErrorLength = 300
+	// maxFlushRounds bounds how many batches one webhook can get per run, so a flood of clicks cannot starve deliveries
+	maxFlushRounds = 10
+)
+
+var ErrUnexpectedStatus = errors.New("webhook: receiver answered with a non 2xx status")
+
+type Options struct {
+	Timeout     time.Duration
+	MaxAttempts int32
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// AllowPrivateNetworks is handed to metadata.NewClient
+	AllowPrivateNetworks bool
+}
+
+// Dispatcher sends queued deliveries, retrying failures with exponential backoff,
+// and queues the expiry events and click batches it finds along the way
+type Dispatcher struct {
+	store       db.Store
+	client      *http.Client
+	interval    time.Duration
+	batchSize   int32
+	maxAttempts int32
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+}
+
+func NewDispatcher(store db.Store, interval time.Duration, opts Options) *Dispatcher {
+	if interval == 0 {
+		interval = DefaultInterval
+	}
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxAttempts == 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+	if opts.BaseDelay == 0 {
+		opts.BaseDelay = DefaultBaseDelay
+	}
+	if opts.MaxDelay == 0 {
+		opts.MaxDelay = DefaultMaxDelay
+	}
+
+	client := metadata.NewClient(metadata.ClientOptions{
+		Timeout: opts.Timeout,
+		// Receivers must answer at the url they registered
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+		AllowPrivateNetworks: opts.AllowPrivateNetworks,
+	})
+
+	return &Dispatcher{
+		store:       store,
+		client:      client,
+		interval:    interval,
+		batchSize:   DefaultBatchSize,
+		maxAttempts: opts.MaxAttempts,
+		baseDelay:   opts.BaseDelay,
+		maxDelay:    opts.MaxDelay,
+	}
+}
+
+// Run queues expiry events and click batches and sends due deliveries until ctx is cancelled
+func (dispatcher *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(dispatcher.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := dispatcher.NotifyExpired(ctx); err != nil {
+			log.Printf("webhook: cannot queue expiry events: %v", err)
+		}
+
+		if _, err := dispatcher.FlushClicks(ctx); err != nil {
+			log.Printf("webhook: cannot batch clicks: %v", err)
+		}
+
+		if _, err := dispatcher.ProcessBatch(ctx); err != nil {
+			log.Printf("webhook: cannot process batch: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// NotifyExpired queues a link.expired event for every link that expired since the last run
+func (dispatcher *Dispatcher) NotifyExpired(ctx context.Context) (int, error) {
+	links, err := dispatcher.store.GetExpiredLinksToNotify(ctx, dispatcher.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, link := range links {
+		deliveries, err := NewDeliveries(link.UserID, EventLinkExpired, NewLinkData(link))
+		if err != nil {
+			return 0, err
+		}
+
+		err = dispatcher.store.NotifyLinkExpiredTx(ctx, db.NotifyLinkExpiredTxParams{
+			LinkID:     link.ID,
+			Deliveries: deliveries,
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(links), nil
+}
+
+// FlushClicks turns queued clicks into deliveries for every webhook whose batch is full or has waited long enough,
+// returning how many deliveries were created
+func (dispatcher *Dispatcher) FlushClicks(ctx context.Context) (int, error) {
+	created := 0
+
+	for round := 0; round < maxFlushRounds; round++ {
+		hooks, err := dispatcher.store.GetWebhooksDueClickBatch(ctx, dispatcher.batchSize)
+		if err != nil {
+			return created, err
+		}
+		if len(hooks) == 0 {
+			break
+		}
+
+		for _, hook := range hooks {
+			// Moving the clicks and creating the delivery is one statement, so a crash here loses nothing
+			_, err := dispatcher.store.CreateClickBatchDelivery(ctx, db.CreateClickBatchDeliveryParams{
+				WebhookID: hook.ID,
+				Limit:     hook.BatchSize,
+			})
+			if err != nil {
+				// Another dispatcher took the batch first
+				if errors.Is(err, db.ErrRecordNotFound) {
+					continue
+				}
+				return created, err
+			}
+			created++
+		}
+	}
+
+	return created, nil
+}
+
+// ProcessBatch claims due deliveries and attempts each once, returning how many were attempted
+func (dispatcher *Dispatcher) ProcessBatch(ctx context.Context) (int, error) {
+	// The lease keeps other dispatchers off these rows; one that crashes mid-batch is retried when it runs out
+	deliveries, err := dispatcher.store.ClaimWebhookDeliveries(ctx, db.ClaimWebhookDeliveriesParams{
+		LeaseUntil: time.Now().Add(2 * dispatcher.client.Timeout),
+		Limit:      dispatcher.batchSize,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	hooks := make(map[int64]db.Webhook)
+
+	for _, delivery := range deliveries {
+		hook, ok := hooks[delivery.WebhookID]
+		if !ok {
+			hook, err = dispatcher.store.GetWebhook(ctx, delivery.WebhookID)
+			if err != nil {
+				return 0, err
+			}
+			hooks[hook.ID] = hook
+		}
+
+		if _, err := dispatcher.Deliver(ctx, hook, delivery); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(deliveries), nil
+}
+
+// Deliver posts one delivery to its webhook and records the attempt, scheduling a retry when it fails
+func (dispatcher *Dispatcher) Deliver(ctx context.Context, hook db.Webhook, delivery db.WebhookDelivery) (db.WebhookDelivery, error) {
+	status, sendErr := dispatcher.send(ctx, hook, delivery)
+
+	arg := db.RecordWebhookAttemptParams{
+		ID:             delivery.ID,
+		Status:         StatusSucceeded,
+		ResponseStatus: int32(status),
+		NextAttemptAt:  delivery.NextAttemptAt,
+	}
+
+	if sendErr != nil {
+		arg.Error = truncate(sendErr.Error(), maxErrorLength)
+		arg.Status = StatusPending
+		arg.NextAttemptAt = time.Now().Add(dispatcher.Backoff(delivery.Attempts + 1))
+
+		if delivery.Attempts+1 >= dispatcher.maxAttempts {
+			arg.Status = StatusFailed
+		}
+	}
+
+	return dispatcher.store.RecordWebhookAttempt(ctx, arg)
+}
+
+// Backoff is the wait before the next attempt once attempts have failed: doubling from the base delay up to the max
+func (dispatcher *Dispatcher) Backoff(attempts int32) time.Duration {
+	delay := dispatcher.baseDelay
+	for i := int32(1); i < attempts && delay < dispatcher.maxDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, dispatcher.maxDelay)
+}
+
+func (dispatcher *Dispatcher) send(ctx context.Context, hook db.Webhook, delivery db.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.Url, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "urlmini-webhook/1.0")
+	req.Header.Set(EventHeader, delivery.Event)
+	req.Header.Set(DeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(SignatureHeader, Sign(hook.Secret, time.Now(), delivery.Payload))
+
+	resp, err := dispatcher.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	io.CopyN(io.Discard, resp.Body, 4096)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("%w: %d", ErrUnexpectedStatus, resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func truncate(value string, length int) string {
+	runes := []rune(value)
+	if len(runes) > length {
+		return string(runes[:length])
+	}
+	return value
+}
@@ -0,0 +1,247 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestDispatcher(store db.Store) *Dispatcher {
+	return NewDispatcher(store, 0, Options{
+		AllowPrivateNetworks: true,
+		Timeout:              time.Second,
+		MaxAttempts:          3,
+		BaseDelay:            time.Minute,
+		MaxDelay:             time.Hour,
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	dispatcher := newTestDispatcher(nil)
+
+	require.Equal(t, time.Minute, dispatcher.Backoff(1))
+	require.Equal(t, 2*time.Minute, dispatcher.Backoff(2))
+	require.Equal(t, 4*time.Minute, dispatcher.Backoff(3))
+	require.Equal(t, time.Hour, dispatcher.Backoff(20))
+}
+
+func TestDeliver(t *testing.T) {
+	var received *http.Request
+	var receivedBody []byte
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		receivedBody, _ = io.ReadAll(r.Body)
+
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	payload := []byte(`{"event":"link.expired","data":{"id":1}}`)
+
+	testCases := []struct {
+		name       string
+		path       string
+		attempts   int32
+		buildStubs func(store *mockdb.MockStore)
+	}{
+		{
+			name: "OK",
+			path: "/ok",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RecordWebhookAttempt(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.RecordWebhookAttemptParams) (db.WebhookDelivery, error) {
+						require.Equal(t, StatusSucceeded, arg.Status)
+						require.Equal(t, int32(http.StatusNoContent), arg.ResponseStatus)
+						require.Empty(t, arg.Error)
+						return db.WebhookDelivery{}, nil
+					})
+			},
+		},
+		{
+			name:     "Retry",
+			path:     "/fail",
+			attempts: 1,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RecordWebhookAttempt(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.RecordWebhookAttemptParams) (db.WebhookDelivery, error) {
+						require.Equal(t, StatusPending, arg.Status)
+						require.Equal(t, int32(http.StatusInternalServerError), arg.ResponseStatus)
+						require.NotEmpty(t, arg.Error)
+						require.WithinDuration(t, time.Now().Add(2*time.Minute), arg.NextAttemptAt, 5*time.Second)
+						return db.WebhookDelivery{}, nil
+					})
+			},
+		},
+		{
+			name:     "GiveUp",
+			path:     "/fail",
+			attempts: 2,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					RecordWebhookAttempt(gomock.Any(), gomock.Any()).
+					Times(1).
+					DoAndReturn(func(_ any, arg db.RecordWebhookAttemptParams) (db.WebhookDelivery, error) {
+						require.Equal(t, StatusFailed, arg.Status)
+						return db.WebhookDelivery{}, nil
+					})
+			},
+		},
+	}
+
+	for i := range testCases {
+		tc := testCases[i]
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			hook := db.Webhook{ID: 1, Url: server.URL + tc.path, Secret: "secret"}
+			delivery := db.WebhookDelivery{ID: 42, WebhookID: 1, Event: EventLinkExpired, Payload: payload, Attempts: tc.attempts}
+
+			_, err := newTestDispatcher(store).Deliver(context.Background(), hook, delivery)
+			require.NoError(t, err)
+
+			require.Equal(t, payload, receivedBody)
+			require.Equal(t, EventLinkExpired, received.Header.Get(EventHeader))
+			require.Equal(t, "42", received.Header.Get(DeliveryHeader))
+			require.NoError(t, Verify("secret", received.Header.Get(SignatureHeader), receivedBody, time.Minute))
+		})
+	}
+}
+
+func TestProcessBatch(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	deliveries := []db.WebhookDelivery{
+		{ID: 1, WebhookID: 5, Payload: []byte(`{}`)},
+		{ID: 2, WebhookID: 5, Payload: []byte(`{}`)},
+	}
+
+	store.EXPECT().
+		ClaimWebhookDeliveries(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ any, arg db.ClaimWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+			require.Equal(t, int32(DefaultBatchSize), arg.Limit)
+			require.True(t, arg.LeaseUntil.After(time.Now()))
+			return deliveries, nil
+		})
+
+	// Deliveries to the same webhook share one lookup
+	store.EXPECT().
+		GetWebhook(gomock.Any(), gomock.Eq(int64(5))).
+		Times(1).
+		Return(db.Webhook{ID: 5, Url: server.URL, Secret: "secret"}, nil)
+
+	store.EXPECT().
+		RecordWebhookAttempt(gomock.Any(), gomock.Any()).
+		Times(2).
+		Return(db.WebhookDelivery{}, nil)
+
+	n, err := newTestDispatcher(store).ProcessBatch(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+	require.Equal(t, 2, hits)
+}
+
+func TestNotifyExpired(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	links := []db.Link{{ID: 1, UserID: 3}, {ID: 2, UserID: 4}}
+
+	store.EXPECT().
+		GetExpiredLinksToNotify(gomock.Any(), gomock.Eq(int32(DefaultBatchSize))).
+		Times(1).
+		Return(links, nil)
+
+	store.EXPECT().
+		NotifyLinkExpiredTx(gomock.Any(), gomock.Any()).
+		Times(2).
+		DoAndReturn(func(_ any, arg db.NotifyLinkExpiredTxParams) error {
+			require.Equal(t, EventLinkExpired, arg.Deliveries.Event)
+			if arg.LinkID == 2 {
+				require.Equal(t, int64(4), arg.Deliveries.UserID)
+				return sql.ErrConnDone
+			}
+			require.Equal(t, int64(1), arg.LinkID)
+			require.Equal(t, int64(3), arg.Deliveries.UserID)
+			return nil
+		})
+
+	n, err := newTestDispatcher(store).NotifyExpired(context.Background())
+	require.ErrorIs(t, err, sql.ErrConnDone)
+	require.Zero(t, n)
+}
+
+func TestFlushClicks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+
+	hooks := []db.Webhook{{ID: 1, BatchSize: 100}, {ID: 2, BatchSize: 10}}
+
+	gomock.InOrder(
+		store.EXPECT().
+			GetWebhooksDueClickBatch(gomock.Any(), gomock.Eq(int32(DefaultBatchSize))).
+			Return(hooks, nil),
+		store.EXPECT().
+			GetWebhooksDueClickBatch(gomock.Any(), gomock.Any()).
+			Return(hooks[1:], nil),
+		store.EXPECT().
+			GetWebhooksDueClickBatch(gomock.Any(), gomock.Any()).
+			Return([]db.Webhook{}, nil),
+	)
+
+	store.EXPECT().
+		CreateClickBatchDelivery(gomock.Any(), gomock.Eq(db.CreateClickBatchDeliveryParams{WebhookID: 1, Limit: 100})).
+		Times(1).
+		Return(db.WebhookDelivery{}, nil)
+
+	store.EXPECT().
+		CreateClickBatchDelivery(gomock.Any(), gomock.Eq(db.CreateClickBatchDeliveryParams{WebhookID: 2, Limit: 10})).
+		Times(1).
+		Return(db.WebhookDelivery{}, nil)
+
+	// The second webhook's next batch was taken by another dispatcher
+	store.EXPECT().
+		CreateClickBatchDelivery(gomock.Any(), gomock.Eq(db.CreateClickBatchDeliveryParams{WebhookID: 2, Limit: 10})).
+		Times(1).
+		Return(db.WebhookDelivery{}, db.ErrRecordNotFound)
+
+	n, err := newTestDispatcher(store).FlushClicks(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, n)
+}
@@ -0,0 +1,169 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	EventLinkExpired    = "link.expired"
+	EventLinkClickLimit = "link.click_limit_reached"
+	EventLinkUnhealthy  = "link.unhealthy"
+	// EventLinkClicked is queued by the RecordClick query itself and delivered in batches
+	EventLinkClicked = "link.clicked"
+
+	DefaultClickBatchSize    = 100
+	DefaultClickBatchMaxWait = time.Minute
+
+	SignatureHeader           = "X-Urlmini-Signature"
+	EventHeader               = "X-Urlmini-Event"
+	DeliveryHeader            = "X-Urlmini-Delivery"
+	signatureVersion          = "v1"
+	defaultSignatureTolerance = 5 * time.Minute
+)
+
+// Events lists every event a webhook can subscribe to
+var Events = []string{EventLinkExpired, EventLinkClickLimit, EventLinkUnhealthy, EventLinkClicked}
+
+// Payload is the body posted to subscribers. Click batches carry a ClickBatch as data.
+type Payload struct {
+	Event      string          `json:"event"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// LinkData describes the link an event is about
+type LinkData struct {
+	ID           int64  `json:"id"`
+	Code         string `json:"code"`
+	Link         string `json:"link"`
+	ClickCount   int64  `json:"click_count"`
+	HealthStatus int32  `json:"health_status,omitempty"`
+	HealthError  string `json:"health_error,omitempty"`
+}
+
+func NewLinkData(link db.Link) LinkData {
+	return LinkData{
+		ID:           link.ID,
+		Code:         link.Code,
+		Link:         link.Link,
+		ClickCount:   link.ClickCount,
+		HealthStatus: link.HealthStatus,
+		HealthError:  link.HealthError,
+	}
+}
+
+// ClickBatch is the data of a link.clicked delivery, oldest click first
+type ClickBatch struct {
+	Clicks []ClickData `json:"clicks"`
+}
+
+type ClickData struct {
+	ID        int64  `json:"id"`
+	LinkID    int64  `json:"link_id"`
+	Code      string `json:"code"`
+	Referrer  string `json:"referrer"`
+	UserAgent string `json:"user_agent"`
+	// BioPageID is set when the click came through one of the user's bio pages
+	BioPageID *int64    `json:"bio_page_id,omitempty"`
+	ClickedAt time.Time `json:"clicked_at"`
+}
+
+// Emit queues a delivery of event for every active webhook of the user subscribed to it
+func Emit(ctx context.Context, store db.Store, userID int64, event string, data interface{}) error {
+	arg, err := NewDeliveries(userID, event, data)
+	if err != nil {
+		return err
+	}
+
+	_, err = store.CreateWebhookDeliveries(ctx, arg)
+	return err
+}
+
+// NewDeliveries builds what Emit queues, for callers that queue it inside their own transaction
+func NewDeliveries(userID int64, event string, data interface{}) (db.CreateWebhookDeliveriesParams, error) {
+	encoded, err := json.Marshal(data)
+	if err != nil {
+		return db.CreateWebhookDeliveriesParams{}, err
+	}
+
+	payload, err := json.Marshal(Payload{
+		Event:      event,
+		OccurredAt: time.Now().UTC(),
+		Data:       encoded,
+	})
+	if err != nil {
+		return db.CreateWebhookDeliveriesParams{}, err
+	}
+
+	return db.CreateWebhookDeliveriesParams{
+		UserID:  userID,
+		Event:   event,
+		Payload: payload,
+	}, nil
+}
+
+// NewSecret generates the key a webhook's deliveries are signed with
+func NewSecret() (string, error) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(key), nil
+}
+
+// Sign computes the signature header for a body sent at timestamp.
+// The timestamp is part of the signed content so a captured request cannot be replayed later.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	ts := strconv.FormatInt(timestamp.Unix(), 10)
+	return "t=" + ts + "," + signatureVersion + "=" + hex.EncodeToString(mac(secret, ts, body))
+}
+
+// Verify checks a signature header the way a receiver should, rejecting ones older than tolerance
+func Verify(secret, header string, body []byte, tolerance time.Duration) error {
+	if tolerance == 0 {
+		tolerance = defaultSignatureTolerance
+	}
+
+	var ts, signature string
+	for _, part := range strings.Split(header, ",") {
+		key, value, _ := strings.Cut(part, "=")
+		switch key {
+		case "t":
+			ts = value
+		case signatureVersion:
+			signature = value
+		}
+	}
+
+	seconds, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return fmt.Errorf("webhook: invalid signature timestamp")
+	}
+	if age := time.Since(time.Unix(seconds, 0)); age > tolerance || age < -tolerance {
+		return fmt.Errorf("webhook: signature timestamp outside tolerance")
+	}
+
+	expected, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(expected, mac(secret, ts, body)) {
+		return fmt.Errorf("webhook: signature mismatch")
+	}
+	return nil
+}
+
+func mac(secret, ts string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(ts))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	mockdb "github.com/bolusarz/urlmini/db/mock"
+	db "github.com/bolusarz/urlmini/db/sqlc"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"event":"link.expired"}`)
+	now := time.Now()
+
+	header := Sign("secret", now, body)
+	require.NoError(t, Verify("secret", header, body, time.Minute))
+
+	require.Error(t, Verify("other", header, body, time.Minute))
+	require.Error(t, Verify("secret", header, []byte(`{}`), time.Minute))
+	require.Error(t, Verify("secret", Sign("secret", now.Add(-time.Hour), body), body, time.Minute))
+	require.Error(t, Verify("secret", "garbage", body, time.Minute))
+}
+
+func TestNewSecret(t *testing.T) {
+	a, err := NewSecret()
+	require.NoError(t, err)
+	b, err := NewSecret()
+	require.NoError(t, err)
+
+	require.NotEqual(t, a, b)
+	require.Len(t, a, len("whsec_")+64)
+}
+
+func TestEmit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	link := db.Link{ID: 3, UserID: 9, Code: "abc123", Link: "https://example.com", ClickCount: 10}
+
+	store.EXPECT().
+		CreateWebhookDeliveries(gomock.Any(), gomock.Any()).
+		Times(1).
+		DoAndReturn(func(_ any, arg db.CreateWebhookDeliveriesParams) ([]db.WebhookDelivery, error) {
+			require.Equal(t, int64(9), arg.UserID)
+			require.Equal(t, EventLinkClickLimit, arg.Event)
+
+			var payload struct {
+				Payload
+				Data LinkData `json:"data"`
+			}
+			require.NoError(t, json.Unmarshal(arg.Payload, &payload))
+			require.Equal(t, EventLinkClickLimit, payload.Event)
+			require.WithinDuration(t, time.Now(), payload.OccurredAt, time.Minute)
+			require.Equal(t, NewLinkData(link), payload.Data)
+			return nil, nil
+		})
+
+	require.NoError(t, Emit(context.Background(), store, link.UserID, EventLinkClickLimit, NewLinkData(link)))
+
+	store.EXPECT().
+		CreateWebhookDeliveries(gomock.Any(), gomock.Any()).
+		Times(1).
+		Return(nil, sql.ErrConnDone)
+
+	require.ErrorIs(t, Emit(context.Background(), store, link.UserID, EventLinkExpired, nil), sql.ErrConnDone)
+}